@@ -2,6 +2,8 @@ package cli
 
 import (
 	"context"
+	"encoding/hex"
+	"fmt"
 	"os"
 	"os/signal"
 	"sync"
@@ -13,6 +15,7 @@ import (
 	"github.com/chains-lab/city-petitions-svc/internal/config"
 	"github.com/chains-lab/city-petitions-svc/internal/dbx"
 	"github.com/chains-lab/city-petitions-svc/internal/logger"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
@@ -34,6 +37,15 @@ func Run(args []string) bool {
 		migrateUpCmd   = migrateCmd.Command("up", "migrate db up")
 		migrateDownCmd = migrateCmd.Command("down", "migrate db down")
 
+		retentionCmd       = service.Command("retention", "signature retention command")
+		retentionRunCmd    = retentionCmd.Command("run", "pseudonymize signers of petitions past their retention period once")
+		retentionRunDryRun = retentionRunCmd.Flag("dry-run", "only report what would be pseudonymized").Bool()
+
+		chainCmd            = service.Command("chain", "signature hash chain command")
+		chainVerifyCmd      = chainCmd.Command("verify", "re-verify the signature hash chain of a petition")
+		chainVerifyPetition = chainVerifyCmd.Arg("petition-id", "petition ID").Required().String()
+		chainBackfillCmd    = chainCmd.Command("backfill", "append signatures given before chaining started to their petitions' chains")
+
 		//docs = service.Command("docs", "documentation command")
 		//
 		//generateDocs = docs.Command("generate", "generate API documentation")
@@ -64,6 +76,12 @@ func Run(args []string) bool {
 		err = dbx.MigrateUp(cfg)
 	case migrateDownCmd.FullCommand():
 		err = dbx.MigrateDown(cfg)
+	case retentionRunCmd.FullCommand():
+		_, err = application.Retention.RunOnce(ctx, log, *retentionRunDryRun)
+	case chainVerifyCmd.FullCommand():
+		err = verifyChain(ctx, log, application, *chainVerifyPetition)
+	case chainBackfillCmd.FullCommand():
+		err = backfillChain(ctx, log, application)
 	default:
 		log.Errorf("unknown command %s", cmd)
 		return false
@@ -89,3 +107,45 @@ func Run(args []string) bool {
 
 	return true
 }
+
+// verifyChain re-verifies the signature chain of a petition and fails if it is broken.
+func verifyChain(ctx context.Context, log *logrus.Logger, application app.App, petitionID string) error {
+	id, err := uuid.Parse(petitionID)
+	if err != nil {
+		return fmt.Errorf("invalid petition id %q: %w", petitionID, err)
+	}
+
+	res, err := application.VerifySignatureChain(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	entry := log.WithFields(logrus.Fields{
+		"petition_id": res.PetitionID,
+		"entries":     res.Entries,
+		"head_hash":   hex.EncodeToString(res.HeadHash),
+		"signatures":  res.Signatures,
+		"unchained":   res.Unchained,
+	})
+	if !res.Valid {
+		entry.Errorf("signature chain is broken at entry %d: %s", res.BrokenAt, res.Problem)
+
+		return fmt.Errorf("signature chain of petition %s is broken", id)
+	}
+
+	entry.Info("signature chain is intact")
+
+	return nil
+}
+
+// backfillChain chains every signature missing from its petition's chain.
+func backfillChain(ctx context.Context, log *logrus.Logger, application app.App) error {
+	res, err := application.BackfillSignatureChain(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("signature chain backfill appended %d signatures to the chains of %d petitions", res.Signatures, res.Petitions)
+
+	return nil
+}
@@ -2,18 +2,52 @@ package api
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/chains-lab/city-petitions-svc/internal/api/grpc"
+	"github.com/chains-lab/city-petitions-svc/internal/api/rest"
 	"github.com/chains-lab/city-petitions-svc/internal/app"
 	"github.com/chains-lab/city-petitions-svc/internal/config"
+	"github.com/chains-lab/city-petitions-svc/internal/metrics"
+	"github.com/chains-lab/city-petitions-svc/internal/tracing"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 )
 
+const tracingShutdownTimeout = 5 * time.Second
+
 func Start(ctx context.Context, cfg config.Config, log *logrus.Logger, app *app.App) error {
+	shutdownTracing, err := tracing.Setup(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %w", err)
+	}
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			log.WithError(err).Warn("failed to flush traces")
+		}
+	}()
+
 	eg, ctx := errgroup.WithContext(ctx)
 
 	eg.Go(func() error { return grpc.Run(ctx, cfg, log, app) })
+	eg.Go(func() error { return app.PetitionUpdates.Run(ctx, log) })
+	eg.Go(func() error { return app.Idempotency.Run(ctx, log) })
+
+	if cfg.Metrics.Enabled {
+		eg.Go(func() error { return metrics.Run(ctx, cfg, log) })
+	}
+
+	if cfg.Petitions.Retention.Enabled {
+		eg.Go(func() error { return app.Retention.Run(ctx, log) })
+	}
+
+	if cfg.Gateway.Enabled {
+		eg.Go(func() error { return rest.Run(ctx, cfg, log) })
+	}
 
 	return eg.Wait()
 }
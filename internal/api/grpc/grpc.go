@@ -12,28 +12,79 @@ import (
 	"github.com/chains-lab/city-petitions-svc/internal/app"
 	"github.com/chains-lab/city-petitions-svc/internal/config"
 	"github.com/chains-lab/city-petitions-svc/internal/logger"
+	"github.com/chains-lab/city-petitions-svc/internal/ratelimit"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 func Run(ctx context.Context, cfg config.Config, log logger.Logger, app *app.App) error {
 	log.Info("gRPC server is starting...")
 
+	limiter, err := ratelimit.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create rate limiter: %w", err)
+	}
+
+	tracingInt := interceptors.Tracing()
+	metricsInt := interceptors.Metrics()
 	logInt := logger.UnaryLogInterceptor(log)
-	requestId := interceptors.RequestID()
-	userAuth := interceptors.UserJwtAuth(cfg.JWT.User.AccessToken.SecretKey)
-	serviceAuth := interceptors.ServiceJwtAuth(cfg.JWT.Service.SecretKey)
+	requestId := interceptors.RequestID(methodPolicies)
+	userAuth := interceptors.UserJwtAuth(cfg.JWT.User.AccessToken.SecretKey, methodPolicies)
+	serviceAuth := interceptors.ServiceJwtAuth(cfg.JWT.Service.SecretKey, methodPolicies)
+	rateLimit := interceptors.RateLimit(limiter)
+	authorize := interceptors.Authorize(app.RBAC, methodPolicies)
+	idempotent := interceptors.Idempotency(app.Idempotency, methodPolicies)
 
-	grpcServer := grpc.NewServer(
+	streamTracingInt := interceptors.StreamTracing()
+	streamMetricsInt := interceptors.StreamMetrics()
+	streamLogInt := logger.StreamLogInterceptor(log)
+	streamRequestId := interceptors.StreamRequestID(methodPolicies)
+	streamUserAuth := interceptors.StreamUserJwtAuth(cfg.JWT.User.AccessToken.SecretKey, methodPolicies)
+	streamServiceAuth := interceptors.StreamServiceJwtAuth(cfg.JWT.Service.SecretKey, methodPolicies)
+	streamRateLimit := interceptors.StreamRateLimit(limiter)
+	streamAuthorize := interceptors.StreamAuthorize(app.RBAC, methodPolicies)
+
+	opts := append(serverOptions(cfg),
 		grpc.ChainUnaryInterceptor(
+			tracingInt,
+			metricsInt,
 			logInt,
 			requestId,
 			serviceAuth,
 			userAuth,
+			rateLimit,
+			authorize,
+			idempotent,
+		),
+		grpc.ChainStreamInterceptor(
+			streamTracingInt,
+			streamMetricsInt,
+			streamLogInt,
+			streamRequestId,
+			streamServiceAuth,
+			streamUserAuth,
+			streamRateLimit,
+			streamAuthorize,
 		),
 	)
 
+	grpcServer := grpc.NewServer(opts...)
+
 	petionProto.RegisterPetitionServiceServer(grpcServer, petition.NewService(cfg, app))
 
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	if cfg.Server.Reflection {
+		reflection.Register(grpcServer)
+	}
+
+	if err := methodPolicies.Validate(grpcServer.GetServiceInfo()); err != nil {
+		return fmt.Errorf("invalid access policies: %w", err)
+	}
+
 	lis, err := net.Listen("tcp", cfg.Server.Port)
 	if err != nil {
 		return fmt.Errorf("failed to listen: %w", err)
@@ -45,9 +96,12 @@ func Run(ctx context.Context, cfg config.Config, log logger.Logger, app *app.App
 		serveErrCh <- grpcServer.Serve(lis)
 	}()
 
+	go watchReadiness(ctx, cfg, log, app, healthServer)
+
 	select {
 	case <-ctx.Done():
 		log.Info("shutting down gRPC server …")
+		healthServer.Shutdown()
 		grpcServer.GracefulStop()
 		return nil
 	case err := <-serveErrCh:
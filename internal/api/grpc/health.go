@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	petionProto "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/app"
+	"github.com/chains-lab/city-petitions-svc/internal/config"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	defaultHealthInterval = 10 * time.Second
+	defaultHealthTimeout  = 3 * time.Second
+)
+
+// watchReadiness keeps the health status of the server and of PetitionService
+// in sync with app readiness until ctx is done.
+func watchReadiness(ctx context.Context, cfg config.Config, log logger.Logger, app *app.App, srv *health.Server) {
+	interval := cfg.Server.Health.Interval
+	if interval <= 0 {
+		interval = defaultHealthInterval
+	}
+	timeout := cfg.Server.Health.Timeout
+	if timeout <= 0 {
+		timeout = defaultHealthTimeout
+	}
+
+	services := []string{"", petionProto.PetitionService_ServiceDesc.ServiceName}
+	last := healthpb.HealthCheckResponse_UNKNOWN
+
+	check := func() {
+		checkCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		st := healthpb.HealthCheckResponse_SERVING
+		if err := app.Ready(checkCtx); err != nil {
+			st = healthpb.HealthCheckResponse_NOT_SERVING
+			if last != st {
+				log.WithError(err).Warn("service is not ready")
+			}
+		} else if last != st {
+			log.Info("service is ready")
+		}
+
+		last = st
+		for _, name := range services {
+			srv.SetServingStatus(name, st)
+		}
+	}
+
+	check()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			check()
+		}
+	}
+}
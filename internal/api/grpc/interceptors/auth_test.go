@@ -0,0 +1,177 @@
+package interceptors
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/rbac"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testUserSecret    = "user-secret"
+	testServiceSecret = "service-secret"
+)
+
+var testPolicies = Policies{
+	"/test.Service/Public":     {Access: AccessPublic},
+	"/test.Service/User":       {Access: AccessUser},
+	"/test.Service/Service":    {Access: AccessService},
+	"/test.Service/Infra":      {Access: AccessInfra},
+	"/test.Service/Permission": {Access: AccessUser, Permission: rbac.PermissionPetitionAdmin},
+	"/test.Service/Role":       {Access: AccessRole, Roles: []string{"admin"}},
+}
+
+// forgedToken is a well-formed HS256 token signed with a key the server does not know.
+func forgedToken(t *testing.T) string {
+	t.Helper()
+
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." +
+		enc.EncodeToString([]byte(`{"sub":"`+uuid.NewString()+`","aud":["city-petitions-svc"],"role":"super_user"}`))
+
+	mac := hmac.New(sha256.New, []byte("not-the-secret"))
+	mac.Write([]byte(unsigned))
+
+	return unsigned + "." + enc.EncodeToString(mac.Sum(nil))
+}
+
+// authChain runs the auth interceptors in the order Run installs them and reports whether the
+// handler was reached.
+func authChain(ctx context.Context, fullMethod string, access permissionChecker) (bool, error) {
+	info := &grpc.UnaryServerInfo{FullMethod: fullMethod}
+	reached := false
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		reached = true
+		return nil, nil
+	}
+
+	chain := []grpc.UnaryServerInterceptor{
+		ServiceJwtAuth(testServiceSecret, testPolicies),
+		UserJwtAuth(testUserSecret, testPolicies),
+		Authorize(access, testPolicies),
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		next, interceptor := handler, chain[i]
+		handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+			return interceptor(ctx, req, info, next)
+		}
+	}
+
+	_, err := handler(ctx, nil)
+
+	return reached, err
+}
+
+type allowAll bool
+
+func (a allowAll) CanAnywhere(rbac.Subject, rbac.Permission) bool { return bool(a) }
+
+func TestAuthRejectsMissingAndWrongTokens(t *testing.T) {
+	forged := forgedToken(t)
+
+	tests := []struct {
+		name   string
+		method string
+		md     metadata.MD // nil means no metadata at all
+		want   codes.Code
+	}{
+		{"public without tokens", "/test.Service/Public", nil, codes.OK},
+		{"public with wrong user token", "/test.Service/Public", metadata.Pairs("x-user-token", forged), codes.Unauthenticated},
+
+		{"user without metadata", "/test.Service/User", nil, codes.Unauthenticated},
+		{"user without service token", "/test.Service/User", metadata.Pairs("x-user-token", forged), codes.Unauthenticated},
+		{"user with wrong service token", "/test.Service/User", metadata.Pairs("x-service-token", forged, "x-user-token", forged), codes.Unauthenticated},
+		{"user with malformed service token", "/test.Service/User", metadata.Pairs("x-service-token", "garbage"), codes.Unauthenticated},
+
+		{"service without metadata", "/test.Service/Service", nil, codes.Unauthenticated},
+		{"service without token", "/test.Service/Service", metadata.Pairs("x-user-token", forged), codes.Unauthenticated},
+		{"service with wrong token", "/test.Service/Service", metadata.Pairs("x-service-token", forged), codes.Unauthenticated},
+
+		{"permission without metadata", "/test.Service/Permission", nil, codes.Unauthenticated},
+		{"permission with wrong service token", "/test.Service/Permission", metadata.Pairs("x-service-token", forged), codes.Unauthenticated},
+
+		{"role without metadata", "/test.Service/Role", nil, codes.Unauthenticated},
+		{"role without user token", "/test.Service/Role", metadata.Pairs("x-request-id", uuid.NewString()), codes.Unauthenticated},
+		{"role with wrong service token", "/test.Service/Role", metadata.Pairs("x-service-token", forged, "x-user-token", forged), codes.Unauthenticated},
+
+		{"infra without tokens", "/test.Service/Infra", nil, codes.OK},
+
+		{"method without policy", "/test.Service/Unknown", nil, codes.PermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+
+			reached, err := authChain(ctx, tt.method, allowAll(true))
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("code = %s, want %s (err: %v)", got, tt.want, err)
+			}
+			if reached != (tt.want == codes.OK) {
+				t.Errorf("handler reached = %t, want %t", reached, tt.want == codes.OK)
+			}
+		})
+	}
+}
+
+func TestUserTokenRequiredByUserAccess(t *testing.T) {
+	for _, method := range []string{"/test.Service/User", "/test.Service/Permission", "/test.Service/Role"} {
+		t.Run(method, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", uuid.NewString()))
+
+			_, err := userJwtAuth(ctx, method, testUserSecret, testPolicies)
+			if got := status.Code(err); got != codes.Unauthenticated {
+				t.Errorf("code = %s, want %s", got, codes.Unauthenticated)
+			}
+
+			ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-token", forgedToken(t)))
+			_, err = userJwtAuth(ctx, method, testUserSecret, testPolicies)
+			if got := status.Code(err); got != codes.Unauthenticated {
+				t.Errorf("wrong token: code = %s, want %s", got, codes.Unauthenticated)
+			}
+		})
+	}
+}
+
+func TestAuthorizePermission(t *testing.T) {
+	user := meta.UserData{ID: uuid.New(), Role: "user"}
+
+	tests := []struct {
+		name   string
+		method string
+		user   *meta.UserData
+		access allowAll
+		want   codes.Code
+	}{
+		{"no user", "/test.Service/Permission", nil, true, codes.Unauthenticated},
+		{"user without permission", "/test.Service/Permission", &user, false, codes.PermissionDenied},
+		{"user with permission", "/test.Service/Permission", &user, true, codes.OK},
+		{"method without permission", "/test.Service/User", &user, false, codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.user != nil {
+				ctx = context.WithValue(ctx, meta.UserCtxKey, *tt.user)
+			}
+
+			err := authorize(ctx, tt.method, tt.access, testPolicies)
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("code = %s, want %s (err: %v)", got, tt.want, err)
+			}
+		})
+	}
+}
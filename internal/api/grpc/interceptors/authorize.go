@@ -0,0 +1,71 @@
+package interceptors
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/problems"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+	"github.com/chains-lab/city-petitions-svc/internal/rbac"
+	"google.golang.org/grpc"
+)
+
+type permissionChecker interface {
+	CanAnywhere(subject rbac.Subject, perm rbac.Permission) bool
+}
+
+// Authorize rejects users that do not hold the permission of the method in any city.
+// City-scoped checks happen in the entities once the target city is known.
+// It must run after UserJwtAuth.
+func Authorize(access permissionChecker, policies Policies) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if err := authorize(ctx, info.FullMethod, access, policies); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func StreamAuthorize(access permissionChecker, policies Policies) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if err := authorize(ss.Context(), info.FullMethod, access, policies); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+func authorize(ctx context.Context, fullMethod string, access permissionChecker, policies Policies) error {
+	policy, ok := policies.Lookup(fullMethod)
+	if !ok || policy.Permission == "" {
+		return nil
+	}
+
+	user := meta.User(ctx)
+	if user == nil {
+		logger.Log(ctx).Errorf("no user in context for method %s", fullMethod)
+
+		return problems.UnauthenticatedError(ctx, "user token not supplied")
+	}
+
+	if !access.CanAnywhere(rbac.Subject{UserID: user.ID, Role: user.Role}, policy.Permission) {
+		logger.Log(ctx).Errorf("user %s with role %s has no permission %s", user.ID, user.Role, policy.Permission)
+
+		return problems.PermissionDeniedError(ctx, fmt.Sprintf("permission %s is required", policy.Permission))
+	}
+
+	return nil
+}
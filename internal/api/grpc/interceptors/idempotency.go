@@ -0,0 +1,173 @@
+package interceptors
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/problems"
+	"github.com/chains-lab/city-petitions-svc/internal/idempotency"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const (
+	idempotencyKeyHeader      = "x-idempotency-key"
+	idempotencyReplayedHeader = "x-idempotency-replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+type idempotencyStore interface {
+	Begin(ctx context.Context, userID uuid.UUID, method, key string, requestHash []byte) (*idempotency.Record, error)
+	Complete(ctx context.Context, userID uuid.UUID, method, key string, record idempotency.Record) error
+	Release(ctx context.Context, userID uuid.UUID, method, key string) error
+}
+
+// Idempotency replays the stored response when a client retries a method with
+// MethodPolicy.Idempotent set under the same x-idempotency-key. It must run after UserJwtAuth;
+// keys are scoped to the calling user and method. Only successful responses are stored.
+func Idempotency(store idempotencyStore, policies Policies) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		policy, ok := policies.Lookup(info.FullMethod)
+		if !ok || !policy.Idempotent {
+			return handler(ctx, req)
+		}
+
+		key := idempotencyKey(ctx)
+		if key == "" {
+			return handler(ctx, req)
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return nil, problems.InvalidArgumentError(ctx, fmt.Sprintf("%s must be at most %d characters", idempotencyKeyHeader, maxIdempotencyKeyLength))
+		}
+
+		user := meta.User(ctx)
+		if user == nil {
+			return handler(ctx, req)
+		}
+
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		requestHash, err := hashRequest(msg)
+		if err != nil {
+			logger.Log(ctx).WithError(err).Errorf("failed to hash request of %s", info.FullMethod)
+
+			return nil, problems.InternalError(ctx)
+		}
+
+		record, err := store.Begin(ctx, user.ID, info.FullMethod, key, requestHash)
+		switch {
+		case errors.Is(err, idempotency.ErrMismatch):
+			return nil, problems.AlreadyExistsError(ctx, fmt.Sprintf("%s was already used with a different request", idempotencyKeyHeader))
+		case errors.Is(err, idempotency.ErrInProgress):
+			return nil, problems.AbortedError(ctx, fmt.Sprintf("request with this %s is still in progress", idempotencyKeyHeader))
+		case err != nil:
+			logger.Log(ctx).WithError(err).Errorf("failed to claim idempotency key for %s", info.FullMethod)
+
+			return nil, problems.InternalError(ctx)
+		}
+
+		if record != nil {
+			resp, err := replayResponse(*record)
+			if err != nil {
+				logger.Log(ctx).WithError(err).Errorf("failed to replay stored response of %s", info.FullMethod)
+
+				return nil, problems.InternalError(ctx)
+			}
+
+			if err := grpc.SetHeader(ctx, metadata.Pairs(idempotencyReplayedHeader, "true")); err != nil {
+				logger.Log(ctx).WithError(err).Warn("failed to set idempotency header")
+			}
+
+			return resp, nil
+		}
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			// Release is not bound to the request context, so a cancelled call still frees its key.
+			if relErr := store.Release(context.WithoutCancel(ctx), user.ID, info.FullMethod, key); relErr != nil {
+				logger.Log(ctx).WithError(relErr).Errorf("failed to release idempotency key for %s", info.FullMethod)
+			}
+
+			return nil, err
+		}
+
+		if err := storeResponse(context.WithoutCancel(ctx), store, user.ID, info.FullMethod, key, resp); err != nil {
+			logger.Log(ctx).WithError(err).Errorf("failed to store response of %s", info.FullMethod)
+		}
+
+		return resp, nil
+	}
+}
+
+func idempotencyKey(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get(idempotencyKeyHeader)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+func hashRequest(msg proto.Message) ([]byte, error) {
+	raw, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(raw)
+
+	return sum[:], nil
+}
+
+func storeResponse(ctx context.Context, store idempotencyStore, userID uuid.UUID, method, key string, resp interface{}) error {
+	msg, ok := resp.(proto.Message)
+	if !ok {
+		return store.Release(ctx, userID, method, key)
+	}
+
+	raw, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	return store.Complete(ctx, userID, method, key, idempotency.Record{
+		ResponseType: string(proto.MessageName(msg)),
+		Response:     raw,
+	})
+}
+
+func replayResponse(record idempotency.Record) (proto.Message, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(record.ResponseType))
+	if err != nil {
+		return nil, err
+	}
+
+	msg := mt.New().Interface()
+	if err := proto.Unmarshal(record.Response, msg); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
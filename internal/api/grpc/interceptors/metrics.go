@@ -0,0 +1,44 @@
+package interceptors
+
+import (
+	"context"
+	"time"
+
+	"github.com/chains-lab/city-petitions-svc/internal/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Metrics records latency and status code of every call.
+// It runs right after Tracing, so rejected calls are counted too.
+func Metrics() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+		metrics.ObserveRPC(info.FullMethod, status.Code(err).String(), start)
+
+		return resp, err
+	}
+}
+
+func StreamMetrics() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		start := time.Now()
+
+		err := handler(srv, ss)
+		metrics.ObserveRPC(info.FullMethod, status.Code(err).String(), start)
+
+		return err
+	}
+}
@@ -0,0 +1,112 @@
+package interceptors
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/chains-lab/city-petitions-svc/internal/rbac"
+	"google.golang.org/grpc"
+)
+
+type Access int
+
+const (
+	// AccessPublic needs no tokens; a user token is still parsed when supplied.
+	AccessPublic Access = iota
+	// AccessUser needs a service token and a user token.
+	AccessUser
+	// AccessService needs only a service token.
+	AccessService
+	// AccessRole needs a service token and a user token with one of MethodPolicy.Roles.
+	AccessRole
+	// AccessInfra is for health checks and reflection: no tokens and no request ID.
+	AccessInfra
+)
+
+func (a Access) String() string {
+	switch a {
+	case AccessPublic:
+		return "public"
+	case AccessUser:
+		return "user"
+	case AccessService:
+		return "service"
+	case AccessRole:
+		return "role"
+	case AccessInfra:
+		return "infra"
+	default:
+		return fmt.Sprintf("access(%d)", int(a))
+	}
+}
+
+type MethodPolicy struct {
+	Access Access
+	Roles  []string
+	// Permission, when set, is checked by Authorize for the calling user.
+	Permission rbac.Permission
+	// Idempotent methods accept an x-idempotency-key and replay the stored response to retries.
+	Idempotent bool
+}
+
+func (p MethodPolicy) RequiresServiceToken() bool {
+	return p.Access != AccessPublic && p.Access != AccessInfra
+}
+
+func (p MethodPolicy) RequiresRequestID() bool {
+	return p.Access != AccessInfra
+}
+
+func (p MethodPolicy) RequiresUserToken() bool {
+	return p.Access == AccessUser || p.Access == AccessRole
+}
+
+func (p MethodPolicy) AllowsRole(role string) bool {
+	if p.Access != AccessRole {
+		return true
+	}
+
+	return slices.Contains(p.Roles, role)
+}
+
+// Policies maps info.FullMethod to the access policy of the method.
+type Policies map[string]MethodPolicy
+
+func (p Policies) Lookup(fullMethod string) (MethodPolicy, bool) {
+	policy, ok := p[fullMethod]
+	return policy, ok
+}
+
+// Validate checks that every method registered on the server has a policy,
+// so a new RPC can not be exposed without deciding who may call it.
+func (p Policies) Validate(services map[string]grpc.ServiceInfo) error {
+	var missing []string
+	for name, info := range services {
+		for _, method := range info.Methods {
+			fullMethod := fmt.Sprintf("/%s/%s", name, method.Name)
+			if _, ok := p[fullMethod]; !ok {
+				missing = append(missing, fullMethod)
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("no access policy for methods: %v", missing)
+	}
+
+	for fullMethod, policy := range p {
+		if policy.Access == AccessRole && len(policy.Roles) == 0 {
+			return fmt.Errorf("method %s requires a role but no roles are listed", fullMethod)
+		}
+		if policy.Permission != "" && !policy.RequiresUserToken() {
+			return fmt.Errorf("method %s requires permission %s but does not require a user", fullMethod, policy.Permission)
+		}
+		if policy.Idempotent && !policy.RequiresUserToken() {
+			return fmt.Errorf("method %s is idempotent but does not require a user", fullMethod)
+		}
+	}
+
+	return nil
+}
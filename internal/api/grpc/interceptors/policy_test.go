@@ -0,0 +1,64 @@
+package interceptors
+
+import (
+	"testing"
+
+	"github.com/chains-lab/city-petitions-svc/internal/rbac"
+	"google.golang.org/grpc"
+)
+
+func TestAllowsRole(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy MethodPolicy
+		role   string
+		want   bool
+	}{
+		{"listed role", MethodPolicy{Access: AccessRole, Roles: []string{"admin", "super_user"}}, "super_user", true},
+		{"unlisted role", MethodPolicy{Access: AccessRole, Roles: []string{"admin"}}, "moderator", false},
+		{"empty role", MethodPolicy{Access: AccessRole, Roles: []string{"admin"}}, "", false},
+		{"user access allows every role", MethodPolicy{Access: AccessUser}, "user", true},
+		{"roles ignored without role access", MethodPolicy{Access: AccessUser, Roles: []string{"admin"}}, "user", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.AllowsRole(tt.role); got != tt.want {
+				t.Errorf("AllowsRole(%q) = %t, want %t", tt.role, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePolicies(t *testing.T) {
+	services := map[string]grpc.ServiceInfo{
+		"test.Service": {Methods: []grpc.MethodInfo{{Name: "Call"}}},
+	}
+
+	tests := []struct {
+		name   string
+		policy MethodPolicy
+		valid  bool
+	}{
+		{"public", MethodPolicy{Access: AccessPublic}, true},
+		{"role with roles", MethodPolicy{Access: AccessRole, Roles: []string{"admin"}}, true},
+		{"role without roles", MethodPolicy{Access: AccessRole}, false},
+		{"role with permission", MethodPolicy{Access: AccessRole, Roles: []string{"admin"}, Permission: rbac.PermissionPolicyManage}, true},
+		{"idempotent role", MethodPolicy{Access: AccessRole, Roles: []string{"admin"}, Idempotent: true}, true},
+		{"permission without user", MethodPolicy{Access: AccessService, Permission: rbac.PermissionPetitionAdmin}, false},
+		{"idempotent without user", MethodPolicy{Access: AccessPublic, Idempotent: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Policies{"/test.Service/Call": tt.policy}.Validate(services)
+			if (err == nil) != tt.valid {
+				t.Errorf("Validate = %v, want valid %t", err, tt.valid)
+			}
+		})
+	}
+
+	if err := (Policies{}).Validate(services); err == nil {
+		t.Error("Validate accepted a method without policy")
+	}
+}
@@ -0,0 +1,71 @@
+package interceptors
+
+import (
+	"context"
+	"time"
+
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/problems"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+)
+
+type rateLimiter interface {
+	Allow(ctx context.Context, fullMethod string, userID uuid.UUID) (bool, time.Duration, error)
+}
+
+// RateLimit enforces per-user budgets of each method. It must run after UserJwtAuth;
+// calls without an authenticated user are not limited.
+// When the limiter store fails the call is let through, so an outage of the store does not take the service down.
+func RateLimit(limiter rateLimiter) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if err := rateLimit(ctx, info.FullMethod, limiter); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func StreamRateLimit(limiter rateLimiter) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if err := rateLimit(ss.Context(), info.FullMethod, limiter); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+func rateLimit(ctx context.Context, fullMethod string, limiter rateLimiter) error {
+	user := meta.User(ctx)
+	if user == nil {
+		return nil
+	}
+
+	allowed, retryAfter, err := limiter.Allow(ctx, fullMethod, user.ID)
+	if err != nil {
+		logger.Log(ctx).WithError(err).Warnf("rate limiter unavailable for method %s", fullMethod)
+
+		return nil
+	}
+
+	if !allowed {
+		logger.Log(ctx).Warnf("user %s exceeded rate limit of %s", user.ID, fullMethod)
+
+		return problems.ResourceExhaustedError(ctx, "too many requests, retry later", retryAfter)
+	}
+
+	return nil
+}
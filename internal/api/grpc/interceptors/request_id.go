@@ -11,30 +11,61 @@ import (
 	"google.golang.org/grpc/metadata"
 )
 
-func RequestID() grpc.UnaryServerInterceptor {
+func RequestID(policies Policies) grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
 		req interface{},
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			return nil, problems.UnauthenticatedError(ctx, fmt.Sprintf("no metadata found in incoming context"))
+		if policy, ok := policies.Lookup(info.FullMethod); ok && !policy.RequiresRequestID() {
+			return handler(ctx, req)
 		}
 
-		requestIDArr := md["x-request-id"]
-		if len(requestIDArr) == 0 {
-			return nil, problems.UnauthenticatedError(ctx, fmt.Sprintf("request ID not supplied"))
+		ctx, err := requestID(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func StreamRequestID(policies Policies) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if policy, ok := policies.Lookup(info.FullMethod); ok && !policy.RequiresRequestID() {
+			return handler(srv, ss)
 		}
 
-		requestID, err := uuid.Parse(requestIDArr[0])
+		ctx, err := requestID(ss.Context())
 		if err != nil {
-			return nil, problems.UnauthenticatedError(ctx, fmt.Sprintf("invalid request ID: %v", err))
+			return err
 		}
 
-		ctx = context.WithValue(ctx, meta.RequestIDCtxKey, requestID)
+		return handler(srv, withContext(ss, ctx))
+	}
+}
 
-		return handler(ctx, req)
+func requestID(ctx context.Context) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, problems.UnauthenticatedError(ctx, fmt.Sprintf("no metadata found in incoming context"))
+	}
+
+	requestIDArr := md["x-request-id"]
+	if len(requestIDArr) == 0 {
+		return nil, problems.UnauthenticatedError(ctx, fmt.Sprintf("request ID not supplied"))
+	}
+
+	requestID, err := uuid.Parse(requestIDArr[0])
+	if err != nil {
+		return nil, problems.UnauthenticatedError(ctx, fmt.Sprintf("invalid request ID: %v", err))
 	}
+
+	return context.WithValue(ctx, meta.RequestIDCtxKey, requestID), nil
 }
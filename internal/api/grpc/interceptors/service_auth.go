@@ -14,54 +14,83 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-func ServiceJwtAuth(skService string) grpc.UnaryServerInterceptor {
+func ServiceJwtAuth(skService string, policies Policies) grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
 		req interface{},
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
-		switch info.FullMethod {
-		case "add methods here if need":
-			return handler(ctx, req)
+		if err := serviceJwtAuth(ctx, info.FullMethod, skService, policies); err != nil {
+			return nil, err
 		}
 
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			logger.Log(ctx).Errorf("no metadata found in incoming context")
+		return handler(ctx, req)
+	}
+}
 
-			return nil, problems.UnauthenticatedError(ctx, "no metadata found in incoming context")
+func StreamServiceJwtAuth(skService string, policies Policies) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if err := serviceJwtAuth(ss.Context(), info.FullMethod, skService, policies); err != nil {
+			return err
 		}
 
-		token := md["x-service-token"]
-		if len(token) == 0 {
-			logger.Log(ctx).Errorf("service token not supplied")
+		return handler(srv, ss)
+	}
+}
 
-			return nil, problems.UnauthenticatedError(ctx, fmt.Sprintf("service token not supplied"))
-		}
+func serviceJwtAuth(ctx context.Context, fullMethod, skService string, policies Policies) error {
+	policy, ok := policies.Lookup(fullMethod)
+	if !ok {
+		logger.Log(ctx).Errorf("no access policy for method %s", fullMethod)
 
-		data, err := auth.VerifyServiceJWT(ctx, token[0], skService)
-		if err != nil {
-			logger.Log(ctx).Errorf("failed to verify service token: %s", err)
+		return problems.PermissionDeniedError(ctx, fmt.Sprintf("method %s is not accessible", fullMethod))
+	}
 
-			return nil, problems.UnauthenticatedError(ctx, "failed to verify service token")
-		}
+	if !policy.RequiresServiceToken() {
+		return nil
+	}
 
-		ThisSvcInAudience := false
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		logger.Log(ctx).Errorf("no metadata found in incoming context")
 
-		for _, aud := range data.Audience {
-			if aud == constant.ServiceName {
-				ThisSvcInAudience = true
-				break
-			}
-		}
+		return problems.UnauthenticatedError(ctx, "no metadata found in incoming context")
+	}
+
+	token := md["x-service-token"]
+	if len(token) == 0 {
+		logger.Log(ctx).Errorf("service token not supplied")
+
+		return problems.UnauthenticatedError(ctx, fmt.Sprintf("service token not supplied"))
+	}
+
+	data, err := auth.VerifyServiceJWT(ctx, token[0], skService)
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to verify service token: %s", err)
 
-		if !ThisSvcInAudience {
-			logger.Log(ctx).Errorf("service issuer %s not in audience %v", data.Issuer, data.Audience)
+		return problems.UnauthenticatedError(ctx, "failed to verify service token")
+	}
 
-			return nil, status.New(codes.Unauthenticated, fmt.Sprintf("service issuer %s not in audience %v", data.Issuer, data.Audience)).Err()
+	ThisSvcInAudience := false
+
+	for _, aud := range data.Audience {
+		if aud == constant.ServiceName {
+			ThisSvcInAudience = true
+			break
 		}
+	}
 
-		return handler(ctx, req)
+	if !ThisSvcInAudience {
+		logger.Log(ctx).Errorf("service issuer %s not in audience %v", data.Issuer, data.Audience)
+
+		return status.New(codes.Unauthenticated, fmt.Sprintf("service issuer %s not in audience %v", data.Issuer, data.Audience)).Err()
 	}
+
+	return nil
 }
@@ -0,0 +1,27 @@
+package interceptors
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// serverStream replaces the context of a grpc.ServerStream,
+// so values set by stream interceptors reach the handler.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func withContext(ss grpc.ServerStream, ctx context.Context) grpc.ServerStream {
+	if s, ok := ss.(*serverStream); ok {
+		s.ctx = ctx
+		return s
+	}
+
+	return &serverStream{ServerStream: ss, ctx: ctx}
+}
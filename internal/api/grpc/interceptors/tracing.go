@@ -0,0 +1,84 @@
+package interceptors
+
+import (
+	"context"
+	"strings"
+
+	"github.com/chains-lab/city-petitions-svc/internal/constant"
+	"github.com/chains-lab/city-petitions-svc/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Tracing opens a server span for every call, continuing the trace of the caller if it sent one.
+// It should run first in the chain, so rejected calls are traced too.
+func Tracing() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		ctx, span := startRPCSpan(ctx, info.FullMethod)
+		defer span.End()
+
+		resp, err := handler(ctx, req)
+		endRPCSpan(span, err)
+
+		return resp, err
+	}
+}
+
+func StreamTracing() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx, span := startRPCSpan(ss.Context(), info.FullMethod)
+		defer span.End()
+
+		err := handler(srv, withContext(ss, ctx))
+		endRPCSpan(span, err)
+
+		return err
+	}
+}
+
+func startRPCSpan(ctx context.Context, fullMethod string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, tracing.MetadataCarrier(md))
+
+	name := strings.TrimPrefix(fullMethod, "/")
+	service, method, _ := strings.Cut(name, "/")
+
+	attrs := []attribute.KeyValue{
+		attribute.String("rpc.system", "grpc"),
+		attribute.String("rpc.service", service),
+		attribute.String("rpc.method", method),
+	}
+	if v := md.Get("x-request-id"); len(v) > 0 {
+		ctx = tracing.WithRequestID(ctx, v[0])
+		attrs = append(attrs, tracing.RequestIDKey.String(v[0]))
+	}
+
+	return otel.Tracer(constant.ServiceName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+func endRPCSpan(span trace.Span, err error) {
+	code := status.Code(err)
+	span.SetAttributes(attribute.Int("rpc.grpc.status_code", int(code)))
+	if err != nil {
+		span.SetStatus(codes.Error, code.String())
+		span.RecordError(err)
+	}
+}
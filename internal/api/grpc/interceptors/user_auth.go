@@ -13,48 +13,96 @@ import (
 	"google.golang.org/grpc/metadata"
 )
 
-func UserJwtAuth(skUser string) grpc.UnaryServerInterceptor {
+func UserJwtAuth(skUser string, policies Policies) grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
 		req interface{},
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			logger.Log(ctx).Errorf("no metadata found in incoming context")
-
-			return nil, problems.UnauthenticatedError(ctx, "no metadata found in incoming context")
+		ctx, err := userJwtAuth(ctx, info.FullMethod, skUser, policies)
+		if err != nil {
+			return nil, err
 		}
 
-		token := md["x-user-token"]
-		if len(token) == 0 {
-			logger.Log(ctx).Errorf("user token not supplied")
+		return handler(ctx, req)
+	}
+}
 
-			return nil, problems.UnauthenticatedError(ctx, fmt.Sprintf("user token not supplied"))
+func StreamUserJwtAuth(skUser string, policies Policies) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx, err := userJwtAuth(ss.Context(), info.FullMethod, skUser, policies)
+		if err != nil {
+			return err
 		}
 
-		userData, err := auth.VerifyUserJWT(ctx, token[0], skUser)
-		if err != nil {
-			logger.Log(ctx).Errorf("failed to verify user token: %s", err)
+		return handler(srv, withContext(ss, ctx))
+	}
+}
+
+func userJwtAuth(ctx context.Context, fullMethod, skUser string, policies Policies) (context.Context, error) {
+	policy, ok := policies.Lookup(fullMethod)
+	if !ok {
+		logger.Log(ctx).Errorf("no access policy for method %s", fullMethod)
+
+		return nil, problems.PermissionDeniedError(ctx, fmt.Sprintf("method %s is not accessible", fullMethod))
+	}
+
+	if policy.Access == AccessService || policy.Access == AccessInfra {
+		return ctx, nil
+	}
 
-			return nil, problems.UnauthenticatedError(ctx, "failed to verify user token")
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		if !policy.RequiresUserToken() {
+			return ctx, nil
 		}
 
-		userID, err := uuid.Parse(userData.Subject)
-		if err != nil {
-			logger.Log(ctx).Errorf("invalid user ID: %v", err)
+		logger.Log(ctx).Errorf("no metadata found in incoming context")
+
+		return nil, problems.UnauthenticatedError(ctx, "no metadata found in incoming context")
+	}
 
-			return nil, problems.UnauthenticatedError(ctx, fmt.Sprintf("invalid user ID: %v", err))
+	token := md["x-user-token"]
+	if len(token) == 0 {
+		if !policy.RequiresUserToken() {
+			return ctx, nil
 		}
 
-		ctx = context.WithValue(ctx, meta.UserCtxKey, meta.UserData{
-			ID:        userID,
-			SessionID: userData.Session,
-			Verified:  userData.Verified,
-			Role:      userData.Role,
-		})
+		logger.Log(ctx).Errorf("user token not supplied")
 
-		return handler(ctx, req)
+		return nil, problems.UnauthenticatedError(ctx, fmt.Sprintf("user token not supplied"))
+	}
+
+	userData, err := auth.VerifyUserJWT(ctx, token[0], skUser)
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to verify user token: %s", err)
+
+		return nil, problems.UnauthenticatedError(ctx, "failed to verify user token")
 	}
+
+	userID, err := uuid.Parse(userData.Subject)
+	if err != nil {
+		logger.Log(ctx).Errorf("invalid user ID: %v", err)
+
+		return nil, problems.UnauthenticatedError(ctx, fmt.Sprintf("invalid user ID: %v", err))
+	}
+
+	if !policy.AllowsRole(userData.Role) {
+		logger.Log(ctx).Errorf("user %s with role %s is not allowed to call %s", userID, userData.Role, fullMethod)
+
+		return nil, problems.PermissionDeniedError(ctx, fmt.Sprintf("role %s is not allowed to call this method", userData.Role))
+	}
+
+	return context.WithValue(ctx, meta.UserCtxKey, meta.UserData{
+		ID:        userID,
+		SessionID: userData.Session,
+		Verified:  userData.Verified,
+		Role:      userData.Role,
+	}), nil
 }
@@ -0,0 +1,37 @@
+package grpc
+
+import (
+	"github.com/chains-lab/city-petitions-svc/internal/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+// serverOptions builds transport options from config; zero values keep the grpc defaults.
+func serverOptions(cfg config.Config) []grpc.ServerOption {
+	c := cfg.Server.GRPC
+
+	var opts []grpc.ServerOption
+
+	if c.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(c.MaxRecvMsgSize))
+	}
+	if c.MaxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(c.MaxSendMsgSize))
+	}
+
+	opts = append(opts,
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionIdle:     c.MaxConnectionIdle,
+			MaxConnectionAge:      c.MaxConnectionAge,
+			MaxConnectionAgeGrace: c.MaxConnectionAgeGrace,
+			Time:                  c.Keepalive.Time,
+			Timeout:               c.Keepalive.Timeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             c.Keepalive.MinTime,
+			PermitWithoutStream: c.Keepalive.PermitWithoutStream,
+		}),
+	)
+
+	return opts
+}
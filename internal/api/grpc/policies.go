@@ -0,0 +1,105 @@
+package grpc
+
+import (
+	petionProto "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/interceptors"
+	"github.com/chains-lab/city-petitions-svc/internal/constant/enum"
+	"github.com/chains-lab/city-petitions-svc/internal/rbac"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionalphapb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+)
+
+// methodPolicies lists who may call every registered method.
+// Run refuses to start if a registered method is missing here.
+var methodPolicies = interceptors.Policies{
+	healthpb.Health_Check_FullMethodName: {Access: interceptors.AccessInfra},
+	healthpb.Health_Watch_FullMethodName: {Access: interceptors.AccessInfra},
+	healthpb.Health_List_FullMethodName:  {Access: interceptors.AccessInfra},
+
+	reflectionpb.ServerReflection_ServerReflectionInfo_FullMethodName:      {Access: interceptors.AccessInfra},
+	reflectionalphapb.ServerReflection_ServerReflectionInfo_FullMethodName: {Access: interceptors.AccessInfra},
+
+	petionProto.PetitionService_GetPetition_FullMethodName:          {Access: interceptors.AccessPublic},
+	petionProto.PetitionService_ListPetitions_FullMethodName:        {Access: interceptors.AccessPublic},
+	petionProto.PetitionService_ListPetitionSigners_FullMethodName:  {Access: interceptors.AccessPublic},
+	petionProto.PetitionService_ListSignatureReasons_FullMethodName: {Access: interceptors.AccessPublic},
+	petionProto.PetitionService_WatchPetition_FullMethodName:        {Access: interceptors.AccessPublic},
+	petionProto.PetitionService_ListComments_FullMethodName:         {Access: interceptors.AccessPublic},
+	// anyone holding a receipt, signer or not, may check it
+	petionProto.PetitionService_VerifySignatureReceipt_FullMethodName: {Access: interceptors.AccessPublic},
+
+	// data subject requests are made by the privacy back office on behalf of the user
+	petionProto.PetitionService_ExportUserData_FullMethodName: {Access: interceptors.AccessService},
+	petionProto.PetitionService_EraseUserData_FullMethodName:  {Access: interceptors.AccessService},
+
+	petionProto.PetitionService_CreatePetition_FullMethodName:    {Access: interceptors.AccessUser, Idempotent: true},
+	petionProto.PetitionService_SignPetition_FullMethodName:      {Access: interceptors.AccessUser, Idempotent: true},
+	petionProto.PetitionService_GetSignatureStats_FullMethodName: {Access: interceptors.AccessUser},
+	petionProto.PetitionService_CheckDuplicates_FullMethodName:   {Access: interceptors.AccessUser},
+
+	petionProto.PetitionService_UpdatePetition_FullMethodName:            {Access: interceptors.AccessUser},
+	petionProto.PetitionService_PublishPetition_FullMethodName:           {Access: interceptors.AccessUser, Idempotent: true},
+	petionProto.PetitionService_InviteCoAuthor_FullMethodName:            {Access: interceptors.AccessUser},
+	petionProto.PetitionService_AcceptCoAuthorInvitation_FullMethodName:  {Access: interceptors.AccessUser},
+	petionProto.PetitionService_DeclineCoAuthorInvitation_FullMethodName: {Access: interceptors.AccessUser},
+	petionProto.PetitionService_RemoveCoAuthor_FullMethodName:            {Access: interceptors.AccessUser},
+
+	petionProto.PetitionService_CreateComment_FullMethodName: {Access: interceptors.AccessUser, Idempotent: true},
+	petionProto.PetitionService_EditComment_FullMethodName:   {Access: interceptors.AccessUser},
+	petionProto.PetitionService_DeleteComment_FullMethodName: {Access: interceptors.AccessUser},
+	petionProto.PetitionService_ReportComment_FullMethodName: {Access: interceptors.AccessUser},
+
+	petionProto.PetitionService_ApprovePetition_FullMethodName: {
+		Access:     interceptors.AccessUser,
+		Permission: rbac.PermissionPetitionAnswer,
+		Idempotent: true,
+	},
+	petionProto.PetitionService_RejectPetition_FullMethodName: {
+		Access:     interceptors.AccessUser,
+		Permission: rbac.PermissionPetitionAnswer,
+		Idempotent: true,
+	},
+
+	petionProto.PetitionService_ListSignatureFlags_FullMethodName: {
+		Access:     interceptors.AccessUser,
+		Permission: rbac.PermissionPetitionModerate,
+	},
+	petionProto.PetitionService_DismissSignatureFlags_FullMethodName: {
+		Access:     interceptors.AccessUser,
+		Permission: rbac.PermissionPetitionModerate,
+	},
+	petionProto.PetitionService_InvalidateSignatures_FullMethodName: {
+		Access:     interceptors.AccessUser,
+		Permission: rbac.PermissionPetitionModerate,
+	},
+	petionProto.PetitionService_ModerateComment_FullMethodName: {
+		Access:     interceptors.AccessUser,
+		Permission: rbac.PermissionPetitionModerate,
+	},
+	petionProto.PetitionService_MergePetitions_FullMethodName: {
+		Access:     interceptors.AccessUser,
+		Permission: rbac.PermissionPetitionAdmin,
+		Idempotent: true,
+	},
+
+	petionProto.PetitionService_ListContentFlags_FullMethodName: {
+		Access:     interceptors.AccessUser,
+		Permission: rbac.PermissionPetitionModerate,
+	},
+	petionProto.PetitionService_DismissContentFlags_FullMethodName: {
+		Access:     interceptors.AccessUser,
+		Permission: rbac.PermissionPetitionModerate,
+	},
+	petionProto.PetitionService_ConfirmContentFlags_FullMethodName: {
+		Access:     interceptors.AccessUser,
+		Permission: rbac.PermissionPetitionModerate,
+	},
+
+	// the access policy is shown to staff only, and only to those holding policy.manage
+	petionProto.PetitionService_GetAccessPolicy_FullMethodName: {
+		Access:     interceptors.AccessRole,
+		Roles:      []string{enum.UserRoleAdmin, enum.UserRoleSuperUser},
+		Permission: rbac.PermissionPolicyManage,
+	},
+}
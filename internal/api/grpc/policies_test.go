@@ -0,0 +1,117 @@
+package grpc
+
+import (
+	"fmt"
+	"testing"
+
+	petionProto "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/interceptors"
+	"github.com/chains-lab/city-petitions-svc/internal/constant/enum"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionalphapb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+)
+
+// registered lists the full names of every method and stream served by Run.
+func registered() []string {
+	var res []string
+	for _, desc := range []grpc.ServiceDesc{
+		petionProto.PetitionService_ServiceDesc,
+		healthpb.Health_ServiceDesc,
+		reflectionpb.ServerReflection_ServiceDesc,
+		reflectionalphapb.ServerReflection_ServiceDesc,
+	} {
+		for _, m := range desc.Methods {
+			res = append(res, fmt.Sprintf("/%s/%s", desc.ServiceName, m.MethodName))
+		}
+		for _, s := range desc.Streams {
+			res = append(res, fmt.Sprintf("/%s/%s", desc.ServiceName, s.StreamName))
+		}
+	}
+
+	return res
+}
+
+func TestEveryMethodHasPolicy(t *testing.T) {
+	methods := registered()
+	if len(methods) == 0 {
+		t.Fatal("no registered methods found")
+	}
+
+	for _, fullMethod := range methods {
+		if _, ok := methodPolicies.Lookup(fullMethod); !ok {
+			t.Errorf("method %s has no access policy", fullMethod)
+		}
+	}
+}
+
+func TestNoPolicyForUnknownMethod(t *testing.T) {
+	known := make(map[string]bool)
+	for _, fullMethod := range registered() {
+		known[fullMethod] = true
+	}
+
+	for fullMethod := range methodPolicies {
+		if !known[fullMethod] {
+			t.Errorf("policy for %s, which is not a registered method", fullMethod)
+		}
+	}
+}
+
+func TestPoliciesValidate(t *testing.T) {
+	services := make(map[string]grpc.ServiceInfo)
+	for _, desc := range []grpc.ServiceDesc{petionProto.PetitionService_ServiceDesc, healthpb.Health_ServiceDesc} {
+		info := grpc.ServiceInfo{}
+		for _, m := range desc.Methods {
+			info.Methods = append(info.Methods, grpc.MethodInfo{Name: m.MethodName})
+		}
+		for _, s := range desc.Streams {
+			info.Methods = append(info.Methods, grpc.MethodInfo{Name: s.StreamName, IsServerStream: s.ServerStreams})
+		}
+		services[desc.ServiceName] = info
+	}
+
+	if err := methodPolicies.Validate(services); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	services["petition.PetitionService"] = grpc.ServiceInfo{
+		Methods: append(services["petition.PetitionService"].Methods, grpc.MethodInfo{Name: "Unlisted"}),
+	}
+	if err := methodPolicies.Validate(services); err == nil {
+		t.Error("Validate accepted a method without policy")
+	}
+}
+
+func TestRolePolicies(t *testing.T) {
+	var roleMethods []string
+	for fullMethod, policy := range methodPolicies {
+		if policy.Access != interceptors.AccessRole {
+			continue
+		}
+
+		roleMethods = append(roleMethods, fullMethod)
+		for _, role := range policy.Roles {
+			if _, err := enum.ParseUserRole(role); err != nil {
+				t.Errorf("method %s: %v", fullMethod, err)
+			}
+		}
+		if policy.AllowsRole(enum.UserRoleUser) {
+			t.Errorf("method %s requires a role but allows plain users", fullMethod)
+		}
+	}
+
+	access := methodPolicies[petionProto.PetitionService_GetAccessPolicy_FullMethodName]
+	if access.Access != interceptors.AccessRole {
+		t.Errorf("GetAccessPolicy access = %s, want %s", access.Access, interceptors.AccessRole)
+	}
+	for _, role := range []string{enum.UserRoleModerator, enum.UserRoleUser} {
+		if access.AllowsRole(role) {
+			t.Errorf("GetAccessPolicy allows role %s", role)
+		}
+	}
+	if len(roleMethods) == 0 {
+		t.Error("no method requires a role")
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 func InternalError(
@@ -139,6 +140,104 @@ func PermissionDeniedError(
 	return st.Err()
 }
 
+func AlreadyExistsError(
+	ctx context.Context,
+	message string,
+) error {
+	requestID := meta.RequestID(ctx)
+	if requestID == uuid.Nil.String() {
+		requestID = "unknown"
+	}
+
+	st := status.New(codes.AlreadyExists, message)
+
+	info := &errdetails.ErrorInfo{
+		Reason: canonicalString(st.Code()),
+		Domain: constant.ServiceName,
+		Metadata: map[string]string{
+			"timestamp": time.Now().UTC().Format(time.RFC3339Nano),
+		},
+	}
+
+	ri := &errdetails.RequestInfo{
+		RequestId: requestID,
+	}
+
+	st, err := st.WithDetails(info, ri)
+	if err != nil {
+		return st.Err()
+	}
+
+	return st.Err()
+}
+
+func AbortedError(
+	ctx context.Context,
+	message string,
+) error {
+	requestID := meta.RequestID(ctx)
+	if requestID == uuid.Nil.String() {
+		requestID = "unknown"
+	}
+
+	st := status.New(codes.Aborted, message)
+
+	info := &errdetails.ErrorInfo{
+		Reason: canonicalString(st.Code()),
+		Domain: constant.ServiceName,
+		Metadata: map[string]string{
+			"timestamp": time.Now().UTC().Format(time.RFC3339Nano),
+		},
+	}
+
+	ri := &errdetails.RequestInfo{
+		RequestId: requestID,
+	}
+
+	st, err := st.WithDetails(info, ri)
+	if err != nil {
+		return st.Err()
+	}
+
+	return st.Err()
+}
+
+func ResourceExhaustedError(
+	ctx context.Context,
+	message string,
+	retryAfter time.Duration,
+) error {
+	requestID := meta.RequestID(ctx)
+	if requestID == uuid.Nil.String() {
+		requestID = "unknown"
+	}
+
+	st := status.New(codes.ResourceExhausted, message)
+
+	info := &errdetails.ErrorInfo{
+		Reason: canonicalString(st.Code()),
+		Domain: constant.ServiceName,
+		Metadata: map[string]string{
+			"timestamp": time.Now().UTC().Format(time.RFC3339Nano),
+		},
+	}
+
+	retry := &errdetails.RetryInfo{
+		RetryDelay: durationpb.New(retryAfter),
+	}
+
+	ri := &errdetails.RequestInfo{
+		RequestId: requestID,
+	}
+
+	st, err := st.WithDetails(info, retry, ri)
+	if err != nil {
+		return st.Err()
+	}
+
+	return st.Err()
+}
+
 func canonicalString(c codes.Code) string {
 	switch c {
 	case codes.OK:
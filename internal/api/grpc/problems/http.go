@@ -0,0 +1,119 @@
+package problems
+
+import (
+	"math"
+	"net/http"
+
+	"github.com/chains-lab/city-petitions-svc/internal/constant"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Problem is an RFC 7807 problem details document built from a gRPC status.
+type Problem struct {
+	Type          string            `json:"type"`
+	Title         string            `json:"title"`
+	Status        int               `json:"status"`
+	Detail        string            `json:"detail,omitempty"`
+	Instance      string            `json:"instance,omitempty"`
+	Reason        string            `json:"reason,omitempty"`
+	Domain        string            `json:"domain,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	RequestID     string            `json:"request_id,omitempty"`
+	InvalidParams []InvalidParam    `json:"invalid_params,omitempty"`
+	Violations    []Violation       `json:"violations,omitempty"`
+	RetryAfter    int64             `json:"retry_after,omitempty"` // seconds
+}
+
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// Violation is a failed precondition, e.g. a similar petition that already exists.
+type Violation struct {
+	Type        string `json:"type"`
+	Subject     string `json:"subject"`
+	Description string `json:"description"`
+}
+
+// HTTPProblem translates a gRPC status and its details into problem JSON.
+func HTTPProblem(st *status.Status, instance string) Problem {
+	httpStatus := HTTPStatus(st.Code())
+
+	problem := Problem{
+		Type:     "about:blank",
+		Title:    canonicalString(st.Code()),
+		Status:   httpStatus,
+		Detail:   st.Message(),
+		Instance: instance,
+	}
+
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			problem.Reason = d.GetReason()
+			problem.Domain = d.GetDomain()
+			problem.Metadata = d.GetMetadata()
+			if d.GetReason() != "" {
+				problem.Type = "urn:" + constant.ServiceName + ":problem:" + d.GetReason()
+			}
+		case *errdetails.RequestInfo:
+			problem.RequestID = d.GetRequestId()
+		case *errdetails.BadRequest:
+			for _, v := range d.GetFieldViolations() {
+				problem.InvalidParams = append(problem.InvalidParams, InvalidParam{
+					Name:   v.GetField(),
+					Reason: v.GetDescription(),
+				})
+			}
+		case *errdetails.PreconditionFailure:
+			for _, v := range d.GetViolations() {
+				problem.Violations = append(problem.Violations, Violation{
+					Type:        v.GetType(),
+					Subject:     v.GetSubject(),
+					Description: v.GetDescription(),
+				})
+			}
+		case *errdetails.RetryInfo:
+			if d.GetRetryDelay() != nil {
+				problem.RetryAfter = int64(math.Ceil(d.GetRetryDelay().AsDuration().Seconds()))
+			}
+		}
+	}
+
+	return problem
+}
+
+// HTTPStatus maps gRPC codes the same way grpc-gateway does.
+func HTTPStatus(c codes.Code) int {
+	switch c {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,31 @@
+package responses
+
+import (
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/app/models"
+)
+
+func AccessPolicy(rules []models.AccessRule) *svc.AccessPolicy {
+	res := make([]*svc.AccessRule, 0, len(rules))
+	for _, rule := range rules {
+		r := &svc.AccessRule{
+			Permission: rule.Permission,
+			CityIds:    make([]string, 0, len(rule.CityIDs)),
+		}
+		if rule.Role != "" {
+			role := rule.Role
+			r.Role = &role
+		}
+		if rule.UserID != nil {
+			userID := rule.UserID.String()
+			r.UserId = &userID
+		}
+		for _, cityID := range rule.CityIDs {
+			r.CityIds = append(r.CityIds, cityID.String())
+		}
+
+		res = append(res, r)
+	}
+
+	return &svc.AccessPolicy{Rules: res}
+}
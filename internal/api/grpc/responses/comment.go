@@ -0,0 +1,45 @@
+package responses
+
+import (
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/app/models"
+	"github.com/chains-lab/city-petitions-svc/internal/pagination"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func Comment(model models.Comment) *svc.Comment {
+	resp := &svc.Comment{
+		Id:         model.ID.String(),
+		PetitionId: model.PetitionID.String(),
+		Depth:      uint32(model.Depth),
+		Replies:    uint32(model.Replies),
+		AuthorId:   model.AuthorID.String(),
+		Body:       model.Body,
+		Status:     model.Status,
+		Deleted:    model.Deleted,
+		CreatedAt:  timestamppb.New(model.CreatedAt),
+	}
+
+	if model.ParentID != nil {
+		parentID := model.ParentID.String()
+		resp.ParentId = &parentID
+	}
+	if model.EditedAt != nil {
+		resp.EditedAt = timestamppb.New(*model.EditedAt)
+	}
+
+	return resp
+}
+
+func CommentList(models []models.Comment, next pagination.CursorResponse) *svc.CommentList {
+	comments := make([]*svc.Comment, 0, len(models))
+
+	for _, model := range models {
+		comments = append(comments, Comment(model))
+	}
+
+	return &svc.CommentList{
+		Comments:   comments,
+		NextCursor: next.NextCursor,
+	}
+}
@@ -0,0 +1,49 @@
+package responses
+
+import (
+	pagProto "github.com/chains-lab/city-petitions-proto/gen/go/common/pagination"
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/app/models"
+	"github.com/chains-lab/city-petitions-svc/internal/pagination"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func ContentFlag(model models.ContentFlag) *svc.ContentFlag {
+	res := &svc.ContentFlag{
+		Id:         model.ID.String(),
+		PetitionId: model.PetitionID.String(),
+		Field:      model.Field,
+		Filter:     model.Filter,
+		Action:     model.Action,
+		Details:    model.Details,
+		Status:     model.Status,
+		CreatedAt:  timestamppb.New(model.CreatedAt),
+	}
+
+	if model.ReviewedBy != nil {
+		reviewedBy := model.ReviewedBy.String()
+		res.ReviewedBy = &reviewedBy
+	}
+	if model.ReviewedAt != nil {
+		res.ReviewedAt = timestamppb.New(*model.ReviewedAt)
+	}
+
+	return res
+}
+
+func ContentFlagList(models []models.ContentFlag, pagResp pagination.Response) *svc.ContentFlagList {
+	flags := make([]*svc.ContentFlag, 0, len(models))
+
+	for _, model := range models {
+		flags = append(flags, ContentFlag(model))
+	}
+
+	return &svc.ContentFlagList{
+		Flags: flags,
+		Pagination: &pagProto.Response{
+			Page:  pagResp.Page,
+			Size:  pagResp.Size,
+			Total: pagResp.Total,
+		},
+	}
+}
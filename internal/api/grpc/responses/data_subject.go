@@ -0,0 +1,28 @@
+package responses
+
+import (
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/app/models"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func UserDataExport(model models.UserDataExport, bundle []byte) *svc.UserDataExport {
+	return &svc.UserDataExport{
+		UserId:      model.UserID.String(),
+		Bundle:      string(bundle),
+		GeneratedAt: timestamppb.New(model.GeneratedAt),
+	}
+}
+
+func UserDataErasure(model models.UserDataErasure) *svc.UserDataErasure {
+	return &svc.UserDataErasure{
+		UserId:         model.UserID.String(),
+		Petitions:      uint32(model.Petitions),
+		Signatures:     uint32(model.Signatures),
+		CoAuthorships:  uint32(model.CoAuthorships),
+		Comments:       uint32(model.Comments),
+		CommentReports: uint32(model.CommentReports),
+		SignatureFlags: uint32(model.SignatureFlags),
+		ContentFlags:   uint32(model.ContentFlags),
+	}
+}
@@ -9,7 +9,7 @@ import (
 )
 
 func Petition(model models.Petition) *svc.Petition {
-	return &svc.Petition{
+	resp := &svc.Petition{
 		Id:          model.ID.String(),
 		CityId:      model.CityID.String(),
 		Title:       model.Title,
@@ -23,6 +23,33 @@ func Petition(model models.Petition) *svc.Petition {
 		CreatedAt:   timestamppb.New(model.CreatedAt),
 		UpdatedAt:   timestamppb.New(model.UpdatedAt),
 	}
+
+	if model.MergedInto != nil {
+		mergedInto := model.MergedInto.String()
+		resp.MergedInto = &mergedInto
+	}
+
+	for _, author := range model.CoAuthors {
+		resp.CoAuthors = append(resp.CoAuthors, PetitionAuthor(author))
+	}
+
+	return resp
+}
+
+func PetitionAuthor(model models.PetitionAuthor) *svc.PetitionAuthor {
+	resp := &svc.PetitionAuthor{
+		PetitionId: model.PetitionID.String(),
+		UserId:     model.UserID.String(),
+		Status:     model.Status,
+		InvitedBy:  model.InvitedBy.String(),
+		CreatedAt:  timestamppb.New(model.CreatedAt),
+	}
+
+	if model.RespondedAt != nil {
+		resp.RespondedAt = timestamppb.New(*model.RespondedAt)
+	}
+
+	return resp
 }
 
 func PetitionsList(models []models.Petition, pagResp pagination.Response) *svc.PetitionList {
@@ -41,3 +68,39 @@ func PetitionsList(models []models.Petition, pagResp pagination.Response) *svc.P
 		},
 	}
 }
+
+func PetitionUpdate(model models.PetitionUpdate) *svc.PetitionUpdate {
+	resp := &svc.PetitionUpdate{
+		PetitionId: model.PetitionID.String(),
+		Signatures: uint32(model.Signatures),
+		Status:     model.Status,
+		UpdatedAt:  timestamppb.New(model.UpdatedAt),
+	}
+
+	if model.MergedInto != nil {
+		mergedInto := model.MergedInto.String()
+		resp.MergedInto = &mergedInto
+	}
+
+	return resp
+}
+
+func DuplicateCandidates(models []models.DuplicateCandidate) *svc.CheckDuplicatesResponse {
+	candidates := make([]*svc.DuplicateCandidate, 0, len(models))
+
+	for _, model := range models {
+		candidates = append(candidates, &svc.DuplicateCandidate{
+			Petition:   Petition(model.Petition),
+			Similarity: model.Similarity,
+		})
+	}
+
+	return &svc.CheckDuplicatesResponse{Candidates: candidates}
+}
+
+func MergedPetitions(target models.Petition, moved int) *svc.MergePetitionsResponse {
+	return &svc.MergePetitionsResponse{
+		Petition: Petition(target),
+		Moved:    uint32(moved),
+	}
+}
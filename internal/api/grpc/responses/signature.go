@@ -5,16 +5,39 @@ import (
 	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
 	"github.com/chains-lab/city-petitions-svc/internal/app/models"
 	"github.com/chains-lab/city-petitions-svc/internal/pagination"
+	"github.com/google/uuid"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func Signature(model models.PetitionSignature) *svc.Signature {
-	return &svc.Signature{
+	res := &svc.Signature{
 		Id:         model.ID.String(),
 		PetitionId: model.PetitionID.String(),
-		UserId:     model.UserID.String(),
 		CreatedAt:  timestamppb.New(model.CreatedAt),
+		Visibility: model.Visibility,
 	}
+
+	// the signer is left out when the viewer may not see it
+	if model.UserID != uuid.Nil {
+		res.UserId = model.UserID.String()
+	}
+	if model.Reason != "" {
+		res.Reason = &model.Reason
+	}
+	if model.Receipt != nil {
+		res.Receipt = SignatureReceipt(*model.Receipt)
+	}
+
+	return res
+}
+
+func SignatureReasons(models []models.PetitionSignature) *svc.SignatureReasonList {
+	reasons := make([]*svc.Signature, 0, len(models))
+	for _, model := range models {
+		reasons = append(reasons, Signature(model))
+	}
+
+	return &svc.SignatureReasonList{Reasons: reasons}
 }
 
 func SignaturesList(models []models.PetitionSignature, pagResp pagination.Response) *svc.SignatureList {
@@ -33,3 +56,16 @@ func SignaturesList(models []models.PetitionSignature, pagResp pagination.Respon
 		},
 	}
 }
+
+func SignatureStats(model models.SignatureStats) *svc.SignatureStats {
+	return &svc.SignatureStats{
+		PetitionId:  model.PetitionID.String(),
+		Total:       uint32(model.Total),
+		Verified:    uint32(model.Verified),
+		Invalidated: uint32(model.Invalidated),
+		Public:      uint32(model.Public),
+		Anonymous:   uint32(model.Anonymous),
+		Private:     uint32(model.Private),
+		WithReason:  uint32(model.WithReason),
+	}
+}
@@ -0,0 +1,48 @@
+package responses
+
+import (
+	pagProto "github.com/chains-lab/city-petitions-proto/gen/go/common/pagination"
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/app/models"
+	"github.com/chains-lab/city-petitions-svc/internal/pagination"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func SignatureFlag(model models.SignatureFlag) *svc.SignatureFlag {
+	res := &svc.SignatureFlag{
+		Id:          model.ID.String(),
+		SignatureId: model.SignatureID.String(),
+		PetitionId:  model.PetitionID.String(),
+		Reason:      model.Reason,
+		Details:     model.Details,
+		Status:      model.Status,
+		CreatedAt:   timestamppb.New(model.CreatedAt),
+	}
+
+	if model.ReviewedBy != nil {
+		reviewedBy := model.ReviewedBy.String()
+		res.ReviewedBy = &reviewedBy
+	}
+	if model.ReviewedAt != nil {
+		res.ReviewedAt = timestamppb.New(*model.ReviewedAt)
+	}
+
+	return res
+}
+
+func SignatureFlagList(models []models.SignatureFlag, pagResp pagination.Response) *svc.SignatureFlagList {
+	flags := make([]*svc.SignatureFlag, 0, len(models))
+
+	for _, model := range models {
+		flags = append(flags, SignatureFlag(model))
+	}
+
+	return &svc.SignatureFlagList{
+		Flags: flags,
+		Pagination: &pagProto.Response{
+			Page:  pagResp.Page,
+			Size:  pagResp.Size,
+			Total: pagResp.Total,
+		},
+	}
+}
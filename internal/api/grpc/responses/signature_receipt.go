@@ -0,0 +1,40 @@
+package responses
+
+import (
+	"encoding/hex"
+
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/app/models"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func SignatureReceipt(model models.SignatureReceipt) *svc.SignatureReceipt {
+	return &svc.SignatureReceipt{
+		PetitionId:  model.PetitionID.String(),
+		SignatureId: model.SignatureID.String(),
+		Seq:         model.Seq,
+		UserHash:    hex.EncodeToString(model.UserHash),
+		PrevHash:    hex.EncodeToString(model.PrevHash),
+		Hash:        hex.EncodeToString(model.Hash),
+		CreatedAt:   timestamppb.New(model.CreatedAt),
+	}
+}
+
+func ReceiptVerification(model models.ReceiptVerification) *svc.SignatureReceiptVerification {
+	res := &svc.SignatureReceiptVerification{
+		Valid:   model.Valid,
+		Problem: model.Problem,
+	}
+
+	if model.Entry != nil {
+		res.Entry = SignatureReceipt(*model.Entry)
+	}
+	if model.Head != nil {
+		res.Head = &svc.SignatureChainHead{
+			Seq:  model.Head.Seq,
+			Hash: hex.EncodeToString(model.Head.Hash),
+		}
+	}
+
+	return res
+}
@@ -2,11 +2,13 @@ package petition
 
 import (
 	"context"
+	"strings"
 
 	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
 	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
 	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/problems"
 	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/responses"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/validation"
 	"github.com/chains-lab/city-petitions-svc/internal/logger"
 	"github.com/google/uuid"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -15,8 +17,6 @@ import (
 func (s Service) ApprovePetition(ctx context.Context, req *svc.ApprovePetitionRequest) (*svc.Petition, error) {
 	initiator := meta.User(ctx)
 
-	//TODO go to city svc check if user in city gov
-
 	petitionId, err := uuid.Parse(req.GetPetitionId())
 	if err != nil {
 		logger.Log(ctx).Errorf("failed to parse petition id: %v", err)
@@ -27,7 +27,15 @@ func (s Service) ApprovePetition(ctx context.Context, req *svc.ApprovePetitionRe
 		})
 	}
 
-	petition, err := s.app.ApprovePetition(ctx, petitionId, req.Reply)
+	reply := strings.TrimSpace(req.GetReply())
+
+	var violations validation.Violations
+	violations.Text("reply", reply, validation.Reply)
+	if err := violations.Err(ctx); err != nil {
+		return nil, err
+	}
+
+	petition, err := s.app.ApprovePetition(ctx, newInitiator(initiator), petitionId, reply)
 	if err != nil {
 		logger.Log(ctx).Errorf("failed to approve petition: %v", err)
 
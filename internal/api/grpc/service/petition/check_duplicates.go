@@ -0,0 +1,41 @@
+package petition
+
+import (
+	"context"
+	"strings"
+
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/problems"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/responses"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/validation"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func (s Service) CheckDuplicates(ctx context.Context, req *svc.CheckDuplicatesRequest) (*svc.CheckDuplicatesResponse, error) {
+	cityID, err := uuid.Parse(req.GetCityId())
+	if err != nil {
+		return nil, problems.InvalidArgumentError(ctx, "city_id is invalid", &errdetails.BadRequest_FieldViolation{
+			Field:       "city_id",
+			Description: "invalid UUID format for city ID",
+		})
+	}
+
+	title := strings.TrimSpace(req.GetTitle())
+
+	var violations validation.Violations
+	violations.Text("title", title, validation.Title)
+	if err := violations.Err(ctx); err != nil {
+		return nil, err
+	}
+
+	candidates, err := s.app.FindDuplicates(ctx, cityID, title)
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to find duplicate petitions: %v", err)
+
+		return nil, err
+	}
+
+	return responses.DuplicateCandidates(candidates), nil
+}
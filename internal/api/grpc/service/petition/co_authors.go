@@ -0,0 +1,99 @@
+package petition
+
+import (
+	"context"
+
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/responses"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+)
+
+func (s Service) InviteCoAuthor(ctx context.Context, req *svc.InviteCoAuthorRequest) (*svc.PetitionAuthor, error) {
+	initiator := meta.User(ctx)
+
+	petitionID, err := parseID(ctx, "petition_id", req.GetPetitionId())
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := parseID(ctx, "user_id", req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	author, err := s.app.InviteCoAuthor(ctx, newInitiator(initiator), petitionID, userID)
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to invite co-author: %v", err)
+
+		return nil, err
+	}
+
+	logger.Log(ctx).Infof("user %s invited %s to co-author petition %s", initiator.ID, userID, petitionID)
+
+	return responses.PetitionAuthor(author), nil
+}
+
+func (s Service) AcceptCoAuthorInvitation(ctx context.Context, req *svc.AcceptCoAuthorInvitationRequest) (*svc.PetitionAuthor, error) {
+	initiator := meta.User(ctx)
+
+	petitionID, err := parseID(ctx, "petition_id", req.GetPetitionId())
+	if err != nil {
+		return nil, err
+	}
+
+	author, err := s.app.AcceptCoAuthorInvitation(ctx, newInitiator(initiator), petitionID)
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to accept co-author invitation: %v", err)
+
+		return nil, err
+	}
+
+	logger.Log(ctx).Infof("user %s became co-author of petition %s", initiator.ID, petitionID)
+
+	return responses.PetitionAuthor(author), nil
+}
+
+func (s Service) DeclineCoAuthorInvitation(ctx context.Context, req *svc.DeclineCoAuthorInvitationRequest) (*svc.PetitionAuthor, error) {
+	initiator := meta.User(ctx)
+
+	petitionID, err := parseID(ctx, "petition_id", req.GetPetitionId())
+	if err != nil {
+		return nil, err
+	}
+
+	author, err := s.app.DeclineCoAuthorInvitation(ctx, newInitiator(initiator), petitionID)
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to decline co-author invitation: %v", err)
+
+		return nil, err
+	}
+
+	logger.Log(ctx).Infof("user %s declined to co-author petition %s", initiator.ID, petitionID)
+
+	return responses.PetitionAuthor(author), nil
+}
+
+func (s Service) RemoveCoAuthor(ctx context.Context, req *svc.RemoveCoAuthorRequest) (*svc.RemoveCoAuthorResponse, error) {
+	initiator := meta.User(ctx)
+
+	petitionID, err := parseID(ctx, "petition_id", req.GetPetitionId())
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := parseID(ctx, "user_id", req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.app.RemoveCoAuthor(ctx, newInitiator(initiator), petitionID, userID); err != nil {
+		logger.Log(ctx).Errorf("failed to remove co-author: %v", err)
+
+		return nil, err
+	}
+
+	logger.Log(ctx).Infof("user %s removed co-author %s from petition %s", initiator.ID, userID, petitionID)
+
+	return &svc.RemoveCoAuthorResponse{}, nil
+}
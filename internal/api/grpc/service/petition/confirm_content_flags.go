@@ -0,0 +1,29 @@
+package petition
+
+import (
+	"context"
+
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+)
+
+func (s Service) ConfirmContentFlags(ctx context.Context, req *svc.ConfirmContentFlagsRequest) (*svc.ConfirmContentFlagsResponse, error) {
+	initiator := meta.User(ctx)
+
+	flagIDs, err := parseIDs(ctx, "flag_ids", req.GetFlagIds(), maxBatchIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	confirmed, err := s.app.ConfirmContentFlags(ctx, newInitiator(initiator), flagIDs)
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to confirm content flags: %v", err)
+
+		return nil, err
+	}
+
+	logger.Log(ctx).Infof("initiator %s confirmed %d content flags", initiator.ID, confirmed)
+
+	return &svc.ConfirmContentFlagsResponse{Confirmed: uint32(confirmed)}, nil
+}
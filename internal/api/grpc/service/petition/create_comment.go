@@ -0,0 +1,50 @@
+package petition
+
+import (
+	"context"
+	"strings"
+
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/responses"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/validation"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+	"github.com/google/uuid"
+)
+
+func (s Service) CreateComment(ctx context.Context, req *svc.CreateCommentRequest) (*svc.Comment, error) {
+	initiator := meta.User(ctx)
+
+	petitionID, err := parseID(ctx, "petition_id", req.GetPetitionId())
+	if err != nil {
+		return nil, err
+	}
+
+	var parentID *uuid.UUID
+	if req.ParentId != nil {
+		id, err := parseID(ctx, "parent_id", req.GetParentId())
+		if err != nil {
+			return nil, err
+		}
+		parentID = &id
+	}
+
+	body := strings.TrimSpace(req.GetBody())
+
+	var violations validation.Violations
+	violations.Text("body", body, validation.Comment)
+	if err := violations.Err(ctx); err != nil {
+		return nil, err
+	}
+
+	comment, err := s.app.CreateComment(ctx, newInitiator(initiator), petitionID, parentID, body)
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to create comment: %v", err)
+
+		return nil, err
+	}
+
+	logger.Log(ctx).Infof("user %s commented on petition %s as %s", initiator.ID, petitionID, comment.Status)
+
+	return responses.Comment(comment), nil
+}
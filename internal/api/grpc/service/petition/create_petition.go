@@ -2,11 +2,13 @@ package petition
 
 import (
 	"context"
+	"strings"
 
 	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
 	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
 	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/problems"
 	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/responses"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/validation"
 	"github.com/chains-lab/city-petitions-svc/internal/app/entities"
 	"github.com/chains-lab/city-petitions-svc/internal/logger"
 	"github.com/google/uuid"
@@ -24,9 +26,21 @@ func (s Service) CreatePetition(ctx context.Context, req *svc.CreatePetitionRequ
 		})
 	}
 
-	petition, err := s.app.CreatePetition(ctx, cityID, initiator.ID, entities.CreatePetitionInput{
-		Title:       req.Title,
-		Description: req.Description,
+	title := strings.TrimSpace(req.GetTitle())
+	description := strings.TrimSpace(req.GetDescription())
+
+	var violations validation.Violations
+	violations.Text("title", title, validation.Title)
+	violations.Text("description", description, validation.Description)
+	if err := violations.Err(ctx); err != nil {
+		return nil, err
+	}
+
+	petition, err := s.app.CreatePetition(ctx, cityID, newInitiator(initiator), entities.CreatePetitionInput{
+		Title:       title,
+		Description: description,
+		Force:       req.GetForce(),
+		Draft:       req.GetDraft(),
 	})
 	if err != nil {
 		logger.Log(ctx).Errorf("failed to create petition: %v", err)
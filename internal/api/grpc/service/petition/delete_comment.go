@@ -0,0 +1,28 @@
+package petition
+
+import (
+	"context"
+
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+)
+
+func (s Service) DeleteComment(ctx context.Context, req *svc.DeleteCommentRequest) (*svc.DeleteCommentResponse, error) {
+	initiator := meta.User(ctx)
+
+	commentID, err := parseID(ctx, "comment_id", req.GetCommentId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.app.DeleteComment(ctx, newInitiator(initiator), commentID); err != nil {
+		logger.Log(ctx).Errorf("failed to delete comment: %v", err)
+
+		return nil, err
+	}
+
+	logger.Log(ctx).Infof("user %s deleted comment %s", initiator.ID, commentID)
+
+	return &svc.DeleteCommentResponse{}, nil
+}
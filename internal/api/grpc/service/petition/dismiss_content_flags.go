@@ -0,0 +1,29 @@
+package petition
+
+import (
+	"context"
+
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+)
+
+func (s Service) DismissContentFlags(ctx context.Context, req *svc.DismissContentFlagsRequest) (*svc.DismissContentFlagsResponse, error) {
+	initiator := meta.User(ctx)
+
+	flagIDs, err := parseIDs(ctx, "flag_ids", req.GetFlagIds(), maxBatchIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	dismissed, err := s.app.DismissContentFlags(ctx, newInitiator(initiator), flagIDs)
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to dismiss content flags: %v", err)
+
+		return nil, err
+	}
+
+	logger.Log(ctx).Infof("initiator %s dismissed %d content flags", initiator.ID, dismissed)
+
+	return &svc.DismissContentFlagsResponse{Dismissed: uint32(dismissed)}, nil
+}
@@ -0,0 +1,40 @@
+package petition
+
+import (
+	"context"
+	"strings"
+
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/responses"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/validation"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+)
+
+func (s Service) EditComment(ctx context.Context, req *svc.EditCommentRequest) (*svc.Comment, error) {
+	initiator := meta.User(ctx)
+
+	commentID, err := parseID(ctx, "comment_id", req.GetCommentId())
+	if err != nil {
+		return nil, err
+	}
+
+	body := strings.TrimSpace(req.GetBody())
+
+	var violations validation.Violations
+	violations.Text("body", body, validation.Comment)
+	if err := violations.Err(ctx); err != nil {
+		return nil, err
+	}
+
+	comment, err := s.app.EditComment(ctx, newInitiator(initiator), commentID, body)
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to edit comment: %v", err)
+
+		return nil, err
+	}
+
+	logger.Log(ctx).Infof("user %s edited comment %s", initiator.ID, commentID)
+
+	return responses.Comment(comment), nil
+}
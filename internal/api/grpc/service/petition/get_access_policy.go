@@ -0,0 +1,23 @@
+package petition
+
+import (
+	"context"
+
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/responses"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+)
+
+func (s Service) GetAccessPolicy(ctx context.Context, _ *svc.GetAccessPolicyRequest) (*svc.AccessPolicy, error) {
+	initiator := meta.User(ctx)
+
+	rules, err := s.app.GetAccessPolicy(ctx, newInitiator(initiator))
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to get access policy: %v", err)
+
+		return nil, err
+	}
+
+	return responses.AccessPolicy(rules), nil
+}
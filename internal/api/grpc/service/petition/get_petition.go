@@ -4,8 +4,10 @@ import (
 	"context"
 
 	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
 	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/problems"
 	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/responses"
+	"github.com/chains-lab/city-petitions-svc/internal/app/entities"
 	"github.com/chains-lab/city-petitions-svc/internal/logger"
 	"github.com/google/uuid"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -20,7 +22,14 @@ func (s Service) GetPetition(ctx context.Context, req *svc.GetPetitionRequest) (
 		})
 	}
 
-	petition, err := s.app.GetPetition(ctx, petitionID)
+	// the petition is public, a supplied user token lets authors and moderators see unpublished ones
+	var viewer *entities.Initiator
+	if user := meta.User(ctx); user != nil {
+		initiator := newInitiator(user)
+		viewer = &initiator
+	}
+
+	petition, err := s.app.GetPetition(ctx, viewer, petitionID)
 	if err != nil {
 		logger.Log(ctx).Errorf("failed to get petition: %v", err)
 
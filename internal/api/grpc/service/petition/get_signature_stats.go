@@ -0,0 +1,28 @@
+package petition
+
+import (
+	"context"
+
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/responses"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+)
+
+func (s Service) GetSignatureStats(ctx context.Context, req *svc.GetSignatureStatsRequest) (*svc.SignatureStats, error) {
+	initiator := meta.User(ctx)
+
+	petitionID, err := parseID(ctx, "petition_id", req.GetPetitionId())
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.app.GetSignatureStats(ctx, newInitiator(initiator), petitionID)
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to get signature stats: %v", err)
+
+		return nil, err
+	}
+
+	return responses.SignatureStats(stats), nil
+}
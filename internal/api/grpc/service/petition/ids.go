@@ -0,0 +1,56 @@
+package petition
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/problems"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// maxBatchIDs bounds the number of IDs accepted by batch methods.
+const maxBatchIDs = 100
+
+// parseIDs parses between 1 and max UUIDs of the request field.
+func parseIDs(ctx context.Context, field string, ids []string, max int) ([]uuid.UUID, error) {
+	if len(ids) == 0 || len(ids) > max {
+		return nil, problems.InvalidArgumentError(ctx, fmt.Sprintf("%s is invalid", field), &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: fmt.Sprintf("between 1 and %d IDs must be supplied", max),
+		})
+	}
+
+	res := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			logger.Log(ctx).Errorf("failed to parse %s: %v", field, err)
+
+			return nil, problems.InvalidArgumentError(ctx, fmt.Sprintf("%s is invalid", field), &errdetails.BadRequest_FieldViolation{
+				Field:       field,
+				Description: fmt.Sprintf("invalid UUID format '%s'", id),
+			})
+		}
+
+		res = append(res, parsed)
+	}
+
+	return res, nil
+}
+
+// parseID parses the UUID of a single request field.
+func parseID(ctx context.Context, field, id string) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to parse %s: %v", field, err)
+
+		return uuid.Nil, problems.InvalidArgumentError(ctx, fmt.Sprintf("%s is invalid", field), &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: fmt.Sprintf("invalid UUID format '%s'", id),
+		})
+	}
+
+	return parsed, nil
+}
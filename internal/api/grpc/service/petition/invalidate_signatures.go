@@ -0,0 +1,39 @@
+package petition
+
+import (
+	"context"
+	"strings"
+
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/validation"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+)
+
+func (s Service) InvalidateSignatures(ctx context.Context, req *svc.InvalidateSignaturesRequest) (*svc.InvalidateSignaturesResponse, error) {
+	initiator := meta.User(ctx)
+
+	signatureIDs, err := parseIDs(ctx, "signature_ids", req.GetSignatureIds(), maxBatchIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := strings.TrimSpace(req.GetReason())
+
+	var violations validation.Violations
+	violations.Text("reason", reason, validation.Reason)
+	if err := violations.Err(ctx); err != nil {
+		return nil, err
+	}
+
+	invalidated, err := s.app.InvalidateSignatures(ctx, newInitiator(initiator), signatureIDs, reason)
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to invalidate signatures: %v", err)
+
+		return nil, err
+	}
+
+	logger.Log(ctx).Infof("initiator %s invalidated %d signatures: %s", initiator.ID, invalidated, reason)
+
+	return &svc.InvalidateSignaturesResponse{Invalidated: uint32(invalidated)}, nil
+}
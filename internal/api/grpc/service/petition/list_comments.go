@@ -0,0 +1,59 @@
+package petition
+
+import (
+	"context"
+
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/problems"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/responses"
+	"github.com/chains-lab/city-petitions-svc/internal/app/entities"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+	"github.com/chains-lab/city-petitions-svc/internal/pagination"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func (s Service) ListComments(ctx context.Context, req *svc.ListCommentsRequest) (*svc.CommentList, error) {
+	petitionID, err := parseID(ctx, "petition_id", req.GetPetitionId())
+	if err != nil {
+		return nil, err
+	}
+
+	var parentID *uuid.UUID
+	if req.ParentId != nil {
+		id, err := parseID(ctx, "parent_id", req.GetParentId())
+		if err != nil {
+			return nil, err
+		}
+		parentID = &id
+	}
+
+	if req.GetCursor() != "" {
+		if _, err := pagination.DecodeCursor(req.GetCursor()); err != nil {
+			return nil, problems.InvalidArgumentError(ctx, "cursor is invalid", &errdetails.BadRequest_FieldViolation{
+				Field:       "cursor",
+				Description: "cursor must be a next_cursor returned by a previous page",
+			})
+		}
+	}
+
+	// the listing is public, a supplied user token lets authors and moderators see held comments
+	var viewer *entities.Initiator
+	if user := meta.User(ctx); user != nil {
+		initiator := newInitiator(user)
+		viewer = &initiator
+	}
+
+	comments, next, err := s.app.ListComments(ctx, viewer, petitionID, parentID, pagination.CursorRequest{
+		Cursor: req.GetCursor(),
+		Limit:  uint64(req.GetLimit()),
+	})
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to list comments: %v", err)
+
+		return nil, err
+	}
+
+	return responses.CommentList(comments, next), nil
+}
@@ -0,0 +1,70 @@
+package petition
+
+import (
+	"context"
+
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/problems"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/responses"
+	"github.com/chains-lab/city-petitions-svc/internal/app/entities"
+	"github.com/chains-lab/city-petitions-svc/internal/constant/enum"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+	"github.com/chains-lab/city-petitions-svc/internal/pagination"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func (s Service) ListContentFlags(ctx context.Context, req *svc.ListContentFlagsRequest) (*svc.ContentFlagList, error) {
+	initiator := meta.User(ctx)
+
+	cityID, err := uuid.Parse(req.GetCityId())
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to parse city id: %v", err)
+
+		return nil, problems.InvalidArgumentError(ctx, "city_id is invalid", &errdetails.BadRequest_FieldViolation{
+			Field:       "city_id",
+			Description: "invalid UUID format for city ID",
+		})
+	}
+
+	filter := entities.ListContentFlagsFilter{CityID: cityID}
+
+	if req.PetitionId != nil {
+		petitionID, err := uuid.Parse(*req.PetitionId)
+		if err != nil {
+			logger.Log(ctx).Errorf("failed to parse petition id: %v", err)
+
+			return nil, problems.InvalidArgumentError(ctx, "petition_id is invalid", &errdetails.BadRequest_FieldViolation{
+				Field:       "petition_id",
+				Description: "invalid UUID format for petition ID",
+			})
+		}
+
+		filter.PetitionID = &petitionID
+	}
+
+	if req.Status != nil {
+		status, err := enum.ParseContentFlagStatus(*req.Status)
+		if err != nil {
+			return nil, problems.InvalidArgumentError(ctx, "status is invalid", &errdetails.BadRequest_FieldViolation{
+				Field:       "status",
+				Description: err.Error(),
+			})
+		}
+
+		filter.Status = &status
+	}
+
+	flags, pag, err := s.app.ListContentFlags(ctx, newInitiator(initiator), filter, pagination.Request{
+		Page: req.Pag.Page,
+		Size: req.Pag.Size,
+	})
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to list content flags: %v", err)
+
+		return nil, err
+	}
+
+	return responses.ContentFlagList(flags, pag), nil
+}
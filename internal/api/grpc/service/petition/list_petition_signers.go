@@ -4,6 +4,7 @@ import (
 	"context"
 
 	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
 	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/problems"
 	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/responses"
 	"github.com/chains-lab/city-petitions-svc/internal/app/entities"
@@ -54,7 +55,14 @@ func (s Service) ListPetitionSigners(ctx context.Context, req *svc.ListPetitionS
 		sort.Newest = true
 	}
 
-	signers, pag, err := s.app.ListSignatures(ctx, filters, sort, pagination.Request{
+	// the listing is public, a supplied user token lets officials, admins and signers see more
+	var viewer *entities.Initiator
+	if user := meta.User(ctx); user != nil {
+		initiator := newInitiator(user)
+		viewer = &initiator
+	}
+
+	signers, pag, err := s.app.ListSignatures(ctx, viewer, filters, sort, pagination.Request{
 		Page: req.Pag.Page,
 		Size: req.Pag.Size,
 	})
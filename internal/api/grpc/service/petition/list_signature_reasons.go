@@ -0,0 +1,44 @@
+package petition
+
+import (
+	"context"
+	"fmt"
+
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/problems"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/responses"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+const (
+	defaultSignatureReasons = 10
+	maxSignatureReasons     = 50
+)
+
+func (s Service) ListSignatureReasons(ctx context.Context, req *svc.ListSignatureReasonsRequest) (*svc.SignatureReasonList, error) {
+	petitionID, err := parseID(ctx, "petition_id", req.GetPetitionId())
+	if err != nil {
+		return nil, err
+	}
+
+	limit := uint64(req.GetLimit())
+	switch {
+	case limit == 0:
+		limit = defaultSignatureReasons
+	case limit > maxSignatureReasons:
+		return nil, problems.InvalidArgumentError(ctx, "limit is too large", &errdetails.BadRequest_FieldViolation{
+			Field:       "limit",
+			Description: fmt.Sprintf("must be at most %d", maxSignatureReasons),
+		})
+	}
+
+	reasons, err := s.app.ListSignatureReasons(ctx, petitionID, limit)
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to list signature reasons: %v", err)
+
+		return nil, err
+	}
+
+	return responses.SignatureReasons(reasons), nil
+}
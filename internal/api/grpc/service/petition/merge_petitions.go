@@ -0,0 +1,43 @@
+package petition
+
+import (
+	"context"
+
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/problems"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/responses"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func (s Service) MergePetitions(ctx context.Context, req *svc.MergePetitionsRequest) (*svc.MergePetitionsResponse, error) {
+	initiator := meta.User(ctx)
+
+	targetID, err := uuid.Parse(req.GetPetitionId())
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to parse petition id: %v", err)
+
+		return nil, problems.InvalidArgumentError(ctx, "petition_id is invalid", &errdetails.BadRequest_FieldViolation{
+			Field:       "petition_id",
+			Description: "invalid UUID format for petition ID",
+		})
+	}
+
+	sourceIDs, err := parseIDs(ctx, "source_ids", req.GetSourceIds(), maxBatchIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	petition, moved, err := s.app.MergePetitions(ctx, newInitiator(initiator), targetID, sourceIDs)
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to merge petitions: %v", err)
+
+		return nil, err
+	}
+
+	logger.Log(ctx).Infof("initiator %s merged %d petitions into %s", initiator.ID, len(sourceIDs), targetID)
+
+	return responses.MergedPetitions(petition, moved), nil
+}
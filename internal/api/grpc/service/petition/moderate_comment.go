@@ -0,0 +1,30 @@
+package petition
+
+import (
+	"context"
+
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/responses"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+)
+
+func (s Service) ModerateComment(ctx context.Context, req *svc.ModerateCommentRequest) (*svc.Comment, error) {
+	initiator := meta.User(ctx)
+
+	commentID, err := parseID(ctx, "comment_id", req.GetCommentId())
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := s.app.ModerateComment(ctx, newInitiator(initiator), commentID, req.GetRemove())
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to moderate comment: %v", err)
+
+		return nil, err
+	}
+
+	logger.Log(ctx).Infof("moderator %s set comment %s to %s", initiator.ID, commentID, comment.Status)
+
+	return responses.Comment(comment), nil
+}
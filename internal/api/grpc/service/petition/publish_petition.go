@@ -0,0 +1,30 @@
+package petition
+
+import (
+	"context"
+
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/responses"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+)
+
+func (s Service) PublishPetition(ctx context.Context, req *svc.PublishPetitionRequest) (*svc.Petition, error) {
+	initiator := meta.User(ctx)
+
+	petitionID, err := parseID(ctx, "petition_id", req.GetPetitionId())
+	if err != nil {
+		return nil, err
+	}
+
+	petition, err := s.app.PublishPetition(ctx, newInitiator(initiator), petitionID)
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to publish petition: %v", err)
+
+		return nil, err
+	}
+
+	logger.Log(ctx).Infof("user %s published petition %s as %s", initiator.ID, petitionID, petition.Status)
+
+	return responses.Petition(petition), nil
+}
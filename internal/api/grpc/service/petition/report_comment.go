@@ -0,0 +1,38 @@
+package petition
+
+import (
+	"context"
+	"strings"
+
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/validation"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+)
+
+func (s Service) ReportComment(ctx context.Context, req *svc.ReportCommentRequest) (*svc.ReportCommentResponse, error) {
+	initiator := meta.User(ctx)
+
+	commentID, err := parseID(ctx, "comment_id", req.GetCommentId())
+	if err != nil {
+		return nil, err
+	}
+
+	reason := strings.TrimSpace(req.GetReason())
+
+	var violations validation.Violations
+	violations.Text("reason", reason, validation.Reason)
+	if err := violations.Err(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := s.app.ReportComment(ctx, newInitiator(initiator), commentID, reason); err != nil {
+		logger.Log(ctx).Errorf("failed to report comment: %v", err)
+
+		return nil, err
+	}
+
+	logger.Log(ctx).Infof("user %s reported comment %s", initiator.ID, commentID)
+
+	return &svc.ReportCommentResponse{}, nil
+}
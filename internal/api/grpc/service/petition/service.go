@@ -4,8 +4,10 @@ import (
 	"context"
 
 	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
 	"github.com/chains-lab/city-petitions-svc/internal/app/entities"
 	"github.com/chains-lab/city-petitions-svc/internal/app/models"
+	"github.com/chains-lab/city-petitions-svc/internal/events"
 	"github.com/chains-lab/city-petitions-svc/internal/pagination"
 	"github.com/google/uuid"
 
@@ -14,16 +16,38 @@ import (
 )
 
 type application interface {
-	CreatePetition(ctx context.Context, cityID, creatorID uuid.UUID, input entities.CreatePetitionInput) (models.Petition, error)
-	GetPetition(ctx context.Context, petitionID uuid.UUID) (models.Petition, error)
-	ApprovePetition(ctx context.Context, petitionID uuid.UUID, reply string) (models.Petition, error)
-	RejectPetition(ctx context.Context, petitionID uuid.UUID, reply string) (models.Petition, error)
+	CreatePetition(ctx context.Context, cityID uuid.UUID, initiator entities.Initiator, input entities.CreatePetitionInput) (models.Petition, error)
+	GetPetition(ctx context.Context, viewer *entities.Initiator, petitionID uuid.UUID) (models.Petition, error)
+	ApprovePetition(ctx context.Context, initiator entities.Initiator, petitionID uuid.UUID, reply string) (models.Petition, error)
+	RejectPetition(ctx context.Context, initiator entities.Initiator, petitionID uuid.UUID, reply string) (models.Petition, error)
 
-	SignPetition(ctx context.Context, initiatorID, petitionID uuid.UUID) (models.PetitionSignature, error)
+	UpdateDraft(ctx context.Context, initiator entities.Initiator, petitionID uuid.UUID, input entities.UpdateDraftInput) (models.Petition, error)
+	PublishPetition(ctx context.Context, initiator entities.Initiator, petitionID uuid.UUID) (models.Petition, error)
+	InviteCoAuthor(ctx context.Context, initiator entities.Initiator, petitionID, userID uuid.UUID) (models.PetitionAuthor, error)
+	AcceptCoAuthorInvitation(ctx context.Context, initiator entities.Initiator, petitionID uuid.UUID) (models.PetitionAuthor, error)
+	DeclineCoAuthorInvitation(ctx context.Context, initiator entities.Initiator, petitionID uuid.UUID) (models.PetitionAuthor, error)
+	RemoveCoAuthor(ctx context.Context, initiator entities.Initiator, petitionID, userID uuid.UUID) error
+
+	CreateComment(ctx context.Context, initiator entities.Initiator, petitionID uuid.UUID, parentID *uuid.UUID, body string) (models.Comment, error)
+	ListComments(
+		ctx context.Context,
+		viewer *entities.Initiator,
+		petitionID uuid.UUID,
+		parentID *uuid.UUID,
+		pag pagination.CursorRequest,
+	) ([]models.Comment, pagination.CursorResponse, error)
+	EditComment(ctx context.Context, initiator entities.Initiator, commentID uuid.UUID, body string) (models.Comment, error)
+	DeleteComment(ctx context.Context, initiator entities.Initiator, commentID uuid.UUID) error
+	ReportComment(ctx context.Context, initiator entities.Initiator, commentID uuid.UUID, reason string) error
+	ModerateComment(ctx context.Context, initiator entities.Initiator, commentID uuid.UUID, remove bool) (models.Comment, error)
+
+	SignPetition(ctx context.Context, initiator entities.Initiator, petitionID uuid.UUID, in entities.SignPetitionInput) (models.PetitionSignature, error)
 	GetSignatureByID(ctx context.Context, userID, petitionID uuid.UUID) (models.PetitionSignature, error)
 
 	GetSignatureByUserIDAndSigID(ctx context.Context, sigID uuid.UUID) (models.PetitionSignature, error)
 
+	WatchPetitions(ctx context.Context, viewer *entities.Initiator, petitionIDs []uuid.UUID) ([]models.PetitionUpdate, *events.Subscription, error)
+
 	ListPetitions(
 		ctx context.Context,
 		filter entities.ListPetitionsFilter,
@@ -33,10 +57,40 @@ type application interface {
 
 	ListSignatures(
 		ctx context.Context,
+		viewer *entities.Initiator,
 		filter entities.ListPetitionsSignFilter,
-		sort entities.ListPetitionsSort,
+		sort entities.ListPetitionsSignSort,
 		pag pagination.Request,
 	) ([]models.PetitionSignature, pagination.Response, error)
+	ListSignatureReasons(ctx context.Context, petitionID uuid.UUID, limit uint64) ([]models.PetitionSignature, error)
+	GetSignatureStats(ctx context.Context, initiator entities.Initiator, petitionID uuid.UUID) (models.SignatureStats, error)
+	VerifySignatureReceipt(ctx context.Context, petitionID, signatureID uuid.UUID, hash []byte) (models.ReceiptVerification, error)
+
+	FindDuplicates(ctx context.Context, cityID uuid.UUID, title string) ([]models.DuplicateCandidate, error)
+
+	ListSignatureFlags(
+		ctx context.Context,
+		initiator entities.Initiator,
+		filter entities.ListSignatureFlagsFilter,
+		pag pagination.Request,
+	) ([]models.SignatureFlag, pagination.Response, error)
+	DismissSignatureFlags(ctx context.Context, initiator entities.Initiator, flagIDs []uuid.UUID) (int, error)
+	InvalidateSignatures(ctx context.Context, initiator entities.Initiator, signatureIDs []uuid.UUID, reason string) (int, error)
+	MergePetitions(ctx context.Context, initiator entities.Initiator, targetID uuid.UUID, sourceIDs []uuid.UUID) (models.Petition, int, error)
+
+	ListContentFlags(
+		ctx context.Context,
+		initiator entities.Initiator,
+		filter entities.ListContentFlagsFilter,
+		pag pagination.Request,
+	) ([]models.ContentFlag, pagination.Response, error)
+	DismissContentFlags(ctx context.Context, initiator entities.Initiator, flagIDs []uuid.UUID) (int, error)
+	ConfirmContentFlags(ctx context.Context, initiator entities.Initiator, flagIDs []uuid.UUID) (int, error)
+
+	GetAccessPolicy(ctx context.Context, initiator entities.Initiator) ([]models.AccessRule, error)
+
+	ExportUserData(ctx context.Context, userID uuid.UUID) (models.UserDataExport, error)
+	EraseUserData(ctx context.Context, userID uuid.UUID) (models.UserDataErasure, error)
 }
 
 type Service struct {
@@ -52,3 +106,13 @@ func NewService(cfg config.Config, app *app.App) Service {
 		cfg: cfg,
 	}
 }
+
+// newInitiator describes the caller authenticated by the user token interceptor.
+func newInitiator(user *meta.UserData) entities.Initiator {
+	return entities.Initiator{
+		ID:        user.ID,
+		SessionID: user.SessionID,
+		Verified:  user.Verified,
+		Role:      user.Role,
+	}
+}
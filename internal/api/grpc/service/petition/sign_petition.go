@@ -2,11 +2,15 @@ package petition
 
 import (
 	"context"
+	"strings"
 
 	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
 	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
 	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/problems"
 	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/responses"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/validation"
+	"github.com/chains-lab/city-petitions-svc/internal/app/entities"
+	"github.com/chains-lab/city-petitions-svc/internal/constant/enum"
 	"github.com/chains-lab/city-petitions-svc/internal/logger"
 	"github.com/google/uuid"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -27,7 +31,30 @@ func (s Service) SignPetition(ctx context.Context, req *svc.SignPetitionRequest)
 		})
 	}
 
-	sign, err := s.app.SignPetition(ctx, initiator.ID, petitionId)
+	var (
+		input      entities.SignPetitionInput
+		violations validation.Violations
+	)
+
+	if req.Reason != nil {
+		reason := strings.TrimSpace(req.GetReason())
+		violations.Text("reason", reason, validation.SignatureReason)
+		input.Reason = &reason
+	}
+
+	if req.GetVisibility() != "" {
+		visibility, err := enum.ParseSignatureVisibility(req.GetVisibility())
+		if err != nil {
+			violations.Add("visibility", err.Error())
+		}
+		input.Visibility = visibility
+	}
+
+	if err := violations.Err(ctx); err != nil {
+		return nil, err
+	}
+
+	sign, err := s.app.SignPetition(ctx, newInitiator(initiator), petitionId, input)
 	if err != nil {
 		logger.Log(ctx).Errorf("failed to sign petition: %v", err)
 
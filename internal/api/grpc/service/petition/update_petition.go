@@ -0,0 +1,51 @@
+package petition
+
+import (
+	"context"
+	"strings"
+
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/responses"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/validation"
+	"github.com/chains-lab/city-petitions-svc/internal/app/entities"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+)
+
+func (s Service) UpdatePetition(ctx context.Context, req *svc.UpdatePetitionRequest) (*svc.Petition, error) {
+	initiator := meta.User(ctx)
+
+	petitionID, err := parseID(ctx, "petition_id", req.GetPetitionId())
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		input      entities.UpdateDraftInput
+		violations validation.Violations
+	)
+	if req.Title != nil {
+		title := strings.TrimSpace(req.GetTitle())
+		violations.Text("title", title, validation.Title)
+		input.Title = &title
+	}
+	if req.Description != nil {
+		description := strings.TrimSpace(req.GetDescription())
+		violations.Text("description", description, validation.Description)
+		input.Description = &description
+	}
+	if err := violations.Err(ctx); err != nil {
+		return nil, err
+	}
+
+	petition, err := s.app.UpdateDraft(ctx, newInitiator(initiator), petitionID, input)
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to update petition: %v", err)
+
+		return nil, err
+	}
+
+	logger.Log(ctx).Infof("user %s updated draft %s", initiator.ID, petitionID)
+
+	return responses.Petition(petition), nil
+}
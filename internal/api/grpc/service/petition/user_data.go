@@ -0,0 +1,52 @@
+package petition
+
+import (
+	"context"
+	"encoding/json"
+
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/responses"
+	"github.com/chains-lab/city-petitions-svc/internal/errx"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+)
+
+func (s Service) ExportUserData(ctx context.Context, req *svc.ExportUserDataRequest) (*svc.UserDataExport, error) {
+	userID, err := parseID(ctx, "user_id", req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	export, err := s.app.ExportUserData(ctx, userID)
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to export user data: %v", err)
+
+		return nil, err
+	}
+
+	bundle, err := json.Marshal(export)
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to encode user data export: %v", err)
+
+		return nil, errx.RaiseInternal(ctx, err)
+	}
+
+	logger.Log(ctx).Infof("exported data of user %s", userID)
+
+	return responses.UserDataExport(export, bundle), nil
+}
+
+func (s Service) EraseUserData(ctx context.Context, req *svc.EraseUserDataRequest) (*svc.UserDataErasure, error) {
+	userID, err := parseID(ctx, "user_id", req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	erasure, err := s.app.EraseUserData(ctx, userID)
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to erase user data: %v", err)
+
+		return nil, err
+	}
+
+	return responses.UserDataErasure(erasure), nil
+}
@@ -0,0 +1,41 @@
+package petition
+
+import (
+	"context"
+	"encoding/hex"
+
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/responses"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/validation"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+)
+
+func (s Service) VerifySignatureReceipt(ctx context.Context, req *svc.VerifySignatureReceiptRequest) (*svc.SignatureReceiptVerification, error) {
+	petitionID, err := parseID(ctx, "petition_id", req.GetPetitionId())
+	if err != nil {
+		return nil, err
+	}
+
+	signatureID, err := parseID(ctx, "signature_id", req.GetSignatureId())
+	if err != nil {
+		return nil, err
+	}
+
+	var violations validation.Violations
+	hash, err := hex.DecodeString(req.GetHash())
+	if err != nil || len(hash) == 0 {
+		violations.Add("hash", "must be the hex-encoded hash of the receipt")
+	}
+	if err := violations.Err(ctx); err != nil {
+		return nil, err
+	}
+
+	res, err := s.app.VerifySignatureReceipt(ctx, petitionID, signatureID, hash)
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to verify signature receipt: %v", err)
+
+		return nil, err
+	}
+
+	return responses.ReceiptVerification(res), nil
+}
@@ -0,0 +1,54 @@
+package petition
+
+import (
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/responses"
+	"github.com/chains-lab/city-petitions-svc/internal/app/entities"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+)
+
+const maxWatchedPetitions = 100
+
+func (s Service) WatchPetition(req *svc.WatchPetitionRequest, stream svc.PetitionService_WatchPetitionServer) error {
+	ctx := stream.Context()
+
+	petitionIDs, err := parseIDs(ctx, "petition_ids", req.GetPetitionIds(), maxWatchedPetitions)
+	if err != nil {
+		return err
+	}
+
+	// the stream is public, a supplied user token lets authors and moderators see unpublished ones
+	var viewer *entities.Initiator
+	if user := meta.User(ctx); user != nil {
+		initiator := newInitiator(user)
+		viewer = &initiator
+	}
+
+	snapshot, sub, err := s.app.WatchPetitions(ctx, viewer, petitionIDs)
+	if err != nil {
+		logger.Log(ctx).Errorf("failed to watch petitions: %v", err)
+
+		return err
+	}
+	defer sub.Close()
+
+	for _, update := range snapshot {
+		if err := stream.Send(responses.PetitionUpdate(update)); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sub.Ready():
+			for _, update := range sub.Drain() {
+				if err := stream.Send(responses.PetitionUpdate(update)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
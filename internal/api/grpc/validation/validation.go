@@ -0,0 +1,108 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/problems"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// TextRule describes an acceptable free text field.
+type TextRule struct {
+	MinLength int // in characters; 0 allows an empty value
+	MaxLength int // in characters, matching the VARCHAR limit of the column
+	// Multiline allows line breaks and tabs; other control characters are never allowed.
+	Multiline bool
+	// MaxURLs bounds the number of links; a negative value allows any number.
+	MaxURLs int
+}
+
+var (
+	Title       = TextRule{MinLength: 1, MaxLength: 255, MaxURLs: 0}
+	Description = TextRule{MinLength: 1, MaxLength: 8192, Multiline: true, MaxURLs: 5}
+	Reply       = TextRule{MinLength: 1, MaxLength: 8192, Multiline: true, MaxURLs: 10}
+	Reason      = TextRule{MinLength: 1, MaxLength: 1024, Multiline: true, MaxURLs: -1}
+	Comment     = TextRule{MinLength: 1, MaxLength: 4096, Multiline: true, MaxURLs: 3}
+	// SignatureReason is the reason a signer gives for supporting a petition.
+	SignatureReason = TextRule{MinLength: 1, MaxLength: 500, Multiline: true, MaxURLs: 0}
+)
+
+var urlPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+
+// Violations collects field violations of one request.
+type Violations []*errdetails.BadRequest_FieldViolation
+
+func (v *Violations) Add(field, description string) {
+	*v = append(*v, &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: description,
+	})
+}
+
+// Text checks value of field against rule. It reports at most one violation per field.
+func (v *Violations) Text(field, value string, rule TextRule) {
+	if !utf8.ValidString(value) {
+		v.Add(field, "must be valid UTF-8")
+		return
+	}
+
+	length := utf8.RuneCountInString(value)
+	if rule.MinLength > 0 && strings.TrimSpace(value) == "" {
+		v.Add(field, "must not be empty or whitespace only")
+		return
+	}
+	if length < rule.MinLength || length > rule.MaxLength {
+		v.Add(field, fmt.Sprintf("must be between %d and %d characters", rule.MinLength, rule.MaxLength))
+		return
+	}
+
+	for _, r := range value {
+		if disallowed(r, rule.Multiline) {
+			v.Add(field, fmt.Sprintf("must not contain control character %U", r))
+			return
+		}
+	}
+
+	if rule.MaxURLs >= 0 {
+		if n := len(urlPattern.FindAllStringIndex(value, -1)); n > rule.MaxURLs {
+			v.Add(field, fmt.Sprintf("must contain at most %d links, found %d", rule.MaxURLs, n))
+			return
+		}
+	}
+}
+
+// Err returns an InvalidArgument error listing all violations, or nil when there are none.
+func (v Violations) Err(ctx context.Context) error {
+	if len(v) == 0 {
+		return nil
+	}
+
+	fields := make([]string, 0, len(v))
+	for _, violation := range v {
+		fields = append(fields, violation.GetField())
+	}
+
+	return problems.InvalidArgumentError(ctx, fmt.Sprintf("%s is invalid", strings.Join(fields, ", ")), v...)
+}
+
+// disallowed reports control characters and the invisible formatting characters
+// that reorder or hide text, such as bidirectional overrides and zero width spaces.
+func disallowed(r rune, multiline bool) bool {
+	switch r {
+	case '\n', '\r', '\t':
+		return !multiline
+	case '\u200B', '\u2028', '\u2029', '\uFEFF':
+		return true
+	}
+
+	if r >= '\u202A' && r <= '\u202E' || r >= '\u2066' && r <= '\u2069' {
+		return true
+	}
+
+	return unicode.IsControl(r)
+}
@@ -0,0 +1,252 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/problems"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const maxBodySize = 1 << 20
+
+var marshaler = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+
+type gateway struct {
+	client svc.PetitionServiceClient
+	log    logger.Logger
+}
+
+func newGateway(client svc.PetitionServiceClient, log logger.Logger) *gateway {
+	return &gateway{
+		client: client,
+		log:    log,
+	}
+}
+
+func (g *gateway) register(mux *http.ServeMux) {
+	for _, rt := range routes {
+		mux.HandleFunc(rt.method+" "+rt.path, g.handle(rt))
+	}
+}
+
+func (g *gateway) handle(rt route) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, requestID := outgoingContext(r)
+		w.Header().Set("X-Request-Id", requestID.String())
+
+		req, err := decodeRequest(ctx, r, rt)
+		if err != nil {
+			g.writeError(w, r, err)
+			return
+		}
+
+		if rt.stream != nil {
+			g.serveStream(ctx, w, r, rt, req)
+			return
+		}
+
+		resp, err := rt.unary(ctx, g.client, req)
+		if err != nil {
+			g.writeError(w, r, err)
+			return
+		}
+
+		g.writeMessage(w, rt.status, resp)
+	}
+}
+
+// serveStream relays a server stream as server-sent events.
+func (g *gateway) serveStream(ctx context.Context, w http.ResponseWriter, r *http.Request, rt route, req proto.Message) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		g.writeError(w, r, fmt.Errorf("response writer does not support streaming"))
+		return
+	}
+
+	recv, err := rt.stream(ctx, g.client, req)
+	if err != nil {
+		g.writeError(w, r, err)
+		return
+	}
+
+	// errors of a server stream only show up on the first receive,
+	// so it is read before committing to an event stream response
+	msg, err := recv()
+	if err != nil {
+		g.writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(rt.status)
+
+	for {
+		body, err := marshaler.Marshal(msg)
+		if err != nil {
+			g.log.WithError(err).Error("failed to marshal stream message")
+			return
+		}
+
+		if _, err := fmt.Fprintf(w, "event: update\ndata: %s\n\n", body); err != nil {
+			return
+		}
+		flusher.Flush()
+
+		msg, err = recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) || ctx.Err() != nil {
+				return
+			}
+
+			st, _ := status.FromError(err)
+			problem, _ := json.Marshal(problems.HTTPProblem(st, r.URL.Path))
+			_, _ = fmt.Fprintf(w, "event: error\ndata: %s\n\n", problem)
+			flusher.Flush()
+
+			return
+		}
+	}
+}
+
+func (g *gateway) writeMessage(w http.ResponseWriter, code int, msg proto.Message) {
+	body, err := marshaler.Marshal(msg)
+	if err != nil {
+		g.log.WithError(err).Error("failed to marshal response")
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(body)
+}
+
+func (g *gateway) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	st, ok := status.FromError(err)
+	if !ok {
+		g.log.WithError(err).Error("gateway error")
+		st, _ = status.FromError(problems.InternalError(r.Context()))
+	}
+
+	problem := problems.HTTPProblem(st, r.URL.Path)
+
+	if problem.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.FormatInt(problem.RetryAfter, 10))
+	}
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+	_ = json.NewEncoder(w).Encode(problem)
+}
+
+// decodeRequest assembles the proto JSON of the request from body, path and query,
+// then lets protojson map it onto the request message.
+func decodeRequest(ctx context.Context, r *http.Request, rt route) (proto.Message, error) {
+	fields := map[string]interface{}{}
+
+	if r.Method != http.MethodGet && r.ContentLength != 0 {
+		dec := json.NewDecoder(io.LimitReader(r.Body, maxBodySize))
+		if err := dec.Decode(&fields); err != nil && !errors.Is(err, io.EOF) {
+			return nil, problems.InvalidArgumentError(ctx, "request body is invalid", &errdetails.BadRequest_FieldViolation{
+				Field:       "body",
+				Description: fmt.Sprintf("invalid JSON: %v", err),
+			})
+		}
+	}
+
+	query := r.URL.Query()
+	for _, p := range rt.params {
+		var raw []string
+		switch p.in {
+		case "path":
+			raw = []string{r.PathValue(p.name)}
+		case "query":
+			raw = query[p.name]
+		default:
+			continue
+		}
+		if len(raw) == 0 || raw[0] == "" {
+			continue
+		}
+
+		if err := p.apply(fields, raw); err != nil {
+			return nil, problems.InvalidArgumentError(ctx, fmt.Sprintf("%s is invalid", p.name), &errdetails.BadRequest_FieldViolation{
+				Field:       p.name,
+				Description: err.Error(),
+			})
+		}
+	}
+
+	body, err := json.Marshal(fields)
+	if err != nil {
+		return nil, problems.InternalError(ctx)
+	}
+
+	req := rt.newRequest()
+	if err := protojson.Unmarshal(body, req); err != nil {
+		return nil, problems.InvalidArgumentError(ctx, "request is invalid", &errdetails.BadRequest_FieldViolation{
+			Field:       "body",
+			Description: err.Error(),
+		})
+	}
+
+	return req, nil
+}
+
+func (p param) apply(fields map[string]interface{}, raw []string) error {
+	switch p.kind {
+	case kindString:
+		setField(fields, p.field, raw[0])
+	case kindStringList:
+		setField(fields, p.field, raw)
+	case kindBool:
+		v, err := strconv.ParseBool(raw[0])
+		if err != nil {
+			return fmt.Errorf("must be true or false")
+		}
+		setField(fields, p.field, v)
+	case kindUint:
+		v, err := strconv.ParseUint(raw[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("must be a non-negative integer")
+		}
+		// protojson expects 64-bit integers as strings
+		setField(fields, p.field, strconv.FormatUint(v, 10))
+	case kindFlag:
+		field, ok := p.flags[raw[0]]
+		if !ok {
+			return fmt.Errorf("unknown value '%s'", raw[0])
+		}
+		if field != "" {
+			setField(fields, field, true)
+		}
+	}
+
+	return nil
+}
+
+func setField(fields map[string]interface{}, path string, value interface{}) {
+	parts := strings.Split(path, ".")
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := fields[part].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			fields[part] = next
+		}
+		fields = next
+	}
+
+	fields[parts[len(parts)-1]] = value
+}
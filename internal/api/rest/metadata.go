@@ -0,0 +1,46 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc/metadata"
+)
+
+// forwardedHeaders are copied from the HTTP request into outgoing gRPC metadata.
+var forwardedHeaders = []string{
+	"x-request-id",
+	"x-user-token",
+	"x-service-token",
+	"x-idempotency-key",
+}
+
+// outgoingContext builds the gRPC call context for r.
+// A request ID is generated when the client did not send one, and the caller's trace context is passed on.
+func outgoingContext(r *http.Request) (context.Context, uuid.UUID) {
+	md := metadata.MD{}
+	for _, h := range forwardedHeaders {
+		if v := r.Header.Get(h); v != "" {
+			md.Set(h, v)
+		}
+	}
+
+	requestID, err := uuid.Parse(r.Header.Get("x-request-id"))
+	if err != nil {
+		requestID = uuid.New()
+		md.Set("x-request-id", requestID.String())
+	}
+
+	propagator := otel.GetTextMapPropagator()
+	ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	propagator.Inject(ctx, tracing.MetadataCarrier(md))
+
+	ctx = context.WithValue(ctx, meta.RequestIDCtxKey, requestID)
+
+	return metadata.NewOutgoingContext(ctx, md), requestID
+}
@@ -0,0 +1,354 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chains-lab/city-petitions-svc/internal/constant"
+)
+
+type object = map[string]interface{}
+
+// schemas describes the JSON shape of the proto responses, using proto field names.
+var schemas = object{
+	"Petition": objectSchema(object{
+		"id":          stringSchema("uuid"),
+		"city_id":     stringSchema("uuid"),
+		"creator_id":  stringSchema("uuid"),
+		"title":       stringSchema(""),
+		"description": stringSchema(""),
+		"status":      stringSchema(""),
+		"signatures":  object{"type": "integer"},
+		"goal":        object{"type": "integer"},
+		"reply":       stringSchema(""),
+		"end_date":    stringSchema("date-time"),
+		"created_at":  stringSchema("date-time"),
+		"updated_at":  stringSchema("date-time"),
+		"merged_into": stringSchema("uuid"),
+		"co_authors":  object{"type": "array", "items": ref("PetitionAuthor")},
+	}),
+	"PetitionAuthor": objectSchema(object{
+		"petition_id":  stringSchema("uuid"),
+		"user_id":      stringSchema("uuid"),
+		"status":       stringSchema(""),
+		"invited_by":   stringSchema("uuid"),
+		"responded_at": stringSchema("date-time"),
+		"created_at":   stringSchema("date-time"),
+	}),
+	"RemoveCoAuthorResponse": objectSchema(object{}),
+	"Comment": objectSchema(object{
+		"id":          stringSchema("uuid"),
+		"petition_id": stringSchema("uuid"),
+		"parent_id":   stringSchema("uuid"),
+		"depth":       object{"type": "integer"},
+		"replies":     object{"type": "integer"},
+		"author_id":   stringSchema("uuid"),
+		"body":        stringSchema(""),
+		"status":      stringSchema(""),
+		"deleted":     object{"type": "boolean"},
+		"edited_at":   stringSchema("date-time"),
+		"created_at":  stringSchema("date-time"),
+	}),
+	"CommentList": objectSchema(object{
+		"comments":    object{"type": "array", "items": ref("Comment")},
+		"next_cursor": stringSchema(""),
+	}),
+	"DeleteCommentResponse": objectSchema(object{}),
+	"ReportCommentResponse": objectSchema(object{}),
+	"Signature": objectSchema(object{
+		"id":          stringSchema("uuid"),
+		"petition_id": stringSchema("uuid"),
+		"user_id":     stringSchema("uuid"),
+		"reason":      stringSchema(""),
+		"visibility":  stringSchema(""),
+		"created_at":  stringSchema("date-time"),
+		"receipt":     ref("SignatureReceipt"),
+	}),
+	"SignatureReceipt": objectSchema(object{
+		"petition_id":  stringSchema("uuid"),
+		"signature_id": stringSchema("uuid"),
+		"seq":          stringSchema("int64"),
+		"user_hash":    stringSchema(""),
+		"prev_hash":    stringSchema(""),
+		"hash":         stringSchema(""),
+		"created_at":   stringSchema("date-time"),
+	}),
+	"SignatureChainHead": objectSchema(object{
+		"seq":  stringSchema("int64"),
+		"hash": stringSchema(""),
+	}),
+	"SignatureReceiptVerification": objectSchema(object{
+		"valid":   object{"type": "boolean"},
+		"problem": stringSchema(""),
+		"entry":   ref("SignatureReceipt"),
+		"head":    ref("SignatureChainHead"),
+	}),
+	"SignatureStats": objectSchema(object{
+		"petition_id": stringSchema("uuid"),
+		"total":       object{"type": "integer"},
+		"verified":    object{"type": "integer"},
+		"invalidated": object{"type": "integer"},
+		"public":      object{"type": "integer"},
+		"anonymous":   object{"type": "integer"},
+		"private":     object{"type": "integer"},
+		"with_reason": object{"type": "integer"},
+	}),
+	"UserDataExport": objectSchema(object{
+		"user_id":      stringSchema("uuid"),
+		"bundle":       stringSchema(""),
+		"generated_at": stringSchema("date-time"),
+	}),
+	"UserDataErasure": objectSchema(object{
+		"user_id":         stringSchema("uuid"),
+		"petitions":       object{"type": "integer"},
+		"signatures":      object{"type": "integer"},
+		"co_authorships":  object{"type": "integer"},
+		"comments":        object{"type": "integer"},
+		"comment_reports": object{"type": "integer"},
+		"signature_flags": object{"type": "integer"},
+		"content_flags":   object{"type": "integer"},
+	}),
+	"SignatureReasonList": objectSchema(object{
+		"reasons": object{"type": "array", "items": ref("Signature")},
+	}),
+	"PetitionUpdate": objectSchema(object{
+		"petition_id": stringSchema("uuid"),
+		"signatures":  object{"type": "integer"},
+		"status":      stringSchema(""),
+		"updated_at":  stringSchema("date-time"),
+		"merged_into": stringSchema("uuid"),
+	}),
+	"Pagination": objectSchema(object{
+		"page":  stringSchema("uint64"),
+		"size":  stringSchema("uint64"),
+		"total": stringSchema("uint64"),
+	}),
+	"PetitionList": objectSchema(object{
+		"petitions":  object{"type": "array", "items": ref("Petition")},
+		"pagination": ref("Pagination"),
+	}),
+	"SignatureList": objectSchema(object{
+		"signatures": object{"type": "array", "items": ref("Signature")},
+		"pagination": ref("Pagination"),
+	}),
+	"SignatureFlag": objectSchema(object{
+		"id":           stringSchema("uuid"),
+		"signature_id": stringSchema("uuid"),
+		"petition_id":  stringSchema("uuid"),
+		"reason":       stringSchema(""),
+		"details":      stringSchema(""),
+		"status":       stringSchema(""),
+		"reviewed_by":  stringSchema("uuid"),
+		"reviewed_at":  stringSchema("date-time"),
+		"created_at":   stringSchema("date-time"),
+	}),
+	"SignatureFlagList": objectSchema(object{
+		"flags":      object{"type": "array", "items": ref("SignatureFlag")},
+		"pagination": ref("Pagination"),
+	}),
+	"DismissSignatureFlagsResponse": objectSchema(object{
+		"dismissed": object{"type": "integer"},
+	}),
+	"InvalidateSignaturesResponse": objectSchema(object{
+		"invalidated": object{"type": "integer"},
+	}),
+	"MergePetitionsResponse": objectSchema(object{
+		"petition": ref("Petition"),
+		"moved":    object{"type": "integer"},
+	}),
+	"ContentFlag": objectSchema(object{
+		"id":          stringSchema("uuid"),
+		"petition_id": stringSchema("uuid"),
+		"field":       stringSchema(""),
+		"filter":      stringSchema(""),
+		"action":      stringSchema(""),
+		"details":     stringSchema(""),
+		"status":      stringSchema(""),
+		"reviewed_by": stringSchema("uuid"),
+		"reviewed_at": stringSchema("date-time"),
+		"created_at":  stringSchema("date-time"),
+	}),
+	"ContentFlagList": objectSchema(object{
+		"flags":      object{"type": "array", "items": ref("ContentFlag")},
+		"pagination": ref("Pagination"),
+	}),
+	"DismissContentFlagsResponse": objectSchema(object{
+		"dismissed": object{"type": "integer"},
+	}),
+	"ConfirmContentFlagsResponse": objectSchema(object{
+		"confirmed": object{"type": "integer"},
+	}),
+	"DuplicateCandidate": objectSchema(object{
+		"petition":   ref("Petition"),
+		"similarity": object{"type": "number"},
+	}),
+	"CheckDuplicatesResponse": objectSchema(object{
+		"candidates": object{"type": "array", "items": ref("DuplicateCandidate")},
+	}),
+	"AccessRule": objectSchema(object{
+		"role":       stringSchema(""),
+		"user_id":    stringSchema("uuid"),
+		"permission": stringSchema(""),
+		"city_ids":   object{"type": "array", "items": stringSchema("uuid")},
+	}),
+	"AccessPolicy": objectSchema(object{
+		"rules": object{"type": "array", "items": ref("AccessRule")},
+	}),
+	"Problem": objectSchema(object{
+		"type":       stringSchema("uri"),
+		"title":      stringSchema(""),
+		"status":     object{"type": "integer"},
+		"detail":     stringSchema(""),
+		"instance":   stringSchema(""),
+		"reason":     stringSchema(""),
+		"domain":     stringSchema(""),
+		"metadata":   object{"type": "object", "additionalProperties": stringSchema("")},
+		"request_id": stringSchema("uuid"),
+		"invalid_params": object{"type": "array", "items": objectSchema(object{
+			"name":   stringSchema(""),
+			"reason": stringSchema(""),
+		})},
+		"violations": object{"type": "array", "items": objectSchema(object{
+			"type":        stringSchema(""),
+			"subject":     stringSchema(""),
+			"description": stringSchema(""),
+		})},
+		"retry_after": object{"type": "integer"},
+	}),
+}
+
+// openAPIDocument generates an OpenAPI 3 document from the route table.
+func openAPIDocument(routes []route) ([]byte, error) {
+	paths := object{}
+	for _, rt := range routes {
+		item, ok := paths[rt.path].(object)
+		if !ok {
+			item = object{}
+			paths[rt.path] = item
+		}
+
+		item[strings.ToLower(rt.method)] = operation(rt)
+	}
+
+	return json.MarshalIndent(object{
+		"openapi": "3.0.3",
+		"info": object{
+			"title":   constant.ServiceName,
+			"version": "v1",
+		},
+		"paths": paths,
+		"components": object{
+			"schemas": schemas,
+			"securitySchemes": object{
+				"userToken":    object{"type": "apiKey", "in": "header", "name": "x-user-token"},
+				"serviceToken": object{"type": "apiKey", "in": "header", "name": "x-service-token"},
+			},
+		},
+	}, "", "  ")
+}
+
+func operation(rt route) object {
+	params := []object{{
+		"name":        "x-request-id",
+		"in":          "header",
+		"description": "request UUID, generated when missing",
+		"schema":      stringSchema("uuid"),
+	}}
+	bodyProps := object{}
+	var bodyRequired []string
+
+	for _, p := range rt.params {
+		if p.in == "body" {
+			bodyProps[p.name] = p.schema()
+			if p.required {
+				bodyRequired = append(bodyRequired, p.name)
+			}
+			continue
+		}
+
+		param := object{
+			"name":     p.name,
+			"in":       p.in,
+			"required": p.required || p.in == "path",
+			"schema":   p.schema(),
+		}
+		if p.description != "" {
+			param["description"] = p.description
+		}
+		if p.kind == kindStringList {
+			param["explode"] = true
+		}
+		params = append(params, param)
+	}
+
+	content := object{"application/json": object{"schema": ref(rt.response)}}
+	if rt.stream != nil {
+		content = object{"text/event-stream": object{"schema": ref(rt.response)}}
+	}
+
+	op := object{
+		"operationId": rt.operationID,
+		"summary":     rt.summary,
+		"parameters":  params,
+		"responses": object{
+			strconv.Itoa(rt.status): object{
+				"description": http.StatusText(rt.status),
+				"content":     content,
+			},
+			"default": object{
+				"description": "error",
+				"content":     object{"application/problem+json": object{"schema": ref("Problem")}},
+			},
+		},
+	}
+
+	if len(bodyProps) > 0 {
+		sort.Strings(bodyRequired)
+		schema := objectSchema(bodyProps)
+		schema["required"] = bodyRequired
+		op["requestBody"] = object{
+			"required": true,
+			"content":  object{"application/json": object{"schema": schema}},
+		}
+	}
+
+	return op
+}
+
+func (p param) schema() object {
+	switch p.kind {
+	case kindBool:
+		return object{"type": "boolean"}
+	case kindUint:
+		return object{"type": "integer", "minimum": 0}
+	case kindStringList:
+		return object{"type": "array", "items": stringSchema("")}
+	case kindFlag:
+		values := make([]string, 0, len(p.flags))
+		for v := range p.flags {
+			values = append(values, v)
+		}
+		sort.Strings(values)
+		return object{"type": "string", "enum": values}
+	default:
+		return stringSchema("")
+	}
+}
+
+func objectSchema(props object) object {
+	return object{"type": "object", "properties": props}
+}
+
+func stringSchema(format string) object {
+	if format == "" {
+		return object{"type": "string"}
+	}
+	return object{"type": "string", "format": format}
+}
+
+func ref(name string) object {
+	return object{"$ref": "#/components/schemas/" + name}
+}
@@ -0,0 +1,89 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	petionProto "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"github.com/chains-lab/city-petitions-svc/internal/config"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+// Run serves the REST/JSON gateway. Every route is proxied to the local gRPC server,
+// so requests pass the same interceptor chain as native gRPC calls.
+func Run(ctx context.Context, cfg config.Config, log logger.Logger) error {
+	log.Info("HTTP gateway is starting...")
+
+	conn, err := grpc.NewClient(grpcTarget(cfg.Server.Port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to create gRPC client: %w", err)
+	}
+	defer conn.Close()
+
+	gw := newGateway(petionProto.NewPetitionServiceClient(conn), log)
+
+	mux := http.NewServeMux()
+	gw.register(mux)
+
+	if cfg.Swagger.Enabled {
+		doc, err := openAPIDocument(routes)
+		if err != nil {
+			return fmt.Errorf("failed to build OpenAPI document: %w", err)
+		}
+
+		mux.HandleFunc("GET "+cfg.Swagger.URL, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write(doc)
+		})
+	}
+
+	srv := &http.Server{
+		Addr:              cfg.Gateway.Port,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	lis, err := net.Listen("tcp", cfg.Gateway.Port)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	log.Infof("HTTP gateway listening on %s", lis.Addr())
+
+	serveErrCh := make(chan error, 1)
+	go func() {
+		serveErrCh <- srv.Serve(lis)
+	}()
+
+	select {
+	case <-ctx.Done():
+		log.Info("shutting down HTTP gateway …")
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		return srv.Shutdown(shutdownCtx)
+	case err := <-serveErrCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("HTTP Serve() exited: %w", err)
+	}
+}
+
+// grpcTarget turns a listen address like ":8002" into a dialable one.
+func grpcTarget(port string) string {
+	host, p, err := net.SplitHostPort(port)
+	if err != nil || host == "" || host == "0.0.0.0" || host == "::" {
+		return net.JoinHostPort("localhost", p)
+	}
+
+	return net.JoinHostPort(host, p)
+}
@@ -0,0 +1,620 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	svc "github.com/chains-lab/city-petitions-proto/gen/go/svc/petition"
+	"google.golang.org/protobuf/proto"
+)
+
+type paramKind int
+
+const (
+	kindString paramKind = iota
+	kindBool
+	kindUint
+	kindStringList
+	// kindFlag selects one boolean field named by the value, e.g. sort=oldest sets "oldest": true.
+	kindFlag
+)
+
+type param struct {
+	name        string
+	in          string // "path", "query", "body" or "header"; headers are only documented, forwardedHeaders passes them on
+	field       string // dotted proto JSON path of the request field
+	kind        paramKind
+	flags       map[string]string // kindFlag: value -> field, "" leaves the default
+	required    bool
+	description string
+}
+
+type recvFunc func() (proto.Message, error)
+
+type route struct {
+	method      string
+	path        string
+	operationID string
+	summary     string
+	params      []param
+	status      int
+	response    string // component schema name of the response
+
+	newRequest func() proto.Message
+	unary      func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error)
+	stream     func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (recvFunc, error)
+}
+
+var (
+	petitionIDPath = param{name: "petition_id", in: "path", field: "petition_id", required: true, description: "petition ID"}
+	commentIDPath  = param{name: "comment_id", in: "path", field: "comment_id", required: true, description: "comment ID"}
+
+	pageQuery = param{name: "page", in: "query", field: "pag.page", kind: kindUint, description: "page number, starting from 1"}
+	sizeQuery = param{name: "size", in: "query", field: "pag.size", kind: kindUint, description: "page size"}
+
+	idempotencyKeyHeader = param{name: "x-idempotency-key", in: "header", description: "retries with the same key replay the first response"}
+)
+
+var routes = []route{
+	{
+		method:      http.MethodPost,
+		path:        "/v1/petitions",
+		operationID: "CreatePetition",
+		summary:     "Create a petition",
+		params: []param{
+			{name: "city_id", in: "body", field: "city_id", required: true},
+			{name: "title", in: "body", field: "title", required: true},
+			{name: "description", in: "body", field: "description", required: true},
+			{name: "force", in: "body", field: "force", kind: kindBool, description: "create even when similar open petitions exist"},
+			{name: "draft", in: "body", field: "draft", kind: kindBool, description: "keep the petition unpublished to invite co-authors first"},
+			idempotencyKeyHeader,
+		},
+		status:     http.StatusCreated,
+		response:   "Petition",
+		newRequest: func() proto.Message { return &svc.CreatePetitionRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.CreatePetition(ctx, req.(*svc.CreatePetitionRequest))
+		},
+	},
+	{
+		method:      http.MethodGet,
+		path:        "/v1/petitions",
+		operationID: "ListPetitions",
+		summary:     "List petitions",
+		params: []param{
+			{name: "city_id", in: "query", field: "filters.city_id"},
+			{name: "creator_id", in: "query", field: "filters.creator_id"},
+			{name: "title_like", in: "query", field: "filters.title_like"},
+			{name: "rejected", in: "query", field: "filters.rejected", kind: kindBool},
+			{name: "approved", in: "query", field: "filters.approved", kind: kindBool},
+			{name: "available", in: "query", field: "filters.available", kind: kindBool},
+			{name: "expired", in: "query", field: "filters.expired", kind: kindBool},
+			{name: "sort", in: "query", kind: kindFlag, flags: map[string]string{
+				"newest":           "",
+				"oldest":           "oldest",
+				"least_signatures": "least_signatures",
+				"most_signatures":  "most_signatures",
+			}},
+			pageQuery,
+			sizeQuery,
+		},
+		status:     http.StatusOK,
+		response:   "PetitionList",
+		newRequest: func() proto.Message { return &svc.ListPetitionsRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.ListPetitions(ctx, req.(*svc.ListPetitionsRequest))
+		},
+	},
+	{
+		method:      http.MethodGet,
+		path:        "/v1/petitions/duplicates",
+		operationID: "CheckDuplicates",
+		summary:     "Find open petitions similar to a new one before submitting it",
+		params: []param{
+			{name: "city_id", in: "query", field: "city_id", required: true},
+			{name: "title", in: "query", field: "title", required: true},
+		},
+		status:     http.StatusOK,
+		response:   "CheckDuplicatesResponse",
+		newRequest: func() proto.Message { return &svc.CheckDuplicatesRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.CheckDuplicates(ctx, req.(*svc.CheckDuplicatesRequest))
+		},
+	},
+	{
+		method:      http.MethodGet,
+		path:        "/v1/petitions/watch",
+		operationID: "WatchPetition",
+		summary:     "Stream signature count and status changes as server-sent events",
+		params: []param{
+			{name: "petition_id", in: "query", field: "petition_ids", kind: kindStringList, required: true, description: "repeat to watch several petitions"},
+		},
+		status:     http.StatusOK,
+		response:   "PetitionUpdate",
+		newRequest: func() proto.Message { return &svc.WatchPetitionRequest{} },
+		stream: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (recvFunc, error) {
+			stream, err := c.WatchPetition(ctx, req.(*svc.WatchPetitionRequest))
+			if err != nil {
+				return nil, err
+			}
+
+			return func() (proto.Message, error) { return stream.Recv() }, nil
+		},
+	},
+	{
+		method:      http.MethodGet,
+		path:        "/v1/petitions/{petition_id}",
+		operationID: "GetPetition",
+		summary:     "Get a petition",
+		params:      []param{petitionIDPath},
+		status:      http.StatusOK,
+		response:    "Petition",
+		newRequest:  func() proto.Message { return &svc.GetPetitionRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.GetPetition(ctx, req.(*svc.GetPetitionRequest))
+		},
+	},
+	{
+		method:      http.MethodPost,
+		path:        "/v1/petitions/{petition_id}/approve",
+		operationID: "ApprovePetition",
+		summary:     "Approve a petition with an official reply",
+		params: []param{
+			petitionIDPath,
+			{name: "reply", in: "body", field: "reply", required: true},
+			idempotencyKeyHeader,
+		},
+		status:     http.StatusOK,
+		response:   "Petition",
+		newRequest: func() proto.Message { return &svc.ApprovePetitionRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.ApprovePetition(ctx, req.(*svc.ApprovePetitionRequest))
+		},
+	},
+	{
+		method:      http.MethodPost,
+		path:        "/v1/petitions/{petition_id}/reject",
+		operationID: "RejectPetition",
+		summary:     "Reject a petition with an official reply",
+		params: []param{
+			petitionIDPath,
+			{name: "reply", in: "body", field: "reply", required: true},
+			idempotencyKeyHeader,
+		},
+		status:     http.StatusOK,
+		response:   "Petition",
+		newRequest: func() proto.Message { return &svc.RejectPetitionRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.RejectPetition(ctx, req.(*svc.RejectPetitionRequest))
+		},
+	},
+	{
+		method:      http.MethodPatch,
+		path:        "/v1/petitions/{petition_id}",
+		operationID: "UpdatePetition",
+		summary:     "Edit a draft petition",
+		params: []param{
+			petitionIDPath,
+			{name: "title", in: "body", field: "title"},
+			{name: "description", in: "body", field: "description"},
+		},
+		status:     http.StatusOK,
+		response:   "Petition",
+		newRequest: func() proto.Message { return &svc.UpdatePetitionRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.UpdatePetition(ctx, req.(*svc.UpdatePetitionRequest))
+		},
+	},
+	{
+		method:      http.MethodPost,
+		path:        "/v1/petitions/{petition_id}/publish",
+		operationID: "PublishPetition",
+		summary:     "Publish a draft petition",
+		params:      []param{petitionIDPath, idempotencyKeyHeader},
+		status:      http.StatusOK,
+		response:    "Petition",
+		newRequest:  func() proto.Message { return &svc.PublishPetitionRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.PublishPetition(ctx, req.(*svc.PublishPetitionRequest))
+		},
+	},
+	{
+		method:      http.MethodPost,
+		path:        "/v1/petitions/{petition_id}/co-authors",
+		operationID: "InviteCoAuthor",
+		summary:     "Invite a user to co-author a draft petition",
+		params: []param{
+			petitionIDPath,
+			{name: "user_id", in: "body", field: "user_id", required: true},
+		},
+		status:     http.StatusCreated,
+		response:   "PetitionAuthor",
+		newRequest: func() proto.Message { return &svc.InviteCoAuthorRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.InviteCoAuthor(ctx, req.(*svc.InviteCoAuthorRequest))
+		},
+	},
+	{
+		method:      http.MethodPost,
+		path:        "/v1/petitions/{petition_id}/co-authors/accept",
+		operationID: "AcceptCoAuthorInvitation",
+		summary:     "Accept an invitation to co-author a petition",
+		params:      []param{petitionIDPath},
+		status:      http.StatusOK,
+		response:    "PetitionAuthor",
+		newRequest:  func() proto.Message { return &svc.AcceptCoAuthorInvitationRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.AcceptCoAuthorInvitation(ctx, req.(*svc.AcceptCoAuthorInvitationRequest))
+		},
+	},
+	{
+		method:      http.MethodPost,
+		path:        "/v1/petitions/{petition_id}/co-authors/decline",
+		operationID: "DeclineCoAuthorInvitation",
+		summary:     "Decline an invitation to co-author a petition",
+		params:      []param{petitionIDPath},
+		status:      http.StatusOK,
+		response:    "PetitionAuthor",
+		newRequest:  func() proto.Message { return &svc.DeclineCoAuthorInvitationRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.DeclineCoAuthorInvitation(ctx, req.(*svc.DeclineCoAuthorInvitationRequest))
+		},
+	},
+	{
+		method:      http.MethodDelete,
+		path:        "/v1/petitions/{petition_id}/co-authors/{user_id}",
+		operationID: "RemoveCoAuthor",
+		summary:     "Remove a co-author or a pending invitation from a draft petition",
+		params: []param{
+			petitionIDPath,
+			{name: "user_id", in: "path", field: "user_id", required: true, description: "co-author user ID"},
+		},
+		status:     http.StatusOK,
+		response:   "RemoveCoAuthorResponse",
+		newRequest: func() proto.Message { return &svc.RemoveCoAuthorRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.RemoveCoAuthor(ctx, req.(*svc.RemoveCoAuthorRequest))
+		},
+	},
+	{
+		method:      http.MethodPost,
+		path:        "/v1/petitions/{petition_id}/comments",
+		operationID: "CreateComment",
+		summary:     "Comment on a petition or reply to a comment",
+		params: []param{
+			petitionIDPath,
+			{name: "body", in: "body", field: "body", required: true},
+			{name: "parent_id", in: "body", field: "parent_id", description: "comment to reply to"},
+			idempotencyKeyHeader,
+		},
+		status:     http.StatusCreated,
+		response:   "Comment",
+		newRequest: func() proto.Message { return &svc.CreateCommentRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.CreateComment(ctx, req.(*svc.CreateCommentRequest))
+		},
+	},
+	{
+		method:      http.MethodGet,
+		path:        "/v1/petitions/{petition_id}/comments",
+		operationID: "ListComments",
+		summary:     "List comments of a petition, oldest first",
+		params: []param{
+			petitionIDPath,
+			{name: "parent_id", in: "query", field: "parent_id", description: "list replies to this comment instead of top-level comments"},
+			{name: "cursor", in: "query", field: "cursor", description: "next_cursor of the previous page"},
+			{name: "limit", in: "query", field: "limit", kind: kindUint, description: "page size"},
+		},
+		status:     http.StatusOK,
+		response:   "CommentList",
+		newRequest: func() proto.Message { return &svc.ListCommentsRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.ListComments(ctx, req.(*svc.ListCommentsRequest))
+		},
+	},
+	{
+		method:      http.MethodPatch,
+		path:        "/v1/comments/{comment_id}",
+		operationID: "EditComment",
+		summary:     "Edit an own comment within the edit window",
+		params: []param{
+			commentIDPath,
+			{name: "body", in: "body", field: "body", required: true},
+		},
+		status:     http.StatusOK,
+		response:   "Comment",
+		newRequest: func() proto.Message { return &svc.EditCommentRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.EditComment(ctx, req.(*svc.EditCommentRequest))
+		},
+	},
+	{
+		method:      http.MethodDelete,
+		path:        "/v1/comments/{comment_id}",
+		operationID: "DeleteComment",
+		summary:     "Delete a comment, replies stay in the thread",
+		params:      []param{commentIDPath},
+		status:      http.StatusOK,
+		response:    "DeleteCommentResponse",
+		newRequest:  func() proto.Message { return &svc.DeleteCommentRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.DeleteComment(ctx, req.(*svc.DeleteCommentRequest))
+		},
+	},
+	{
+		method:      http.MethodPost,
+		path:        "/v1/comments/{comment_id}/report",
+		operationID: "ReportComment",
+		summary:     "Report a comment to the moderators",
+		params: []param{
+			commentIDPath,
+			{name: "reason", in: "body", field: "reason", required: true},
+		},
+		status:     http.StatusOK,
+		response:   "ReportCommentResponse",
+		newRequest: func() proto.Message { return &svc.ReportCommentRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.ReportComment(ctx, req.(*svc.ReportCommentRequest))
+		},
+	},
+	{
+		method:      http.MethodPost,
+		path:        "/v1/comments/{comment_id}/moderate",
+		operationID: "ModerateComment",
+		summary:     "Publish or remove a reported or held comment",
+		params: []param{
+			commentIDPath,
+			{name: "remove", in: "body", field: "remove", kind: kindBool, description: "remove the comment instead of publishing it"},
+		},
+		status:     http.StatusOK,
+		response:   "Comment",
+		newRequest: func() proto.Message { return &svc.ModerateCommentRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.ModerateComment(ctx, req.(*svc.ModerateCommentRequest))
+		},
+	},
+	{
+		method:      http.MethodPost,
+		path:        "/v1/petitions/{petition_id}/signatures",
+		operationID: "SignPetition",
+		summary:     "Sign a petition",
+		params: []param{
+			petitionIDPath,
+			{name: "reason", in: "body", field: "reason", description: "why the signer supports the petition"},
+			{name: "visibility", in: "body", field: "visibility", description: "public, anonymous (default) or private"},
+			idempotencyKeyHeader,
+		},
+		status:     http.StatusCreated,
+		response:   "Signature",
+		newRequest: func() proto.Message { return &svc.SignPetitionRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.SignPetition(ctx, req.(*svc.SignPetitionRequest))
+		},
+	},
+	{
+		method:      http.MethodGet,
+		path:        "/v1/petitions/{petition_id}/receipts/{signature_id}",
+		operationID: "VerifySignatureReceipt",
+		summary:     "Check a signature receipt against the petition's hash chain",
+		params: []param{
+			petitionIDPath,
+			{name: "signature_id", in: "path", field: "signature_id", required: true, description: "signature ID of the receipt"},
+			{name: "hash", in: "query", field: "hash", required: true, description: "hex-encoded hash of the receipt"},
+		},
+		status:     http.StatusOK,
+		response:   "SignatureReceiptVerification",
+		newRequest: func() proto.Message { return &svc.VerifySignatureReceiptRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.VerifySignatureReceipt(ctx, req.(*svc.VerifySignatureReceiptRequest))
+		},
+	},
+	{
+		method:      http.MethodGet,
+		path:        "/v1/petitions/{petition_id}/reasons",
+		operationID: "ListSignatureReasons",
+		summary:     "List top reasons signers gave, verified signers first",
+		params: []param{
+			petitionIDPath,
+			{name: "limit", in: "query", field: "limit", kind: kindUint, description: "number of reasons, at most 50"},
+		},
+		status:     http.StatusOK,
+		response:   "SignatureReasonList",
+		newRequest: func() proto.Message { return &svc.ListSignatureReasonsRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.ListSignatureReasons(ctx, req.(*svc.ListSignatureReasonsRequest))
+		},
+	},
+	{
+		method:      http.MethodGet,
+		path:        "/v1/petitions/{petition_id}/signature-stats",
+		operationID: "GetSignatureStats",
+		summary:     "Aggregate signature counts for the petition's authors, officials and admins",
+		params:      []param{petitionIDPath},
+		status:      http.StatusOK,
+		response:    "SignatureStats",
+		newRequest:  func() proto.Message { return &svc.GetSignatureStatsRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.GetSignatureStats(ctx, req.(*svc.GetSignatureStatsRequest))
+		},
+	},
+	{
+		method:      http.MethodGet,
+		path:        "/v1/signatures",
+		operationID: "ListPetitionSigners",
+		summary:     "List petition signatures; officials and admins get every signer, others only public ones",
+		params: []param{
+			{name: "petition_id", in: "query", field: "petition_id"},
+			{name: "user_id", in: "query", field: "user_id"},
+			{name: "sort", in: "query", kind: kindFlag, flags: map[string]string{
+				"newest": "",
+				"oldest": "oldest",
+			}},
+			pageQuery,
+			sizeQuery,
+		},
+		status:     http.StatusOK,
+		response:   "SignatureList",
+		newRequest: func() proto.Message { return &svc.ListPetitionSignersRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.ListPetitionSigners(ctx, req.(*svc.ListPetitionSignersRequest))
+		},
+	},
+	{
+		method:      http.MethodGet,
+		path:        "/v1/users/{user_id}/data",
+		operationID: "ExportUserData",
+		summary:     "Export everything stored about a user as a JSON bundle (service token only)",
+		params: []param{
+			{name: "user_id", in: "path", field: "user_id", required: true, description: "user ID"},
+		},
+		status:     http.StatusOK,
+		response:   "UserDataExport",
+		newRequest: func() proto.Message { return &svc.ExportUserDataRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.ExportUserData(ctx, req.(*svc.ExportUserDataRequest))
+		},
+	},
+	{
+		method:      http.MethodDelete,
+		path:        "/v1/users/{user_id}/data",
+		operationID: "EraseUserData",
+		summary:     "Anonymize a user across all data, keeping signature counts (service token only)",
+		params: []param{
+			{name: "user_id", in: "path", field: "user_id", required: true, description: "user ID"},
+		},
+		status:     http.StatusOK,
+		response:   "UserDataErasure",
+		newRequest: func() proto.Message { return &svc.EraseUserDataRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.EraseUserData(ctx, req.(*svc.EraseUserDataRequest))
+		},
+	},
+	{
+		method:      http.MethodGet,
+		path:        "/v1/signature-flags",
+		operationID: "ListSignatureFlags",
+		summary:     "List fraud flags of signatures in a city",
+		params: []param{
+			{name: "city_id", in: "query", field: "city_id", required: true},
+			{name: "petition_id", in: "query", field: "petition_id"},
+			{name: "status", in: "query", field: "status", description: "open, dismissed or confirmed"},
+			pageQuery,
+			sizeQuery,
+		},
+		status:     http.StatusOK,
+		response:   "SignatureFlagList",
+		newRequest: func() proto.Message { return &svc.ListSignatureFlagsRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.ListSignatureFlags(ctx, req.(*svc.ListSignatureFlagsRequest))
+		},
+	},
+	{
+		method:      http.MethodPost,
+		path:        "/v1/signature-flags/dismiss",
+		operationID: "DismissSignatureFlags",
+		summary:     "Dismiss fraud flags as false positives",
+		params: []param{
+			{name: "flag_ids", in: "body", field: "flag_ids", kind: kindStringList, required: true},
+		},
+		status:     http.StatusOK,
+		response:   "DismissSignatureFlagsResponse",
+		newRequest: func() proto.Message { return &svc.DismissSignatureFlagsRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.DismissSignatureFlags(ctx, req.(*svc.DismissSignatureFlagsRequest))
+		},
+	},
+	{
+		method:      http.MethodPost,
+		path:        "/v1/signatures/invalidate",
+		operationID: "InvalidateSignatures",
+		summary:     "Invalidate signatures so they are no longer counted",
+		params: []param{
+			{name: "signature_ids", in: "body", field: "signature_ids", kind: kindStringList, required: true},
+			{name: "reason", in: "body", field: "reason", required: true},
+		},
+		status:     http.StatusOK,
+		response:   "InvalidateSignaturesResponse",
+		newRequest: func() proto.Message { return &svc.InvalidateSignaturesRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.InvalidateSignatures(ctx, req.(*svc.InvalidateSignaturesRequest))
+		},
+	},
+	{
+		method:      http.MethodPost,
+		path:        "/v1/petitions/{petition_id}/merge",
+		operationID: "MergePetitions",
+		summary:     "Merge duplicate petitions into this one and move their signatures",
+		params: []param{
+			petitionIDPath,
+			{name: "source_ids", in: "body", field: "source_ids", kind: kindStringList, required: true},
+			idempotencyKeyHeader,
+		},
+		status:     http.StatusOK,
+		response:   "MergePetitionsResponse",
+		newRequest: func() proto.Message { return &svc.MergePetitionsRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.MergePetitions(ctx, req.(*svc.MergePetitionsRequest))
+		},
+	},
+	{
+		method:      http.MethodGet,
+		path:        "/v1/content-flags",
+		operationID: "ListContentFlags",
+		summary:     "List content filter flags of petitions in a city",
+		params: []param{
+			{name: "city_id", in: "query", field: "city_id", required: true},
+			{name: "petition_id", in: "query", field: "petition_id"},
+			{name: "status", in: "query", field: "status", description: "open, dismissed or confirmed"},
+			pageQuery,
+			sizeQuery,
+		},
+		status:     http.StatusOK,
+		response:   "ContentFlagList",
+		newRequest: func() proto.Message { return &svc.ListContentFlagsRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.ListContentFlags(ctx, req.(*svc.ListContentFlagsRequest))
+		},
+	},
+	{
+		method:      http.MethodPost,
+		path:        "/v1/content-flags/dismiss",
+		operationID: "DismissContentFlags",
+		summary:     "Dismiss content flags as false positives, publishing petitions held for moderation",
+		params: []param{
+			{name: "flag_ids", in: "body", field: "flag_ids", kind: kindStringList, required: true},
+		},
+		status:     http.StatusOK,
+		response:   "DismissContentFlagsResponse",
+		newRequest: func() proto.Message { return &svc.DismissContentFlagsRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.DismissContentFlags(ctx, req.(*svc.DismissContentFlagsRequest))
+		},
+	},
+	{
+		method:      http.MethodPost,
+		path:        "/v1/content-flags/confirm",
+		operationID: "ConfirmContentFlags",
+		summary:     "Confirm content flags, rejecting petitions held for moderation",
+		params: []param{
+			{name: "flag_ids", in: "body", field: "flag_ids", kind: kindStringList, required: true},
+		},
+		status:     http.StatusOK,
+		response:   "ConfirmContentFlagsResponse",
+		newRequest: func() proto.Message { return &svc.ConfirmContentFlagsRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.ConfirmContentFlags(ctx, req.(*svc.ConfirmContentFlagsRequest))
+		},
+	},
+	{
+		method:      http.MethodGet,
+		path:        "/v1/access-policy",
+		operationID: "GetAccessPolicy",
+		summary:     "Show the role and grant mapping in effect",
+		status:      http.StatusOK,
+		response:    "AccessPolicy",
+		newRequest:  func() proto.Message { return &svc.GetAccessPolicyRequest{} },
+		unary: func(ctx context.Context, c svc.PetitionServiceClient, req proto.Message) (proto.Message, error) {
+			return c.GetAccessPolicy(ctx, req.(*svc.GetAccessPolicyRequest))
+		},
+	},
+}
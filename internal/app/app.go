@@ -1,14 +1,32 @@
 package app
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 
 	"github.com/chains-lab/city-petitions-svc/internal/app/entities"
+	"github.com/chains-lab/city-petitions-svc/internal/cache"
 	"github.com/chains-lab/city-petitions-svc/internal/config"
+	"github.com/chains-lab/city-petitions-svc/internal/contentfilter"
+	"github.com/chains-lab/city-petitions-svc/internal/dbx"
+	"github.com/chains-lab/city-petitions-svc/internal/events"
+	"github.com/chains-lab/city-petitions-svc/internal/idempotency"
+	"github.com/chains-lab/city-petitions-svc/internal/metrics"
+	"github.com/chains-lab/city-petitions-svc/internal/rbac"
+	"github.com/chains-lab/city-petitions-svc/internal/residency"
+	"github.com/chains-lab/city-petitions-svc/internal/retention"
 )
 
 type App struct {
 	entities.Petition
+
+	RBAC            rbac.RBAC
+	PetitionUpdates *events.Listener
+	Idempotency     *idempotency.Store
+	Retention       *retention.Worker
+
+	pg *sql.DB
 }
 
 func NewApp(cfg config.Config) (App, error) {
@@ -17,7 +35,56 @@ func NewApp(cfg config.Config) (App, error) {
 		return App{}, err
 	}
 
+	if err := metrics.RegisterDB(pg); err != nil {
+		return App{}, err
+	}
+
+	residencyVerifier, err := residency.NewVerifier(cfg)
+	if err != nil {
+		return App{}, err
+	}
+
+	access, err := rbac.New(cfg.RBAC)
+	if err != nil {
+		return App{}, err
+	}
+
+	petitionsCache, err := cache.New(cfg)
+	if err != nil {
+		return App{}, err
+	}
+
+	content, err := contentfilter.New(cfg)
+	if err != nil {
+		return App{}, err
+	}
+
+	broker := events.NewBroker()
+	petition, err := entities.NewPetition(cfg, pg, residencyVerifier, access, broker, petitionsCache, content)
+	if err != nil {
+		return App{}, err
+	}
+
 	return App{
-		Petition: entities.NewPetition(pg),
+		Petition:        petition,
+		RBAC:            access,
+		PetitionUpdates: events.NewListener(cfg.Database.SQL.URL, broker),
+		Idempotency:     idempotency.New(cfg, pg),
+		Retention:       retention.New(cfg, petition),
+		pg:              pg,
 	}, nil
 }
+
+// Ready reports whether the app can serve requests: the database answers
+// and all migrations are applied.
+func (a App) Ready(ctx context.Context) error {
+	if err := a.pg.PingContext(ctx); err != nil {
+		return fmt.Errorf("database ping: %w", err)
+	}
+
+	if err := dbx.CheckMigrations(a.pg); err != nil {
+		return err
+	}
+
+	return nil
+}
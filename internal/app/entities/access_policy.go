@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"context"
+
+	"github.com/chains-lab/city-petitions-svc/internal/app/models"
+	"github.com/chains-lab/city-petitions-svc/internal/rbac"
+	"github.com/chains-lab/city-petitions-svc/internal/tracing"
+	"github.com/google/uuid"
+)
+
+// GetAccessPolicy returns the role and grant mapping in effect. Only holders of policy.manage,
+// which is never city scoped, may read it.
+func (p Petition) GetAccessPolicy(ctx context.Context, initiator Initiator) ([]models.AccessRule, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.GetAccessPolicy")
+	defer span.End()
+
+	if err := p.checkPermission(ctx, initiator, rbac.PermissionPolicyManage, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	rules := p.access.Rules()
+
+	res := make([]models.AccessRule, 0, len(rules))
+	for _, rule := range rules {
+		r := models.AccessRule{
+			Role:       rule.Role,
+			Permission: string(rule.Permission),
+			CityIDs:    rule.Cities,
+		}
+		if rule.UserID != uuid.Nil {
+			userID := rule.UserID
+			r.UserID = &userID
+		}
+
+		res = append(res, r)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chains-lab/city-petitions-svc/internal/config"
+	"github.com/chains-lab/city-petitions-svc/internal/constant/enum"
+	"github.com/chains-lab/city-petitions-svc/internal/rbac"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetAccessPolicy(t *testing.T) {
+	access, err := rbac.New(config.RBACConfig{})
+	if err != nil {
+		t.Fatalf("rbac.New: %v", err)
+	}
+	p := Petition{access: access}
+
+	tests := []struct {
+		role string
+		want codes.Code
+	}{
+		{enum.UserRoleUser, codes.PermissionDenied},
+		{enum.UserRoleModerator, codes.PermissionDenied},
+		{enum.UserRoleAdmin, codes.PermissionDenied},
+		{enum.UserRoleSuperUser, codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			rules, err := p.GetAccessPolicy(context.Background(), Initiator{ID: uuid.New(), Role: tt.role})
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("code = %s, want %s (err: %v)", got, tt.want, err)
+			}
+			if err == nil && len(rules) != len(access.Rules()) {
+				t.Errorf("got %d rules, want %d", len(rules), len(access.Rules()))
+			}
+		})
+	}
+}
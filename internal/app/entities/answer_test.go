@@ -0,0 +1,115 @@
+package entities
+
+import (
+	"context"
+	"database/sql/driver"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chains-lab/city-petitions-svc/internal/app/models"
+	"github.com/chains-lab/city-petitions-svc/internal/config"
+	"github.com/chains-lab/city-petitions-svc/internal/constant/enum"
+	"github.com/chains-lab/city-petitions-svc/internal/contentfilter"
+	"github.com/chains-lab/city-petitions-svc/internal/dbx"
+	"github.com/chains-lab/city-petitions-svc/internal/rbac"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type allowContent struct{}
+
+func (allowContent) Check(context.Context, ...contentfilter.Field) (contentfilter.Verdict, error) {
+	return contentfilter.Verdict{Action: contentfilter.ActionAllow}, nil
+}
+
+// newPetitionWithStatus returns a Petition over a fake database holding visPetitionID with the
+// given status, under the default role mapping.
+func newPetitionWithStatus(t *testing.T, petitionStatus string) (Petition, *fakeDB) {
+	t.Helper()
+
+	access, err := rbac.New(config.RBACConfig{})
+	if err != nil {
+		t.Fatalf("rbac.New: %v", err)
+	}
+
+	now := time.Now().UTC()
+	db, fake := openFakeDB(t, func(query string, args []driver.NamedValue) ([]string, [][]driver.Value) {
+		if !strings.Contains(query, "FROM petitions ") {
+			return nil, nil
+		}
+
+		return []string{"id", "city_id", "creator_id", "title", "description", "status", "signatures", "goal", "reply", "end_date", "created_at", "updated_at", "merged_into", "closed_at"},
+			[][]driver.Value{{
+				visPetitionID.String(), visCityID.String(), visCreatorID.String(), "title", "description",
+				petitionStatus, int64(4), int64(100), "", now.Add(time.Hour), now, now, nil, nil,
+			}}
+	})
+
+	return Petition{
+		db:            db,
+		q:             dbx.NewPetitionsQ(db),
+		contentFlagsQ: dbx.NewContentFlagsQ(db),
+		authorsQ:      dbx.NewPetitionAuthorsQ(db),
+		access:        access,
+		content:       allowContent{},
+	}, fake
+}
+
+func TestAnswerRequiresPublished(t *testing.T) {
+	answer := map[string]func(p Petition, ctx context.Context, initiator Initiator, petitionID uuid.UUID, reply string) (models.Petition, error){
+		"approve": Petition.ApprovePetition,
+		"reject":  Petition.RejectPetition,
+	}
+
+	tests := []struct {
+		status string
+		want   codes.Code
+	}{
+		// drafts go through PublishPetition first, for its co-author check, re-screen and end date
+		{enum.PetitionDraft, codes.FailedPrecondition},
+		// the content filter hold is lifted by DismissContentFlags or ConfirmContentFlags only
+		{enum.PetitionModeration, codes.FailedPrecondition},
+		{enum.PetitionApproved, codes.FailedPrecondition},
+		{enum.PetitionRejected, codes.FailedPrecondition},
+		{enum.PetitionMerged, codes.FailedPrecondition},
+		{enum.PetitionPublished, codes.OK},
+	}
+
+	moderator := Initiator{ID: uuid.New(), Role: enum.UserRoleModerator}
+
+	for name, fn := range answer {
+		for _, tt := range tests {
+			t.Run(name+" "+tt.status, func(t *testing.T) {
+				p, fake := newPetitionWithStatus(t, tt.status)
+
+				_, err := fn(p, context.Background(), moderator, visPetitionID, "reply")
+				if got := status.Code(err); got != tt.want {
+					t.Fatalf("code = %s, want %s (err: %v)", got, tt.want, err)
+				}
+
+				updates := fake.find("UPDATE petitions")
+				if err != nil {
+					if len(updates) != 0 {
+						t.Errorf("petition updated: %v", updates)
+					}
+					return
+				}
+
+				if len(updates) != 1 {
+					t.Fatalf("got %d petition updates, want 1", len(updates))
+				}
+				update := updates[0]
+				if !strings.Contains(update.query, "status = $") || !slices.Contains(update.args, driver.Value(enum.PetitionPublished)) {
+					t.Errorf("update is not limited to published petitions: %s %v", update.query, update.args)
+				}
+				// retention counts from closed_at
+				if !strings.Contains(update.query, "closed_at = $") {
+					t.Errorf("update does not set closed_at: %s", update.query)
+				}
+			})
+		}
+	}
+}
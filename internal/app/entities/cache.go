@@ -0,0 +1,179 @@
+package entities
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/chains-lab/city-petitions-svc/internal/app/models"
+	"github.com/chains-lab/city-petitions-svc/internal/cache"
+	"github.com/chains-lab/city-petitions-svc/internal/config"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+	"github.com/chains-lab/city-petitions-svc/internal/pagination"
+	"github.com/google/uuid"
+)
+
+const (
+	petitionCacheKeyPrefix     = "petitions:petition:"
+	petitionListCacheKeyPrefix = "petitions:list:"
+	// petitionListGenerationKey is part of every list key; bumping it drops all cached pages at once.
+	petitionListGenerationKey = "petitions:list:generation"
+
+	// defaultListTTL bounds how stale signature counts on cached list pages get, since
+	// signing does not drop cached lists.
+	defaultListTTL = 30 * time.Second
+)
+
+// petitionsCache is a read-through cache of single petitions and the first pages of petition lists.
+// Cache failures are logged and treated as misses, so they never fail a request.
+type petitionsCache struct {
+	cache     cache.Cache // nil when caching is disabled
+	ttl       time.Duration
+	listTTL   time.Duration
+	listPages uint64
+}
+
+func newPetitionsCache(cfg config.Config, c cache.Cache) petitionsCache {
+	listTTL := cfg.Cache.ListTTL
+	if listTTL <= 0 {
+		listTTL = defaultListTTL
+	}
+
+	return petitionsCache{
+		cache:     c,
+		ttl:       time.Duration(cfg.Database.Redis.Lifetime) * time.Minute,
+		listTTL:   listTTL,
+		listPages: cfg.Cache.ListPages,
+	}
+}
+
+type cachedPetitionList struct {
+	Petitions []models.Petition   `json:"petitions"`
+	Page      pagination.Response `json:"page"`
+}
+
+func (c petitionsCache) getPetition(ctx context.Context, petitionID uuid.UUID) (models.Petition, bool) {
+	var petition models.Petition
+	ok := c.get(ctx, petitionCacheKeyPrefix+petitionID.String(), &petition)
+
+	return petition, ok
+}
+
+func (c petitionsCache) setPetition(ctx context.Context, petition models.Petition) {
+	c.set(ctx, petitionCacheKeyPrefix+petition.ID.String(), petition, c.ttl)
+}
+
+func (c petitionsCache) getList(ctx context.Context, key string) (cachedPetitionList, bool) {
+	var list cachedPetitionList
+	ok := c.get(ctx, key, &list)
+
+	return list, ok
+}
+
+func (c petitionsCache) setList(ctx context.Context, key string, list cachedPetitionList) {
+	c.set(ctx, key, list, c.listTTL)
+}
+
+// listKey returns the cache key of a petition list query, or false when the page is not cached.
+func (c petitionsCache) listKey(
+	ctx context.Context,
+	filter ListPetitionsFilter,
+	sort ListPetitionsSort,
+	pag pagination.Request,
+) (string, bool) {
+	if c.cache == nil || pag.Page > c.listPages {
+		return "", false
+	}
+
+	generation, ok, err := c.cache.Get(ctx, petitionListGenerationKey)
+	if err != nil {
+		logger.Log(ctx).WithError(err).Warn("failed to read petition list cache generation")
+		return "", false
+	}
+	if !ok {
+		generation = []byte("0")
+	}
+
+	query, err := json.Marshal(struct {
+		Filter ListPetitionsFilter
+		Sort   ListPetitionsSort
+		Page   pagination.Request
+	}{filter, sort, pag})
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(query)
+
+	return petitionListCacheKeyPrefix + string(generation) + ":" + hex.EncodeToString(sum[:]), true
+}
+
+// invalidate drops the given petitions and every cached list page. It is for changes that
+// move petitions in or out of lists or change what lists show besides the signature count.
+func (c petitionsCache) invalidate(ctx context.Context, petitionIDs ...uuid.UUID) {
+	if c.cache == nil {
+		return
+	}
+
+	c.invalidateCounts(ctx, petitionIDs...)
+
+	if _, err := c.cache.Incr(ctx, petitionListGenerationKey); err != nil {
+		logger.Log(ctx).WithError(err).Warn("failed to invalidate cached petition lists")
+	}
+}
+
+// invalidateCounts drops the given petitions only. Signature counts on cached list pages
+// catch up when the pages expire after listTTL.
+func (c petitionsCache) invalidateCounts(ctx context.Context, petitionIDs ...uuid.UUID) {
+	if c.cache == nil || len(petitionIDs) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(petitionIDs))
+	for _, id := range petitionIDs {
+		keys = append(keys, petitionCacheKeyPrefix+id.String())
+	}
+
+	if err := c.cache.Delete(ctx, keys...); err != nil {
+		logger.Log(ctx).WithError(err).Warn("failed to invalidate cached petitions")
+	}
+}
+
+func (c petitionsCache) get(ctx context.Context, key string, dst interface{}) bool {
+	if c.cache == nil {
+		return false
+	}
+
+	raw, ok, err := c.cache.Get(ctx, key)
+	if err != nil {
+		logger.Log(ctx).WithError(err).Warnf("failed to read cache key %s", key)
+		return false
+	}
+	if !ok {
+		return false
+	}
+
+	if err := json.Unmarshal(raw, dst); err != nil {
+		logger.Log(ctx).WithError(err).Warnf("failed to decode cache key %s", key)
+		return false
+	}
+
+	return true
+}
+
+func (c petitionsCache) set(ctx context.Context, key string, value interface{}, ttl time.Duration) {
+	if c.cache == nil {
+		return
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		logger.Log(ctx).WithError(err).Warnf("failed to encode cache key %s", key)
+		return
+	}
+
+	if err := c.cache.Set(ctx, key, raw, ttl); err != nil {
+		logger.Log(ctx).WithError(err).Warnf("failed to write cache key %s", key)
+	}
+}
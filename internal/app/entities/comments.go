@@ -0,0 +1,439 @@
+package entities
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chains-lab/city-petitions-svc/internal/app/models"
+	"github.com/chains-lab/city-petitions-svc/internal/config"
+	"github.com/chains-lab/city-petitions-svc/internal/constant/enum"
+	"github.com/chains-lab/city-petitions-svc/internal/contentfilter"
+	"github.com/chains-lab/city-petitions-svc/internal/dbx"
+	"github.com/chains-lab/city-petitions-svc/internal/errx"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+	"github.com/chains-lab/city-petitions-svc/internal/pagination"
+	"github.com/chains-lab/city-petitions-svc/internal/rbac"
+	"github.com/chains-lab/city-petitions-svc/internal/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type commentsQ interface {
+	New() dbx.PetitionCommentsQ
+
+	Insert(ctx context.Context, input dbx.PetitionComment) error
+	Get(ctx context.Context) (dbx.PetitionComment, error)
+	Select(ctx context.Context) ([]dbx.PetitionComment, error)
+	Update(ctx context.Context, in dbx.UpdatePetitionCommentInput) error
+	AnonymizeUser(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	FilterID(id uuid.UUID) dbx.PetitionCommentsQ
+	FilterPetitionID(petitionID uuid.UUID) dbx.PetitionCommentsQ
+	FilterParentID(parentID *uuid.UUID) dbx.PetitionCommentsQ
+	FilterAuthorID(authorID uuid.UUID) dbx.PetitionCommentsQ
+	FilterStatus(status ...string) dbx.PetitionCommentsQ
+	FilterStatusOrAuthor(authorID uuid.UUID, status ...string) dbx.PetitionCommentsQ
+	FilterNotDeleted() dbx.PetitionCommentsQ
+	FilterDeletedBy(userID uuid.UUID) dbx.PetitionCommentsQ
+
+	After(createdAt time.Time, id uuid.UUID) dbx.PetitionCommentsQ
+	OrderByCreated(ascending bool) dbx.PetitionCommentsQ
+	Limit(limit uint64) dbx.PetitionCommentsQ
+
+	Count(ctx context.Context) (uint64, error)
+}
+
+type commentReportsQ interface {
+	New() dbx.CommentReportsQ
+
+	Insert(ctx context.Context, input dbx.CommentReport) (bool, error)
+	Select(ctx context.Context) ([]dbx.CommentReport, error)
+	Update(ctx context.Context, in dbx.UpdateCommentReportInput) error
+	AnonymizeUser(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	FilterCommentID(commentID uuid.UUID) dbx.CommentReportsQ
+	FilterReporterID(userID uuid.UUID) dbx.CommentReportsQ
+	FilterReviewedBy(userID uuid.UUID) dbx.CommentReportsQ
+	FilterStatus(status string) dbx.CommentReportsQ
+
+	OrderByCreated(ascending bool) dbx.CommentReportsQ
+
+	Count(ctx context.Context) (uint64, error)
+}
+
+type commentRules struct {
+	editWindow      time.Duration
+	maxDepth        int
+	reportThreshold int
+	maxPageSize     uint64
+}
+
+func newCommentRules(cfg config.Config) commentRules {
+	return commentRules{
+		editWindow:      cfg.Petitions.Comments.EditWindow,
+		maxDepth:        cfg.Petitions.Comments.MaxDepth,
+		reportThreshold: cfg.Petitions.Comments.ReportThreshold,
+		maxPageSize:     cfg.Petitions.Comments.MaxPageSize,
+	}
+}
+
+// CreateComment posts a comment on a petition, or a reply when parentID is set.
+// Comments matched by the content filter are held for moderation.
+func (p Petition) CreateComment(ctx context.Context, initiator Initiator, petitionID uuid.UUID, parentID *uuid.UUID, body string) (models.Comment, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.CreateComment", attribute.String("petition.id", petitionID.String()))
+	defer span.End()
+
+	petition, err := p.q.New().FilterID(petitionID).Get(ctx)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return models.Comment{}, errx.RaisePetitionNotFoundByID(ctx, err, petitionID)
+		default:
+			return models.Comment{}, errx.RaiseInternal(ctx, err)
+		}
+	}
+
+	switch petition.Status {
+	case enum.PetitionPublished, enum.PetitionApproved, enum.PetitionRejected:
+	default:
+		return models.Comment{}, errx.RaisePetitionIsNotAvailable(ctx, fmt.Errorf("petition %s is %s", petitionID, petition.Status), petitionID.String())
+	}
+
+	comment := dbx.PetitionComment{
+		ID:         uuid.New(),
+		PetitionID: petitionID,
+		AuthorID:   initiator.ID,
+		Body:       body,
+		Status:     enum.CommentPublished,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	if parentID != nil {
+		parent, err := p.commentsQ.New().FilterID(*parentID).FilterPetitionID(petitionID).FilterNotDeleted().Get(ctx)
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return models.Comment{}, errx.RaiseCommentNotFoundByID(ctx, err, *parentID)
+			default:
+				return models.Comment{}, errx.RaiseInternal(ctx, err)
+			}
+		}
+
+		if parent.Status != enum.CommentPublished {
+			return models.Comment{}, errx.RaiseCommentNotFoundByID(ctx, fmt.Errorf("parent comment is %s", parent.Status), *parentID)
+		}
+
+		if parent.Depth+1 > p.commentRules.maxDepth {
+			return models.Comment{}, errx.RaiseCommentThreadTooDeep(ctx, fmt.Errorf("parent comment is %d deep", parent.Depth), *parentID, p.commentRules.maxDepth)
+		}
+
+		comment.ParentID = uuid.NullUUID{UUID: *parentID, Valid: true}
+		comment.Depth = parent.Depth + 1
+	}
+
+	verdict, err := p.screenContent(ctx, contentfilter.Field{Name: "body", Text: body})
+	if err != nil {
+		return models.Comment{}, err
+	}
+
+	if verdict.NeedsModeration() {
+		comment.Status = enum.CommentModeration
+	}
+
+	if err := p.commentsQ.New().Insert(ctx, comment); err != nil {
+		return models.Comment{}, errx.RaiseInternal(ctx, err)
+	}
+
+	return commentModel(comment, true), nil
+}
+
+// ListComments pages through top-level comments of a petition, or replies to parentID, oldest
+// first. Comments held for moderation are listed only to their authors and moderators.
+// viewer is nil for anonymous callers.
+func (p Petition) ListComments(
+	ctx context.Context,
+	viewer *Initiator,
+	petitionID uuid.UUID,
+	parentID *uuid.UUID,
+	pag pagination.CursorRequest,
+) ([]models.Comment, pagination.CursorResponse, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.ListComments", attribute.String("petition.id", petitionID.String()))
+	defer span.End()
+
+	petition, err := p.q.New().FilterID(petitionID).Get(ctx)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, pagination.CursorResponse{}, errx.RaisePetitionNotFoundByID(ctx, err, petitionID)
+		default:
+			return nil, pagination.CursorResponse{}, errx.RaiseInternal(ctx, err)
+		}
+	}
+
+	moderator := viewer != nil && p.access.Can(viewer.subject(), rbac.PermissionPetitionModerate, petition.CityID)
+
+	query := p.commentsQ.New().FilterPetitionID(petitionID).FilterParentID(parentID)
+	switch {
+	case moderator:
+	case viewer != nil:
+		query = query.FilterStatusOrAuthor(viewer.ID, enum.CommentPublished, enum.CommentRemoved)
+	default:
+		query = query.FilterStatus(enum.CommentPublished, enum.CommentRemoved)
+	}
+
+	if pag.Cursor != "" {
+		cursor, err := pagination.DecodeCursor(pag.Cursor)
+		if err != nil {
+			return nil, pagination.CursorResponse{}, errx.RaiseInternal(ctx, err)
+		}
+		query = query.After(cursor.CreatedAt, cursor.ID)
+	}
+
+	limit := pagination.CursorLimit(pag, p.commentRules.maxPageSize)
+
+	// one more comment than asked tells whether there is a next page
+	comments, err := query.OrderByCreated(true).Limit(limit + 1).Select(ctx)
+	if err != nil {
+		return nil, pagination.CursorResponse{}, errx.RaiseInternal(ctx, err)
+	}
+
+	var next pagination.CursorResponse
+	if uint64(len(comments)) > limit {
+		comments = comments[:limit]
+		last := comments[len(comments)-1]
+		next.NextCursor = pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
+	res := make([]models.Comment, 0, len(comments))
+	for _, c := range comments {
+		res = append(res, commentModel(c, moderator))
+	}
+
+	return res, next, nil
+}
+
+// EditComment replaces the body of the initiator's comment within the edit window.
+func (p Petition) EditComment(ctx context.Context, initiator Initiator, commentID uuid.UUID, body string) (models.Comment, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.EditComment", attribute.String("comment.id", commentID.String()))
+	defer span.End()
+
+	comment, err := p.getComment(ctx, commentID)
+	if err != nil {
+		return models.Comment{}, err
+	}
+
+	if comment.AuthorID != initiator.ID {
+		return models.Comment{}, errx.RaiseNotCommentAuthor(ctx, fmt.Errorf("only the author may edit a comment"), commentID, initiator.ID)
+	}
+
+	if comment.Status == enum.CommentRemoved {
+		return models.Comment{}, errx.RaiseCommentNotFoundByID(ctx, fmt.Errorf("comment was removed by a moderator"), commentID)
+	}
+
+	now := time.Now().UTC()
+	if window := p.commentRules.editWindow; window > 0 && now.Sub(comment.CreatedAt) > window {
+		return models.Comment{}, errx.RaiseCommentEditWindowExpired(ctx, fmt.Errorf("comment was posted at %s", comment.CreatedAt), commentID, window)
+	}
+
+	verdict, err := p.screenContent(ctx, contentfilter.Field{Name: "body", Text: body})
+	if err != nil {
+		return models.Comment{}, err
+	}
+
+	// a comment held by reports stays held even when its new body passes the filter
+	if verdict.NeedsModeration() {
+		comment.Status = enum.CommentModeration
+	}
+	comment.Body = body
+	comment.EditedAt = sql.NullTime{Time: now, Valid: true}
+
+	err = p.commentsQ.New().FilterID(commentID).FilterNotDeleted().Update(ctx, dbx.UpdatePetitionCommentInput{
+		Body:     &comment.Body,
+		Status:   &comment.Status,
+		EditedAt: &now,
+	})
+	if err != nil {
+		return models.Comment{}, errx.RaiseInternal(ctx, err)
+	}
+
+	return commentModel(comment, true), nil
+}
+
+// DeleteComment soft deletes a comment. Authors delete their own comments, moderators any
+// comment in their city. Replies stay in the thread.
+func (p Petition) DeleteComment(ctx context.Context, initiator Initiator, commentID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "entities.Petition.DeleteComment", attribute.String("comment.id", commentID.String()))
+	defer span.End()
+
+	comment, err := p.getComment(ctx, commentID)
+	if err != nil {
+		return err
+	}
+
+	if comment.AuthorID != initiator.ID {
+		petition, err := p.q.New().FilterID(comment.PetitionID).Get(ctx)
+		if err != nil {
+			return errx.RaiseInternal(ctx, err)
+		}
+
+		if err := p.checkPermission(ctx, initiator, rbac.PermissionPetitionModerate, petition.CityID); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now().UTC()
+	err = p.commentsQ.New().FilterID(commentID).FilterNotDeleted().Update(ctx, dbx.UpdatePetitionCommentInput{
+		DeletedAt: &now,
+		DeletedBy: &initiator.ID,
+	})
+	if err != nil {
+		return errx.RaiseInternal(ctx, err)
+	}
+
+	return nil
+}
+
+// ReportComment records the initiator's report of a comment. Once a comment collects the
+// configured number of open reports it is held for moderation. Repeated reports by the same
+// user are ignored.
+func (p Petition) ReportComment(ctx context.Context, initiator Initiator, commentID uuid.UUID, reason string) error {
+	ctx, span := tracing.Start(ctx, "entities.Petition.ReportComment", attribute.String("comment.id", commentID.String()))
+	defer span.End()
+
+	comment, err := p.getComment(ctx, commentID)
+	if err != nil {
+		return err
+	}
+
+	if comment.Status == enum.CommentRemoved {
+		return errx.RaiseCommentNotFoundByID(ctx, fmt.Errorf("comment was removed by a moderator"), commentID)
+	}
+
+	err = dbx.Transaction(ctx, p.db, func(ctx context.Context) error {
+		inserted, err := p.commentReportsQ.New().Insert(ctx, dbx.CommentReport{
+			ID:         uuid.New(),
+			CommentID:  commentID,
+			ReporterID: initiator.ID,
+			Reason:     reason,
+			Status:     enum.CommentReportOpen,
+			CreatedAt:  time.Now().UTC(),
+		})
+		if err != nil || !inserted || p.commentRules.reportThreshold <= 0 {
+			return err
+		}
+
+		open, err := p.commentReportsQ.New().FilterCommentID(commentID).FilterStatus(enum.CommentReportOpen).Count(ctx)
+		if err != nil || open < uint64(p.commentRules.reportThreshold) {
+			return err
+		}
+
+		held := enum.CommentModeration
+		if err := p.commentsQ.New().FilterID(commentID).FilterStatus(enum.CommentPublished).Update(ctx, dbx.UpdatePetitionCommentInput{
+			Status: &held,
+		}); err != nil {
+			return err
+		}
+
+		logger.Log(ctx).Infof("comment %s is held for moderation after %d reports", commentID, open)
+
+		return nil
+	})
+	if err != nil {
+		return errx.RaiseInternal(ctx, err)
+	}
+
+	return nil
+}
+
+// ModerateComment settles the open reports of a comment. A removed comment stays in its thread
+// with a blank body; otherwise the comment is published again and its reports are dismissed.
+func (p Petition) ModerateComment(ctx context.Context, initiator Initiator, commentID uuid.UUID, remove bool) (models.Comment, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.ModerateComment", attribute.String("comment.id", commentID.String()))
+	defer span.End()
+
+	comment, err := p.getComment(ctx, commentID)
+	if err != nil {
+		return models.Comment{}, err
+	}
+
+	petition, err := p.q.New().FilterID(comment.PetitionID).Get(ctx)
+	if err != nil {
+		return models.Comment{}, errx.RaiseInternal(ctx, err)
+	}
+
+	if err := p.checkPermission(ctx, initiator, rbac.PermissionPetitionModerate, petition.CityID); err != nil {
+		return models.Comment{}, err
+	}
+
+	comment.Status = enum.CommentPublished
+	reportStatus := enum.CommentReportDismissed
+	if remove {
+		comment.Status = enum.CommentRemoved
+		reportStatus = enum.CommentReportConfirmed
+	}
+	now := time.Now().UTC()
+
+	err = dbx.Transaction(ctx, p.db, func(ctx context.Context) error {
+		if err := p.commentsQ.New().FilterID(commentID).Update(ctx, dbx.UpdatePetitionCommentInput{
+			Status: &comment.Status,
+		}); err != nil {
+			return err
+		}
+
+		return p.commentReportsQ.New().FilterCommentID(commentID).FilterStatus(enum.CommentReportOpen).Update(ctx, dbx.UpdateCommentReportInput{
+			Status:     &reportStatus,
+			ReviewedBy: &initiator.ID,
+			ReviewedAt: &now,
+		})
+	})
+	if err != nil {
+		return models.Comment{}, errx.RaiseInternal(ctx, err)
+	}
+
+	return commentModel(comment, true), nil
+}
+
+// getComment returns a comment that was not deleted.
+func (p Petition) getComment(ctx context.Context, commentID uuid.UUID) (dbx.PetitionComment, error) {
+	comment, err := p.commentsQ.New().FilterID(commentID).FilterNotDeleted().Get(ctx)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return dbx.PetitionComment{}, errx.RaiseCommentNotFoundByID(ctx, err, commentID)
+		default:
+			return dbx.PetitionComment{}, errx.RaiseInternal(ctx, err)
+		}
+	}
+
+	return comment, nil
+}
+
+// commentModel converts c, blanking the body of deleted and removed comments unless showBody is set.
+func commentModel(c dbx.PetitionComment, showBody bool) models.Comment {
+	res := models.Comment{
+		ID:         c.ID,
+		PetitionID: c.PetitionID,
+		Depth:      c.Depth,
+		AuthorID:   c.AuthorID,
+		Body:       c.Body,
+		Status:     c.Status,
+		Replies:    c.Replies,
+		Deleted:    c.DeletedAt.Valid,
+		CreatedAt:  c.CreatedAt,
+	}
+	if c.ParentID.Valid {
+		res.ParentID = &c.ParentID.UUID
+	}
+	if c.EditedAt.Valid {
+		res.EditedAt = &c.EditedAt.Time
+	}
+
+	if !showBody && (res.Deleted || res.Status == enum.CommentRemoved) {
+		res.Body = ""
+	}
+
+	return res
+}
@@ -0,0 +1,285 @@
+package entities
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/chains-lab/city-petitions-svc/internal/app/models"
+	"github.com/chains-lab/city-petitions-svc/internal/constant/enum"
+	"github.com/chains-lab/city-petitions-svc/internal/contentfilter"
+	"github.com/chains-lab/city-petitions-svc/internal/dbx"
+	"github.com/chains-lab/city-petitions-svc/internal/errx"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+	"github.com/chains-lab/city-petitions-svc/internal/pagination"
+	"github.com/chains-lab/city-petitions-svc/internal/rbac"
+	"github.com/chains-lab/city-petitions-svc/internal/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type contentFilter interface {
+	Check(ctx context.Context, fields ...contentfilter.Field) (contentfilter.Verdict, error)
+}
+
+// screenContent runs the content filter over fields and fails when the content is rejected.
+func (p Petition) screenContent(ctx context.Context, fields ...contentfilter.Field) (contentfilter.Verdict, error) {
+	verdict, err := p.content.Check(ctx, fields...)
+	if err != nil {
+		return contentfilter.Verdict{}, errx.RaiseInternal(ctx, err)
+	}
+
+	for _, m := range verdict.Matches {
+		logger.Log(ctx).Infof("content filter %s matched %s (%s): %s", m.Filter, m.Field, m.Action, m.Details)
+	}
+
+	if verdict.Rejected() {
+		return contentfilter.Verdict{}, errx.RaiseContentRejected(
+			ctx,
+			fmt.Errorf("content rejected with %d matches", len(verdict.Matches)),
+			verdict.Matches,
+		)
+	}
+
+	return verdict, nil
+}
+
+// contentFlags turns the matches of verdict that need a moderator's attention into flags of petitionID.
+func contentFlags(petitionID uuid.UUID, verdict contentfilter.Verdict, now time.Time) []dbx.ContentFlag {
+	var flags []dbx.ContentFlag
+	for _, m := range verdict.Matches {
+		if m.Action != contentfilter.ActionFlag && m.Action != contentfilter.ActionModerate {
+			continue
+		}
+
+		flags = append(flags, dbx.ContentFlag{
+			ID:         uuid.New(),
+			PetitionID: petitionID,
+			Field:      m.Field,
+			Filter:     m.Filter,
+			Action:     m.Action,
+			Details:    m.Details,
+			Status:     enum.ContentFlagOpen,
+			CreatedAt:  now,
+		})
+	}
+
+	return flags
+}
+
+type ListContentFlagsFilter struct {
+	CityID     uuid.UUID
+	PetitionID *uuid.UUID
+	Status     *string
+}
+
+// ListContentFlags lists content filter flags of petitions in a city, newest first.
+func (p Petition) ListContentFlags(
+	ctx context.Context,
+	initiator Initiator,
+	filter ListContentFlagsFilter,
+	pag pagination.Request,
+) ([]models.ContentFlag, pagination.Response, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.ListContentFlags", attribute.String("city.id", filter.CityID.String()))
+	defer span.End()
+
+	if err := p.checkPermission(ctx, initiator, rbac.PermissionPetitionModerate, filter.CityID); err != nil {
+		return nil, pagination.Response{}, err
+	}
+
+	query := p.contentFlagsQ.New().FilterCityID(filter.CityID)
+	if filter.PetitionID != nil {
+		query = query.FilterPetitionID(*filter.PetitionID)
+	}
+	if filter.Status != nil {
+		query = query.FilterStatus(*filter.Status)
+	}
+
+	total, err := query.Count(ctx)
+	if err != nil {
+		return nil, pagination.Response{}, errx.RaiseInternal(ctx, err)
+	}
+
+	limit, offset := pagination.CalculateLimitOffset(pag)
+
+	flags, err := query.OrderByCreated(false).Page(limit, offset).Select(ctx)
+	if err != nil {
+		return nil, pagination.Response{}, errx.RaiseInternal(ctx, err)
+	}
+
+	res := make([]models.ContentFlag, 0, len(flags))
+	for _, f := range flags {
+		res = append(res, contentFlagModel(f))
+	}
+
+	return res, pagination.Response{
+		Page:  pag.Page,
+		Size:  pag.Size,
+		Total: total,
+	}, nil
+}
+
+// DismissContentFlags closes open flags as false positives and returns how many were dismissed.
+// Petitions held for moderation are published once none of their flags is open any more.
+func (p Petition) DismissContentFlags(ctx context.Context, initiator Initiator, flagIDs []uuid.UUID) (int, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.DismissContentFlags", attribute.Int("flags.count", len(flagIDs)))
+	defer span.End()
+
+	open, petitionIDs, err := p.openContentFlags(ctx, initiator, flagIDs)
+	if err != nil || len(open) == 0 {
+		return 0, err
+	}
+
+	status := enum.ContentFlagDismissed
+	now := time.Now().UTC()
+
+	var published []uuid.UUID
+	err = dbx.Transaction(ctx, p.db, func(ctx context.Context) error {
+		err := p.contentFlagsQ.New().FilterIDs(open...).FilterStatus(enum.ContentFlagOpen).Update(ctx, dbx.UpdateContentFlagInput{
+			Status:     &status,
+			ReviewedBy: &initiator.ID,
+			ReviewedAt: &now,
+		})
+		if err != nil {
+			return err
+		}
+
+		held, err := p.q.New().FilterIDs(petitionIDs...).FilterStatus(enum.PetitionModeration).Select(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, petition := range held {
+			remaining, err := p.contentFlagsQ.New().FilterPetitionID(petition.ID).FilterStatus(enum.ContentFlagOpen).Count(ctx)
+			if err != nil {
+				return err
+			}
+			if remaining > 0 {
+				continue
+			}
+
+			// the petition could not collect signatures while it was held, so its period starts now
+			publishedStatus := enum.PetitionPublished
+			endDate := now.AddDate(0, 0, 30)
+			err = p.q.New().FilterID(petition.ID).Update(ctx, dbx.UpdatePetitionInput{
+				Status:  &publishedStatus,
+				EndDate: &endDate,
+			})
+			if err != nil {
+				return err
+			}
+
+			published = append(published, petition.ID)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, errx.RaiseInternal(ctx, err)
+	}
+
+	if len(published) > 0 {
+		logger.Log(ctx).Infof("published %d petitions after moderation", len(published))
+		p.cache.invalidate(ctx, published...)
+	}
+
+	return len(open), nil
+}
+
+// ConfirmContentFlags upholds open flags and returns how many were confirmed.
+// Petitions held for moderation with a confirmed flag are rejected.
+func (p Petition) ConfirmContentFlags(ctx context.Context, initiator Initiator, flagIDs []uuid.UUID) (int, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.ConfirmContentFlags", attribute.Int("flags.count", len(flagIDs)))
+	defer span.End()
+
+	open, petitionIDs, err := p.openContentFlags(ctx, initiator, flagIDs)
+	if err != nil || len(open) == 0 {
+		return 0, err
+	}
+
+	status := enum.ContentFlagConfirmed
+	rejected := enum.PetitionRejected
+	now := time.Now().UTC()
+
+	err = dbx.Transaction(ctx, p.db, func(ctx context.Context) error {
+		err := p.contentFlagsQ.New().FilterIDs(open...).FilterStatus(enum.ContentFlagOpen).Update(ctx, dbx.UpdateContentFlagInput{
+			Status:     &status,
+			ReviewedBy: &initiator.ID,
+			ReviewedAt: &now,
+		})
+		if err != nil {
+			return err
+		}
+
+		return p.q.New().FilterIDs(petitionIDs...).FilterStatus(enum.PetitionModeration).Update(ctx, dbx.UpdatePetitionInput{
+			Status:    &rejected,
+			UpdatedAt: &now,
+			ClosedAt:  &now,
+		})
+	})
+	if err != nil {
+		return 0, errx.RaiseInternal(ctx, err)
+	}
+
+	p.cache.invalidate(ctx, petitionIDs...)
+
+	return len(open), nil
+}
+
+// openContentFlags checks that all flagIDs exist and that initiator may moderate their petitions.
+// It returns the flags that are still open and the petitions they belong to.
+func (p Petition) openContentFlags(ctx context.Context, initiator Initiator, flagIDs []uuid.UUID) ([]uuid.UUID, []uuid.UUID, error) {
+	flags, err := p.contentFlagsQ.New().FilterIDs(flagIDs...).Select(ctx)
+	if err != nil {
+		return nil, nil, errx.RaiseInternal(ctx, err)
+	}
+
+	found := make(map[uuid.UUID]struct{}, len(flags))
+	petitionIDs := make([]uuid.UUID, 0, len(flags))
+	open := make([]uuid.UUID, 0, len(flags))
+	for _, f := range flags {
+		found[f.ID] = struct{}{}
+		if f.Status == enum.ContentFlagOpen {
+			open = append(open, f.ID)
+			petitionIDs = append(petitionIDs, f.PetitionID)
+		}
+	}
+
+	for _, id := range flagIDs {
+		if _, ok := found[id]; !ok {
+			return nil, nil, errx.RaiseContentFlagNotFoundByID(ctx, sql.ErrNoRows, id)
+		}
+	}
+
+	checked := make([]uuid.UUID, 0, len(flags))
+	for _, f := range flags {
+		checked = append(checked, f.PetitionID)
+	}
+	if err := p.checkPetitionsPermission(ctx, initiator, rbac.PermissionPetitionModerate, checked); err != nil {
+		return nil, nil, err
+	}
+
+	return open, petitionIDs, nil
+}
+
+func contentFlagModel(f dbx.ContentFlag) models.ContentFlag {
+	res := models.ContentFlag{
+		ID:         f.ID,
+		PetitionID: f.PetitionID,
+		Field:      f.Field,
+		Filter:     f.Filter,
+		Action:     f.Action,
+		Details:    f.Details,
+		Status:     f.Status,
+		CreatedAt:  f.CreatedAt,
+	}
+	if f.ReviewedBy.Valid {
+		res.ReviewedBy = &f.ReviewedBy.UUID
+	}
+	if f.ReviewedAt.Valid {
+		res.ReviewedAt = &f.ReviewedAt.Time
+	}
+
+	return res
+}
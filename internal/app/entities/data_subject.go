@@ -0,0 +1,302 @@
+package entities
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/chains-lab/city-petitions-svc/internal/app/models"
+	"github.com/chains-lab/city-petitions-svc/internal/dbx"
+	"github.com/chains-lab/city-petitions-svc/internal/errx"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+	"github.com/chains-lab/city-petitions-svc/internal/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type idempotencyKeysQ interface {
+	New() dbx.IdempotencyKeysQ
+
+	Delete(ctx context.Context) error
+
+	FilterUserID(userID uuid.UUID) dbx.IdempotencyKeysQ
+}
+
+// ExportUserData collects everything stored about userID: petitions they created, their
+// co-authorships, signatures, comments and comment reports, and the moderation actions they
+// took. Stored idempotent responses are left out, they expire within a day.
+func (p Petition) ExportUserData(ctx context.Context, userID uuid.UUID) (models.UserDataExport, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.ExportUserData", attribute.String("user.id", userID.String()))
+	defer span.End()
+
+	res := models.UserDataExport{
+		UserID:         userID,
+		GeneratedAt:    time.Now().UTC(),
+		Petitions:      []models.ExportedPetition{},
+		CoAuthorships:  []models.ExportedCoAuthorship{},
+		Signatures:     []models.ExportedSignature{},
+		Comments:       []models.ExportedComment{},
+		CommentReports: []models.ExportedCommentReport{},
+		Audit:          []models.AuditEntry{},
+	}
+
+	// one snapshot, so the sections agree with each other
+	err := dbx.Transaction(ctx, p.db, func(ctx context.Context) error {
+		petitions, err := p.q.New().FilterCreatorID(userID).OrderByCreated(true).Select(ctx)
+		if err != nil {
+			return err
+		}
+		for _, petition := range petitions {
+			res.Petitions = append(res.Petitions, models.ExportedPetition{
+				ID:          petition.ID,
+				CityID:      petition.CityID,
+				Title:       petition.Title,
+				Description: petition.Description,
+				Status:      petition.Status,
+				Signatures:  petition.Signatures,
+				Reply:       petition.Reply,
+				MergedInto:  uuidPtr(petition.MergedInto),
+				EndDate:     petition.EndDate,
+				CreatedAt:   petition.CreatedAt,
+				UpdatedAt:   petition.UpdatedAt,
+			})
+		}
+
+		authors, err := p.authorsQ.New().FilterUserID(userID).OrderByCreated(true).Select(ctx)
+		if err != nil {
+			return err
+		}
+		for _, a := range authors {
+			res.CoAuthorships = append(res.CoAuthorships, models.ExportedCoAuthorship{
+				PetitionID:  a.PetitionID,
+				Status:      a.Status,
+				InvitedBy:   a.InvitedBy,
+				RespondedAt: timePtr(a.RespondedAt),
+				CreatedAt:   a.CreatedAt,
+			})
+		}
+
+		signatures, err := p.sigQ.New().FilterUserID(userID).OrderByCreated(true).Select(ctx)
+		if err != nil {
+			return err
+		}
+		for _, sig := range signatures {
+			res.Signatures = append(res.Signatures, models.ExportedSignature{
+				ID:                sig.ID,
+				PetitionID:        sig.PetitionID,
+				SessionID:         uuidPtr(sig.SessionID),
+				Verified:          sig.Verified,
+				Reason:            sig.Reason.String,
+				Visibility:        sig.Visibility,
+				Invalidated:       sig.Invalidated,
+				InvalidatedReason: sig.InvalidatedReason.String,
+				MergedFrom:        uuidPtr(sig.MergedFrom),
+				CreatedAt:         sig.CreatedAt,
+			})
+		}
+
+		comments, err := p.commentsQ.New().FilterAuthorID(userID).OrderByCreated(true).Select(ctx)
+		if err != nil {
+			return err
+		}
+		for _, c := range comments {
+			res.Comments = append(res.Comments, models.ExportedComment{
+				ID:         c.ID,
+				PetitionID: c.PetitionID,
+				ParentID:   uuidPtr(c.ParentID),
+				Body:       c.Body,
+				Status:     c.Status,
+				EditedAt:   timePtr(c.EditedAt),
+				DeletedAt:  timePtr(c.DeletedAt),
+				CreatedAt:  c.CreatedAt,
+			})
+		}
+
+		reports, err := p.commentReportsQ.New().FilterReporterID(userID).OrderByCreated(true).Select(ctx)
+		if err != nil {
+			return err
+		}
+		for _, r := range reports {
+			res.CommentReports = append(res.CommentReports, models.ExportedCommentReport{
+				ID:        r.ID,
+				CommentID: r.CommentID,
+				Reason:    r.Reason,
+				Status:    r.Status,
+				CreatedAt: r.CreatedAt,
+			})
+		}
+
+		res.Audit, err = p.auditEntries(ctx, userID)
+		return err
+	})
+	if err != nil {
+		return models.UserDataExport{}, errx.RaiseInternal(ctx, err)
+	}
+
+	return res, nil
+}
+
+// auditEntries lists the moderation actions userID took on content of other users.
+func (p Petition) auditEntries(ctx context.Context, userID uuid.UUID) ([]models.AuditEntry, error) {
+	res := []models.AuditEntry{}
+
+	invalidated, err := p.sigQ.New().FilterInvalidatedBy(userID).Select(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, sig := range invalidated {
+		res = append(res, models.AuditEntry{
+			Action:     models.AuditSignatureInvalidated,
+			TargetID:   sig.ID,
+			PetitionID: &sig.PetitionID,
+			Outcome:    sig.InvalidatedReason.String,
+			At:         sig.InvalidatedAt.Time,
+		})
+	}
+
+	sigFlags, err := p.flagsQ.New().FilterReviewedBy(userID).Select(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range sigFlags {
+		res = append(res, models.AuditEntry{
+			Action:     models.AuditSignatureFlagReviewed,
+			TargetID:   f.ID,
+			PetitionID: &f.PetitionID,
+			Outcome:    f.Status,
+			At:         f.ReviewedAt.Time,
+		})
+	}
+
+	contentFlags, err := p.contentFlagsQ.New().FilterReviewedBy(userID).Select(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range contentFlags {
+		res = append(res, models.AuditEntry{
+			Action:     models.AuditContentFlagReviewed,
+			TargetID:   f.ID,
+			PetitionID: &f.PetitionID,
+			Outcome:    f.Status,
+			At:         f.ReviewedAt.Time,
+		})
+	}
+
+	reports, err := p.commentReportsQ.New().FilterReviewedBy(userID).Select(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range reports {
+		res = append(res, models.AuditEntry{
+			Action:   models.AuditCommentReportReviewed,
+			TargetID: r.ID,
+			Outcome:  r.Status,
+			At:       r.ReviewedAt.Time,
+		})
+	}
+
+	deleted, err := p.commentsQ.New().FilterDeletedBy(userID).Select(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range deleted {
+		// own comments are exported as comments already
+		if c.AuthorID == userID {
+			continue
+		}
+		res = append(res, models.AuditEntry{
+			Action:     models.AuditCommentDeleted,
+			TargetID:   c.ID,
+			PetitionID: &c.PetitionID,
+			At:         c.DeletedAt.Time,
+		})
+	}
+
+	return res, nil
+}
+
+// EraseUserData anonymizes userID across all tables. Rows are kept and only unlinked from the
+// user, so signature counts, petitions and comment threads stay intact; free text the user
+// wrote next to signatures, comments and reports is dropped. Petitions they created keep their
+// title and description, which are public record. Signature chain entries are append-only and
+// kept; they hold a keyed hash of the user salted with the user's signer salt, which is deleted
+// here, so the entries can no longer be linked to the user.
+func (p Petition) EraseUserData(ctx context.Context, userID uuid.UUID) (models.UserDataErasure, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.EraseUserData", attribute.String("user.id", userID.String()))
+	defer span.End()
+
+	res := models.UserDataErasure{UserID: userID}
+	var affected []uuid.UUID
+
+	err := dbx.Transaction(ctx, p.db, func(ctx context.Context) error {
+		// cached petitions carry the creator and co-authors, so remember which ones change
+		created, err := p.q.New().FilterCreatorID(userID).Select(ctx)
+		if err != nil {
+			return err
+		}
+		for _, petition := range created {
+			affected = append(affected, petition.ID)
+		}
+
+		authored, err := p.authorsQ.New().FilterUserID(userID).Select(ctx)
+		if err != nil {
+			return err
+		}
+		for _, a := range authored {
+			affected = append(affected, a.PetitionID)
+		}
+
+		steps := []struct {
+			count *int
+			run   func(ctx context.Context, userID uuid.UUID) (int64, error)
+		}{
+			{&res.Petitions, p.q.New().AnonymizeUser},
+			{&res.Signatures, p.sigQ.New().AnonymizeUser},
+			{&res.CoAuthorships, p.authorsQ.New().AnonymizeUser},
+			{&res.Comments, p.commentsQ.New().AnonymizeUser},
+			{&res.CommentReports, p.commentReportsQ.New().AnonymizeUser},
+			{&res.SignatureFlags, p.flagsQ.New().AnonymizeUser},
+			{&res.ContentFlags, p.contentFlagsQ.New().AnonymizeUser},
+		}
+		for _, step := range steps {
+			n, err := step.run(ctx, userID)
+			if err != nil {
+				return err
+			}
+			*step.count = int(n)
+		}
+
+		if err := p.saltsQ.New().FilterUserID(userID).Delete(ctx); err != nil {
+			return err
+		}
+
+		return p.idempotencyQ.New().FilterUserID(userID).Delete(ctx)
+	})
+	if err != nil {
+		return models.UserDataErasure{}, errx.RaiseInternal(ctx, err)
+	}
+
+	p.cache.invalidate(ctx, affected...)
+	logger.Log(ctx).Infof(
+		"erased user %s: %d petitions, %d signatures, %d co-authorships, %d comments, %d reports anonymized",
+		userID, res.Petitions, res.Signatures, res.CoAuthorships, res.Comments, res.CommentReports,
+	)
+
+	return res, nil
+}
+
+func uuidPtr(id uuid.NullUUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+
+	return &id.UUID
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+
+	return &t.Time
+}
@@ -0,0 +1,160 @@
+package entities
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// fakeDB answers the queries of the code under test from respond, so entities can be tested
+// without Postgres. Queries and statements are recorded for inspection; statements affect one
+// row and transactions always commit.
+type fakeDB struct {
+	respond func(query string, args []driver.NamedValue) (columns []string, rows [][]driver.Value)
+
+	mu      sync.Mutex
+	queries []fakeQuery
+}
+
+type fakeQuery struct {
+	query string
+	args  []driver.Value
+}
+
+func (f *fakeDB) record(query string, args []driver.NamedValue) {
+	values := make([]driver.Value, 0, len(args))
+	for _, a := range args {
+		values = append(values, a.Value)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.queries = append(f.queries, fakeQuery{query: query, args: values})
+}
+
+// find returns the recorded queries containing substr.
+func (f *fakeDB) find(substr string) []fakeQuery {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var res []fakeQuery
+	for _, q := range f.queries {
+		if strings.Contains(q.query, substr) {
+			res = append(res, q)
+		}
+	}
+
+	return res
+}
+
+func (f *fakeDB) last() fakeQuery {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.queries) == 0 {
+		return fakeQuery{}
+	}
+
+	return f.queries[len(f.queries)-1]
+}
+
+var (
+	fakeDBs   sync.Map
+	fakeDBSeq atomic.Int64
+)
+
+func init() {
+	sql.Register("entities-fake", fakeDriver{})
+}
+
+// openFakeDB returns a *sql.DB served by respond.
+func openFakeDB(t *testing.T, respond func(query string, args []driver.NamedValue) ([]string, [][]driver.Value)) (*sql.DB, *fakeDB) {
+	t.Helper()
+
+	name := fmt.Sprintf("fake-%d", fakeDBSeq.Add(1))
+	f := &fakeDB{respond: respond}
+	fakeDBs.Store(name, f)
+
+	db, err := sql.Open("entities-fake", name)
+	if err != nil {
+		t.Fatalf("opening fake db: %v", err)
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+		fakeDBs.Delete(name)
+	})
+
+	return db, f
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	f, ok := fakeDBs.Load(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown fake db %q", name)
+	}
+
+	return fakeConn{db: f.(*fakeDB)}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("fake db does not prepare statements")
+}
+
+func (fakeConn) Close() error { return nil }
+
+func (fakeConn) Begin() (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+func (c fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.record(query, args)
+
+	return driver.RowsAffected(1), nil
+}
+
+func (c fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.db.record(query, args)
+	columns, rows := c.db.respond(query, args)
+
+	return &fakeRows{columns: columns, rows: rows}, nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+
+	return nil
+}
@@ -4,14 +4,24 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/chains-lab/city-petitions-svc/internal/app/models"
+	"github.com/chains-lab/city-petitions-svc/internal/cache"
+	"github.com/chains-lab/city-petitions-svc/internal/config"
 	"github.com/chains-lab/city-petitions-svc/internal/constant/enum"
+	"github.com/chains-lab/city-petitions-svc/internal/contentfilter"
 	"github.com/chains-lab/city-petitions-svc/internal/dbx"
 	"github.com/chains-lab/city-petitions-svc/internal/errx"
+	"github.com/chains-lab/city-petitions-svc/internal/events"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+	"github.com/chains-lab/city-petitions-svc/internal/metrics"
 	"github.com/chains-lab/city-petitions-svc/internal/pagination"
+	"github.com/chains-lab/city-petitions-svc/internal/rbac"
+	"github.com/chains-lab/city-petitions-svc/internal/tracing"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type petitionsQ interface {
@@ -22,8 +32,10 @@ type petitionsQ interface {
 	Select(ctx context.Context) ([]dbx.Petition, error)
 	Update(ctx context.Context, in dbx.UpdatePetitionInput) error
 	Delete(ctx context.Context) error
+	AnonymizeUser(ctx context.Context, userID uuid.UUID) (int64, error)
 
 	FilterID(id uuid.UUID) dbx.PetitionsQ
+	FilterIDs(ids ...uuid.UUID) dbx.PetitionsQ
 	FilterCityID(cityID uuid.UUID) dbx.PetitionsQ
 	FilterCreatorID(userID uuid.UUID) dbx.PetitionsQ
 	FilterStatus(status string) dbx.PetitionsQ
@@ -32,6 +44,7 @@ type petitionsQ interface {
 
 	FilterCreatedAt(t time.Time, after bool) dbx.PetitionsQ
 	FilterEndDate(t time.Time, after bool) dbx.PetitionsQ
+	FilterClosedBefore(t time.Time) dbx.PetitionsQ
 
 	TitleLike(s string) dbx.PetitionsQ
 
@@ -48,46 +61,259 @@ type signaturesQ interface {
 	Insert(ctx context.Context, input dbx.PetitionSignature) error
 	Get(ctx context.Context) (dbx.PetitionSignature, error)
 	Select(ctx context.Context) ([]dbx.PetitionSignature, error)
+	Update(ctx context.Context, in dbx.UpdatePetitionSignatureInput) (int64, error)
 	Delete(ctx context.Context) error
+	MoveToPetition(ctx context.Context, targetID uuid.UUID, sourceIDs ...uuid.UUID) (int64, error)
+	AnonymizeUser(ctx context.Context, userID uuid.UUID) (int64, error)
+	Pseudonymize(ctx context.Context, pseudonyms map[uuid.UUID]uuid.UUID, at time.Time) (int64, error)
+	Stats(ctx context.Context, petitionID uuid.UUID) (dbx.SignatureStats, error)
 
 	FilterID(id uuid.UUID) dbx.PetitionSignaturesQ
+	FilterIDs(ids ...uuid.UUID) dbx.PetitionSignaturesQ
 	FilterPetitionID(petitionID uuid.UUID) dbx.PetitionSignaturesQ
 	FilterUserID(userID uuid.UUID) dbx.PetitionSignaturesQ
+	FilterUserIDNot(userID uuid.UUID) dbx.PetitionSignaturesQ
+	FilterSessionID(sessionID uuid.UUID) dbx.PetitionSignaturesQ
+	FilterInvalidated(invalidated bool) dbx.PetitionSignaturesQ
+	FilterInvalidatedBy(userID uuid.UUID) dbx.PetitionSignaturesQ
+	FilterVisibility(visibility ...string) dbx.PetitionSignaturesQ
+	FilterVisibilityOrUserID(userID uuid.UUID, visibility ...string) dbx.PetitionSignaturesQ
+	FilterShownReason() dbx.PetitionSignaturesQ
+	FilterPseudonymized(pseudonymized bool) dbx.PetitionSignaturesQ
+	FilterMergedFrom(petitionIDs ...uuid.UUID) dbx.PetitionSignaturesQ
+	FilterUnchained() dbx.PetitionSignaturesQ
+	FilterCreatedAt(t time.Time, after bool) dbx.PetitionSignaturesQ
 
 	OrderByCreated(ascending bool) dbx.PetitionSignaturesQ
+	OrderByVerifiedFirst() dbx.PetitionSignaturesQ
 
 	Count(ctx context.Context) (uint64, error)
 	Page(limit, offset uint64) dbx.PetitionSignaturesQ
 }
 
+type signatureFlagsQ interface {
+	New() dbx.SignatureFlagsQ
+
+	Insert(ctx context.Context, flags ...dbx.SignatureFlag) error
+	Select(ctx context.Context) ([]dbx.SignatureFlag, error)
+	Update(ctx context.Context, in dbx.UpdateSignatureFlagInput) (int64, error)
+	AnonymizeUser(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	FilterIDs(ids ...uuid.UUID) dbx.SignatureFlagsQ
+	FilterSignatureIDs(signatureIDs ...uuid.UUID) dbx.SignatureFlagsQ
+	FilterPetitionID(petitionID uuid.UUID) dbx.SignatureFlagsQ
+	FilterCityID(cityID uuid.UUID) dbx.SignatureFlagsQ
+	FilterStatus(status string) dbx.SignatureFlagsQ
+	FilterReviewedBy(userID uuid.UUID) dbx.SignatureFlagsQ
+
+	OrderByCreated(ascending bool) dbx.SignatureFlagsQ
+
+	Count(ctx context.Context) (uint64, error)
+	Page(limit, offset uint64) dbx.SignatureFlagsQ
+}
+
+type contentFlagsQ interface {
+	New() dbx.ContentFlagsQ
+
+	Insert(ctx context.Context, flags ...dbx.ContentFlag) error
+	Select(ctx context.Context) ([]dbx.ContentFlag, error)
+	Update(ctx context.Context, in dbx.UpdateContentFlagInput) error
+	AnonymizeUser(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	FilterIDs(ids ...uuid.UUID) dbx.ContentFlagsQ
+	FilterPetitionID(petitionID uuid.UUID) dbx.ContentFlagsQ
+	FilterPetitionIDs(petitionIDs ...uuid.UUID) dbx.ContentFlagsQ
+	FilterCityID(cityID uuid.UUID) dbx.ContentFlagsQ
+	FilterStatus(status string) dbx.ContentFlagsQ
+	FilterReviewedBy(userID uuid.UUID) dbx.ContentFlagsQ
+
+	OrderByCreated(ascending bool) dbx.ContentFlagsQ
+
+	Count(ctx context.Context) (uint64, error)
+	Page(limit, offset uint64) dbx.ContentFlagsQ
+}
+
+type ResidencyVerifier interface {
+	IsResident(ctx context.Context, cityID, userID uuid.UUID) (bool, error)
+}
+
+type accessControl interface {
+	Can(subject rbac.Subject, perm rbac.Permission, cityID uuid.UUID) bool
+	Rules() []rbac.Rule
+}
+
+type updatesBroker interface {
+	Subscribe(petitionIDs ...uuid.UUID) *events.Subscription
+}
+
 type Petition struct {
-	q    petitionsQ
-	sigQ signaturesQ
+	db            *sql.DB
+	q             petitionsQ
+	sigQ          signaturesQ
+	flagsQ        signatureFlagsQ
+	contentFlagsQ contentFlagsQ
+	authorsQ      petitionAuthorsQ
+	chainQ        signatureChainQ
+	saltsQ        signerSaltsQ
+
+	commentsQ       commentsQ
+	commentReportsQ commentReportsQ
+	idempotencyQ    idempotencyKeysQ
+
+	verification     verificationPolicy
+	authors          authorsPolicy
+	commentRules     commentRules
+	retention        retentionPolicy
+	pseudonymKey     []byte
+	dailyCreateLimit int
+	fraud            fraudDetector
+	duplicates       duplicateDetector
+	residency        ResidencyVerifier
+	access           accessControl
+	updates          updatesBroker
+	cache            petitionsCache
+	content          contentFilter
 }
 
-func NewPetition(pg *sql.DB) Petition {
+func NewPetition(
+	cfg config.Config,
+	pg *sql.DB,
+	residency ResidencyVerifier,
+	access accessControl,
+	updates updatesBroker,
+	c cache.Cache,
+	content contentFilter,
+) (Petition, error) {
+	if err := checkPseudonymKey(cfg.Petitions.PseudonymKey); err != nil {
+		return Petition{}, err
+	}
+
 	return Petition{
-		q:    dbx.NewPetitionsQ(pg),
-		sigQ: dbx.NewPetitionSignaturesQ(pg),
+		db:               pg,
+		q:                dbx.NewPetitionsQ(pg),
+		sigQ:             dbx.NewPetitionSignaturesQ(pg),
+		flagsQ:           dbx.NewSignatureFlagsQ(pg),
+		contentFlagsQ:    dbx.NewContentFlagsQ(pg),
+		authorsQ:         dbx.NewPetitionAuthorsQ(pg),
+		chainQ:           dbx.NewSignatureChainQ(pg),
+		saltsQ:           dbx.NewSignerSaltsQ(pg),
+		commentsQ:        dbx.NewPetitionCommentsQ(pg),
+		commentReportsQ:  dbx.NewCommentReportsQ(pg),
+		idempotencyQ:     dbx.NewIdempotencyKeysQ(pg),
+		verification:     newVerificationPolicy(cfg),
+		authors:          newAuthorsPolicy(cfg),
+		commentRules:     newCommentRules(cfg),
+		retention:        newRetentionPolicy(cfg),
+		pseudonymKey:     []byte(cfg.Petitions.PseudonymKey),
+		dailyCreateLimit: cfg.Petitions.DailyCreateLimit,
+		fraud:            newFraudDetector(cfg),
+		duplicates:       newDuplicateDetector(cfg),
+		residency:        residency,
+		access:           access,
+		updates:          updates,
+		cache:            newPetitionsCache(cfg, c),
+		content:          content,
+	}, nil
+}
+
+// minPseudonymKeySize is the shortest pseudonym key accepted, the size of the HMAC-SHA256 output.
+const minPseudonymKeySize = 32
+
+// checkPseudonymKey refuses a missing or short pseudonym key. Signer hashes are written once
+// and can not be rekeyed, so a weak key can not be fixed later.
+func checkPseudonymKey(key string) error {
+	switch {
+	case key == "":
+		return errors.New("petitions.pseudonym_key is not set")
+	case len(key) < minPseudonymKeySize:
+		return fmt.Errorf("petitions.pseudonym_key must be at least %d bytes long", minPseudonymKeySize)
 	}
+
+	return nil
+}
+
+// Initiator describes the user performing an action, as taken from the request token.
+type Initiator struct {
+	ID        uuid.UUID
+	SessionID uuid.UUID
+	Verified  bool
+	Role      string
+}
+
+func (i Initiator) subject() rbac.Subject {
+	return rbac.Subject{UserID: i.ID, Role: i.Role}
 }
 
 type CreatePetitionInput struct {
 	Title       string
 	Description string
+	// Force creates the petition even when similar open petitions exist,
+	// after the author has seen them.
+	Force bool
+	// Draft keeps the petition unpublished so co-authors can be invited first.
+	// Petitions are always drafts in cities that require co-authors.
+	Draft bool
 }
 
-func (p Petition) CreatePetition(ctx context.Context, cityID, creatorID uuid.UUID, input CreatePetitionInput) (models.Petition, error) {
+func (p Petition) CreatePetition(ctx context.Context, cityID uuid.UUID, initiator Initiator, input CreatePetitionInput) (models.Petition, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.CreatePetition", attribute.String("city.id", cityID.String()))
+	defer span.End()
+
+	if err := p.verification.check(ctx, cityID, initiator, verificationActionCreate); err != nil {
+		return models.Petition{}, err
+	}
+
+	if err := p.checkResidency(ctx, cityID, initiator.ID); err != nil {
+		return models.Petition{}, err
+	}
+
+	if err := p.checkDailyCreateLimit(ctx, initiator.ID); err != nil {
+		return models.Petition{}, err
+	}
+
+	verdict, err := p.screenContent(ctx,
+		contentfilter.Field{Name: "title", Text: input.Title},
+		contentfilter.Field{Name: "description", Text: input.Description},
+	)
+	if err != nil {
+		return models.Petition{}, err
+	}
+
+	if !input.Force {
+		duplicates, err := p.FindDuplicates(ctx, cityID, input.Title)
+		if err != nil {
+			return models.Petition{}, err
+		}
+
+		if len(duplicates) > 0 {
+			return models.Petition{}, errx.RaisePetitionDuplicatesFound(
+				ctx,
+				fmt.Errorf("%d open petitions in city %s resemble '%s'", len(duplicates), cityID, input.Title),
+				duplicates,
+			)
+		}
+	}
+
+	status := enum.PetitionPublished
+	switch {
+	case input.Draft || p.authors.forCity(cityID).MinCoAuthors > 0:
+		// drafts are screened again when published
+		status = enum.PetitionDraft
+		verdict = contentfilter.Verdict{}
+	case verdict.NeedsModeration():
+		status = enum.PetitionModeration
+	}
+
 	petitionID := uuid.New()
 	now := time.Now().UTC()
 
 	petition := dbx.Petition{
 		ID:          petitionID,
 		CityID:      cityID,
-		CreatorID:   creatorID,
+		CreatorID:   initiator.ID,
 		Title:       input.Title,
 		Description: input.Description,
-		Status:      enum.PetitionPublished,
+		Status:      status,
 		Signatures:  0,
 		Goal:        10000, // Default goal is 10,000 signatures
 		Reply:       "",
@@ -96,17 +322,37 @@ func (p Petition) CreatePetition(ctx context.Context, cityID, creatorID uuid.UUI
 		UpdatedAt:   now,
 	}
 
-	if err := p.q.New().Insert(ctx, petition); err != nil {
+	err = dbx.Transaction(ctx, p.db, func(ctx context.Context) error {
+		if err := p.q.New().Insert(ctx, petition); err != nil {
+			return err
+		}
+
+		return p.contentFlagsQ.New().Insert(ctx, contentFlags(petitionID, verdict, now)...)
+	})
+	if err != nil {
 		switch {
 		default:
 			return models.Petition{}, errx.RaiseInternal(ctx, err)
 		}
 	}
 
+	p.cache.invalidate(ctx)
+	metrics.PetitionCreated(cityID)
+
 	return petitionModel(petition), nil
 }
 
-func (p Petition) GetPetition(ctx context.Context, petitionID uuid.UUID) (models.Petition, error) {
+// GetPetition returns a petition to viewer, which is nil for anonymous callers. Drafts are shown
+// only to their authors and moderators, held petitions only to their creator and moderators;
+// others get NotFound. Only publicly visible petitions are cached.
+func (p Petition) GetPetition(ctx context.Context, viewer *Initiator, petitionID uuid.UUID) (models.Petition, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.GetPetition", attribute.String("petition.id", petitionID.String()))
+	defer span.End()
+
+	if cached, ok := p.cache.getPetition(ctx, petitionID); ok && !hiddenStatus(cached.Status) {
+		return cached, nil
+	}
+
 	petition, err := p.q.New().FilterID(petitionID).Get(ctx)
 	if err != nil {
 		switch {
@@ -117,10 +363,62 @@ func (p Petition) GetPetition(ctx context.Context, petitionID uuid.UUID) (models
 		}
 	}
 
-	return petitionModel(petition), nil
+	if err := p.checkVisible(ctx, viewer, petition); err != nil {
+		return models.Petition{}, err
+	}
+
+	res := []models.Petition{petitionModel(petition)}
+	if err := p.withCoAuthors(ctx, res); err != nil {
+		return models.Petition{}, err
+	}
+
+	if !hiddenStatus(petition.Status) {
+		p.cache.setPetition(ctx, res[0])
+	}
+
+	return res[0], nil
+}
+
+// hiddenStatus reports whether petitions in status are hidden from the public.
+func hiddenStatus(status string) bool {
+	return status == enum.PetitionDraft || status == enum.PetitionModeration
+}
+
+// checkVisible fails with NotFound unless viewer may see petition: drafts are visible to their
+// authors, held petitions to their creator, and both to moderators of the petition's city.
+func (p Petition) checkVisible(ctx context.Context, viewer *Initiator, petition dbx.Petition) error {
+	if !hiddenStatus(petition.Status) {
+		return nil
+	}
+
+	notFound := errx.RaisePetitionNotFoundByID(ctx, fmt.Errorf("petition is %s", petition.Status), petition.ID)
+
+	switch {
+	case viewer == nil:
+		return notFound
+	case petition.CreatorID == viewer.ID:
+		return nil
+	case p.access.Can(viewer.subject(), rbac.PermissionPetitionModerate, petition.CityID):
+		return nil
+	case petition.Status != enum.PetitionDraft:
+		return notFound
+	}
+
+	author, err := p.isAuthor(ctx, viewer.ID, petition)
+	if err != nil {
+		return errx.RaiseInternal(ctx, err)
+	}
+	if !author {
+		return notFound
+	}
+
+	return nil
 }
 
-func (p Petition) ApprovePetition(ctx context.Context, petitionID uuid.UUID, reply string) (models.Petition, error) {
+func (p Petition) ApprovePetition(ctx context.Context, initiator Initiator, petitionID uuid.UUID, reply string) (models.Petition, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.ApprovePetition", attribute.String("petition.id", petitionID.String()))
+	defer span.End()
+
 	petition, err := p.q.New().FilterID(petitionID).Get(ctx)
 	if err != nil {
 		switch {
@@ -131,14 +429,49 @@ func (p Petition) ApprovePetition(ctx context.Context, petitionID uuid.UUID, rep
 		}
 	}
 
+	if err := p.checkPermission(ctx, initiator, rbac.PermissionPetitionAnswer, petition.CityID); err != nil {
+		return models.Petition{}, err
+	}
+	if err := checkAnswerable(ctx, petition); err != nil {
+		return models.Petition{}, err
+	}
+
+	// official replies are never held for moderation, matches are only flagged
+	verdict, err := p.screenContent(ctx, contentfilter.Field{Name: "reply", Text: reply})
+	if err != nil {
+		return models.Petition{}, err
+	}
+
 	status := enum.PetitionApproved
+	now := time.Now().UTC()
 
 	updateInput := dbx.UpdatePetitionInput{
-		Status: &status,
-		Reply:  &reply,
+		Status:    &status,
+		Reply:     &reply,
+		UpdatedAt: &now,
+		ClosedAt:  &now,
 	}
 
-	if err := p.q.New().FilterID(petitionID).Update(ctx, updateInput); err != nil {
+	var rejected error
+	err = dbx.Transaction(ctx, p.db, func(ctx context.Context) error {
+		locked, err := p.q.New().FilterID(petitionID).ForUpdate().Get(ctx)
+		if err != nil {
+			return err
+		}
+		if rejected = checkAnswerable(ctx, locked); rejected != nil {
+			return rejected
+		}
+
+		if err := p.q.New().FilterID(petitionID).FilterStatus(enum.PetitionPublished).Update(ctx, updateInput); err != nil {
+			return err
+		}
+
+		return p.contentFlagsQ.New().Insert(ctx, contentFlags(petitionID, verdict, now)...)
+	})
+	if rejected != nil {
+		return models.Petition{}, rejected
+	}
+	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
 			return models.Petition{}, errx.RaisePetitionNotFoundByID(ctx, err, petitionID)
@@ -147,9 +480,12 @@ func (p Petition) ApprovePetition(ctx context.Context, petitionID uuid.UUID, rep
 		}
 	}
 
+	p.cache.invalidate(ctx, petitionID)
+	metrics.PetitionAnswered(petition.CityID, status)
+
 	//TODO add kafka event for petition approval
 
-	return models.Petition{
+	res := []models.Petition{{
 		ID:          petition.ID,
 		CityID:      petition.CityID,
 		CreatorID:   petition.CreatorID,
@@ -161,11 +497,19 @@ func (p Petition) ApprovePetition(ctx context.Context, petitionID uuid.UUID, rep
 		Reply:       reply,
 		EndDate:     petition.EndDate,
 		CreatedAt:   petition.CreatedAt,
-		UpdatedAt:   petition.UpdatedAt,
-	}, nil
+		UpdatedAt:   now,
+	}}
+	if err := p.withCoAuthors(ctx, res); err != nil {
+		return models.Petition{}, err
+	}
+
+	return res[0], nil
 }
 
-func (p Petition) RejectPetition(ctx context.Context, petitionID uuid.UUID, reply string) (models.Petition, error) {
+func (p Petition) RejectPetition(ctx context.Context, initiator Initiator, petitionID uuid.UUID, reply string) (models.Petition, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.RejectPetition", attribute.String("petition.id", petitionID.String()))
+	defer span.End()
+
 	petition, err := p.q.New().FilterID(petitionID).Get(ctx)
 	if err != nil {
 		switch {
@@ -176,15 +520,50 @@ func (p Petition) RejectPetition(ctx context.Context, petitionID uuid.UUID, repl
 		}
 	}
 
+	if err := p.checkPermission(ctx, initiator, rbac.PermissionPetitionAnswer, petition.CityID); err != nil {
+		return models.Petition{}, err
+	}
+	if err := checkAnswerable(ctx, petition); err != nil {
+		return models.Petition{}, err
+	}
+
+	// official replies are never held for moderation, matches are only flagged
+	verdict, err := p.screenContent(ctx, contentfilter.Field{Name: "reply", Text: reply})
+	if err != nil {
+		return models.Petition{}, err
+	}
+
 	status := enum.PetitionRejected
+	now := time.Now().UTC()
 
 	updateInput := dbx.UpdatePetitionInput{
-		Status:  &status,
-		Reply:   &reply,
-		EndDate: &petition.EndDate,
+		Status:    &status,
+		Reply:     &reply,
+		EndDate:   &petition.EndDate,
+		UpdatedAt: &now,
+		ClosedAt:  &now,
 	}
 
-	if err := p.q.New().FilterID(petitionID).Update(ctx, updateInput); err != nil {
+	var rejected error
+	err = dbx.Transaction(ctx, p.db, func(ctx context.Context) error {
+		locked, err := p.q.New().FilterID(petitionID).ForUpdate().Get(ctx)
+		if err != nil {
+			return err
+		}
+		if rejected = checkAnswerable(ctx, locked); rejected != nil {
+			return rejected
+		}
+
+		if err := p.q.New().FilterID(petitionID).FilterStatus(enum.PetitionPublished).Update(ctx, updateInput); err != nil {
+			return err
+		}
+
+		return p.contentFlagsQ.New().Insert(ctx, contentFlags(petitionID, verdict, now)...)
+	})
+	if rejected != nil {
+		return models.Petition{}, rejected
+	}
+	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
 			return models.Petition{}, errx.RaisePetitionNotFoundByID(ctx, err, petitionID)
@@ -193,9 +572,12 @@ func (p Petition) RejectPetition(ctx context.Context, petitionID uuid.UUID, repl
 		}
 	}
 
+	p.cache.invalidate(ctx, petitionID)
+	metrics.PetitionAnswered(petition.CityID, status)
+
 	//TODO add kafka event for petition rejection
 
-	return models.Petition{
+	res := []models.Petition{{
 		ID:          petition.ID,
 		CityID:      petition.CityID,
 		CreatorID:   petition.CreatorID,
@@ -207,45 +589,171 @@ func (p Petition) RejectPetition(ctx context.Context, petitionID uuid.UUID, repl
 		Reply:       reply,
 		EndDate:     petition.EndDate,
 		CreatedAt:   petition.CreatedAt,
-		UpdatedAt:   petition.UpdatedAt,
-	}, nil
+		UpdatedAt:   now,
+	}}
+	if err := p.withCoAuthors(ctx, res); err != nil {
+		return models.Petition{}, err
+	}
+
+	return res[0], nil
+}
+
+// checkAnswerable fails unless petition is published. Drafts and petitions held for moderation
+// have not passed review yet, answered and merged petitions are closed.
+func checkAnswerable(ctx context.Context, petition dbx.Petition) error {
+	if petition.Status != enum.PetitionPublished {
+		return errx.RaisePetitionIsNotAvailable(ctx, fmt.Errorf("petition %s is %s", petition.ID, petition.Status), petition.ID.String())
+	}
+
+	return nil
 }
 
-func (p Petition) SignPetition(ctx context.Context, initiatorID, petitionID uuid.UUID) (models.PetitionSignature, error) {
+type SignPetitionInput struct {
+	Reason     *string // optional, shown next to the signature according to Visibility
+	Visibility string  // one of enum.GetAllSignatureVisibility, anonymous when empty
+}
+
+func (p Petition) SignPetition(ctx context.Context, initiator Initiator, petitionID uuid.UUID, in SignPetitionInput) (models.PetitionSignature, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.SignPetition", attribute.String("petition.id", petitionID.String()))
+	defer span.End()
+
+	petition, err := p.q.New().FilterID(petitionID).Get(ctx)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return models.PetitionSignature{}, errx.RaisePetitionNotFoundByID(ctx, err, petitionID)
+		default:
+			return models.PetitionSignature{}, errx.RaiseInternal(ctx, err)
+		}
+	}
+
+	if err := checkSignable(ctx, petition, time.Now()); err != nil {
+		return models.PetitionSignature{}, err
+	}
+
+	if err := p.verification.check(ctx, petition.CityID, initiator, verificationActionSign); err != nil {
+		return models.PetitionSignature{}, err
+	}
+
+	if err := p.checkResidency(ctx, petition.CityID, initiator.ID); err != nil {
+		return models.PetitionSignature{}, err
+	}
+
 	signatureID := uuid.New()
-	now := time.Now().UTC()
+	// Postgres keeps microseconds, the receipt must hash the time as stored
+	now := time.Now().UTC().Truncate(time.Microsecond)
 
 	signature := dbx.PetitionSignature{
 		ID:         signatureID,
 		PetitionID: petitionID,
-		UserID:     initiatorID,
+		UserID:     initiator.ID,
 		CreatedAt:  now,
+		Verified:   initiator.Verified,
+		SessionID:  uuid.NullUUID{UUID: initiator.SessionID, Valid: initiator.SessionID != uuid.Nil},
+		Visibility: enum.SignatureAnonymous,
+	}
+
+	if in.Visibility != "" {
+		signature.Visibility = in.Visibility
+	}
+
+	if in.Reason != nil {
+		verdict, err := p.screenContent(ctx, contentfilter.Field{Name: "reason", Text: *in.Reason})
+		if err != nil {
+			return models.PetitionSignature{}, err
+		}
+
+		// the signature counts anyway, a held reason is shown to officials and admins only
+		signature.Reason = sql.NullString{String: *in.Reason, Valid: true}
+		signature.ReasonHidden = verdict.NeedsModeration()
 	}
 
-	if err := p.sigQ.New().Insert(ctx, signature); err != nil {
+	var (
+		receipt  []dbx.SignatureChainEntry
+		rejected error
+	)
+	err = dbx.Transaction(ctx, p.db, func(ctx context.Context) error {
+		// the chain of a petition grows one signature at a time
+		locked, err := p.q.New().FilterID(petitionID).ForUpdate().Get(ctx)
+		if err != nil {
+			return err
+		}
+		if rejected = checkSignable(ctx, locked, now); rejected != nil {
+			return rejected
+		}
+
+		// UNIQUE (petition_id, user_id) no longer matches a signature whose signer was pseudonymized
+		salts, err := p.signerSalts(ctx, initiator.ID)
+		if err != nil {
+			return err
+		}
+		_, err = p.sigQ.New().FilterPetitionID(petitionID).FilterUserID(p.pseudonym(petitionID, initiator.ID, salts[initiator.ID])).Get(ctx)
 		switch {
-		case errors.Is(err, sql.ErrNoRows):
-			_, err = p.sigQ.New().FilterID(petitionID).FilterUserID(initiatorID).Get(ctx)
-			if err == nil {
-				return models.PetitionSignature{}, errx.RaisePetitionSignaturesAlreadyExists(ctx, err, petitionID, initiatorID)
-			}
-
-			_, err := p.q.New().FilterID(petitionID).Get(ctx)
-			if err != nil {
-				switch {
-				case errors.Is(err, sql.ErrNoRows):
-					return models.PetitionSignature{}, errx.RaisePetitionNotFoundByID(ctx, err, petitionID)
-				}
-			}
+		case err == nil:
+			rejected = errx.RaisePetitionSignaturesAlreadyExists(ctx, fmt.Errorf("user %s signed before being pseudonymized", initiator.ID), petitionID, initiator.ID)
+			return rejected
+		case !errors.Is(err, sql.ErrNoRows):
+			return err
+		}
+
+		if err := p.sigQ.New().Insert(ctx, signature); err != nil {
+			return err
+		}
+
+		receipt, err = p.appendToChain(ctx, petitionID, []dbx.PetitionSignature{signature}, now)
+		return err
+	})
+	if rejected != nil {
+		return models.PetitionSignature{}, rejected
+	}
+	if err != nil {
+		_, getErr := p.sigQ.New().FilterPetitionID(petitionID).FilterUserID(initiator.ID).Get(ctx)
+		switch {
+		case getErr == nil:
+			return models.PetitionSignature{}, errx.RaisePetitionSignaturesAlreadyExists(ctx, err, petitionID, initiator.ID)
 		default:
 			return models.PetitionSignature{}, errx.RaiseInternal(ctx, err)
 		}
 	}
 
-	return petitionSignatureModel(signature), nil
+	p.cache.invalidateCounts(ctx, petitionID)
+	metrics.PetitionSigned(petition.CityID)
+
+	if err := p.analyzeSignature(ctx, signature); err != nil {
+		logger.Log(ctx).WithError(err).Warnf("failed to analyze signature %s", signature.ID)
+	}
+
+	res := petitionSignatureModel(signature)
+	res.Receipt = receiptModel(receipt[0])
+
+	return res, nil
+}
+
+// checkSignable fails unless petition is published and its end date is after now.
+func checkSignable(ctx context.Context, petition dbx.Petition, now time.Time) error {
+	var cause error
+	switch {
+	case petition.Status == enum.PetitionModeration:
+		cause = fmt.Errorf("petition %s is held for moderation", petition.ID)
+	case petition.Status == enum.PetitionMerged:
+		cause = fmt.Errorf("petition %s was merged into %s", petition.ID, petition.MergedInto.UUID)
+	case petition.Status == enum.PetitionDraft:
+		cause = fmt.Errorf("petition %s is a draft", petition.ID)
+	case petition.Status != enum.PetitionPublished:
+		cause = fmt.Errorf("petition %s is %s", petition.ID, petition.Status)
+	case !petition.EndDate.After(now):
+		cause = fmt.Errorf("petition %s ended at %s", petition.ID, petition.EndDate)
+	default:
+		return nil
+	}
+
+	return errx.RaisePetitionIsNotAvailable(ctx, cause, petition.ID.String())
 }
 
 func (p Petition) GetSignatureByID(ctx context.Context, userID, petitionID uuid.UUID) (models.PetitionSignature, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.GetSignatureByID", attribute.String("petition.id", petitionID.String()))
+	defer span.End()
+
 	res, err := p.sigQ.New().FilterID(petitionID).FilterUserID(userID).Get(ctx)
 	if err != nil {
 		switch {
@@ -260,6 +768,9 @@ func (p Petition) GetSignatureByID(ctx context.Context, userID, petitionID uuid.
 }
 
 func (p Petition) GetSignatureByUserIDAndSigID(ctx context.Context, sigID uuid.UUID) (models.PetitionSignature, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.GetSignatureByUserIDAndSigID", attribute.String("signature.id", sigID.String()))
+	defer span.End()
+
 	res, err := p.sigQ.New().FilterID(sigID).Get(ctx)
 	if err != nil {
 		switch {
@@ -273,6 +784,55 @@ func (p Petition) GetSignatureByUserIDAndSigID(ctx context.Context, sigID uuid.U
 	return petitionSignatureModel(res), nil
 }
 
+// WatchPetitions subscribes to updates of the given petitions and returns their current state.
+// The subscription is taken before the snapshot is read, so no change between the two is lost.
+// Petitions viewer may not see are reported as not found, as by GetPetition.
+// Callers must close the subscription.
+func (p Petition) WatchPetitions(ctx context.Context, viewer *Initiator, petitionIDs []uuid.UUID) ([]models.PetitionUpdate, *events.Subscription, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.WatchPetitions", attribute.Int("petitions.count", len(petitionIDs)))
+	defer span.End()
+
+	sub := p.updates.Subscribe(petitionIDs...)
+
+	petitions, err := p.q.New().FilterIDs(petitionIDs...).Select(ctx)
+	if err != nil {
+		sub.Close()
+
+		switch {
+		default:
+			return nil, nil, errx.RaiseInternal(ctx, err)
+		}
+	}
+
+	found := make(map[uuid.UUID]struct{}, len(petitions))
+	snapshot := make([]models.PetitionUpdate, 0, len(petitions))
+	for _, petition := range petitions {
+		if err := p.checkVisible(ctx, viewer, petition); err != nil {
+			sub.Close()
+
+			return nil, nil, err
+		}
+
+		found[petition.ID] = struct{}{}
+		snapshot = append(snapshot, models.PetitionUpdate{
+			PetitionID: petition.ID,
+			Signatures: petition.Signatures,
+			Status:     petition.Status,
+			UpdatedAt:  petition.UpdatedAt,
+		})
+	}
+
+	for _, id := range petitionIDs {
+		if _, ok := found[id]; !ok {
+			sub.Close()
+
+			return nil, nil, errx.RaisePetitionNotFoundByID(ctx, sql.ErrNoRows, id)
+		}
+	}
+
+	return snapshot, sub, nil
+}
+
 type ListPetitionsFilter struct {
 	CityID    *uuid.UUID
 	CreatorID *uuid.UUID
@@ -296,6 +856,9 @@ func (p Petition) ListPetitions(
 	sort ListPetitionsSort,
 	pag pagination.Request,
 ) ([]models.Petition, pagination.Response, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.ListPetitions")
+	defer span.End()
+
 	query := p.q.New()
 
 	if filter.CityID != nil {
@@ -323,9 +886,11 @@ func (p Petition) ListPetitions(
 	if available || expired {
 		statuses = append(statuses, enum.PetitionPublished)
 	}
-	if len(statuses) > 0 {
-		query = query.FilterStatusIn(statuses...)
+	if len(statuses) == 0 {
+		// petitions held for moderation are not listed
+		statuses = append(statuses, enum.PetitionPublished, enum.PetitionApproved, enum.PetitionRejected)
 	}
+	query = query.FilterStatusIn(statuses...)
 
 	now := time.Now().UTC()
 	switch {
@@ -348,6 +913,13 @@ func (p Petition) ListPetitions(
 		query = query.OrderByCreated(false)
 	}
 
+	cacheKey, cacheable := p.cache.listKey(ctx, filter, sort, pag)
+	if cacheable {
+		if cached, ok := p.cache.getList(ctx, cacheKey); ok {
+			return cached.Petitions, cached.Page, nil
+		}
+	}
+
 	limit, offset := pagination.CalculateLimitOffset(pag)
 
 	petitions, err := query.Page(limit, offset).Select(ctx)
@@ -373,11 +945,21 @@ func (p Petition) ListPetitions(
 		modelsPetitions = append(modelsPetitions, petitionModel(p))
 	}
 
-	return modelsPetitions, pagination.Response{
+	if err := p.withCoAuthors(ctx, modelsPetitions); err != nil {
+		return nil, pagination.Response{}, err
+	}
+
+	page := pagination.Response{
 		Page:  pag.Page,
 		Size:  pag.Size,
 		Total: total,
-	}, nil
+	}
+
+	if cacheable {
+		p.cache.setList(ctx, cacheKey, cachedPetitionList{Petitions: modelsPetitions, Page: page})
+	}
+
+	return modelsPetitions, page, nil
 }
 
 type ListPetitionsSignFilter struct {
@@ -390,12 +972,19 @@ type ListPetitionsSignSort struct {
 	Oldest bool // Sort by oldest first
 }
 
+// ListSignatures lists signatures visible to viewer, who is nil for anonymous callers.
+// Officials and admins of the petition's city get the full list, signers always see their own
+// signatures and everyone else sees only the signers who chose to be listed publicly.
 func (p Petition) ListSignatures(
 	ctx context.Context,
+	viewer *Initiator,
 	filter ListPetitionsSignFilter,
 	sort ListPetitionsSignSort,
 	pag pagination.Request,
 ) ([]models.PetitionSignature, pagination.Response, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.ListSignatures")
+	defer span.End()
+
 	query := p.sigQ.New()
 
 	if filter.PetitionID != nil {
@@ -405,6 +994,15 @@ func (p Petition) ListSignatures(
 		query = query.FilterUserID(*filter.UserID)
 	}
 
+	full, err := p.listsAllSigners(ctx, viewer, filter.PetitionID)
+	if err != nil {
+		return nil, pagination.Response{}, err
+	}
+
+	if !full {
+		query = visibleSignatures(query, viewer)
+	}
+
 	switch {
 	case sort.Oldest:
 		query = query.OrderByCreated(true)
@@ -412,27 +1010,90 @@ func (p Petition) ListSignatures(
 		query = query.OrderByCreated(false)
 	}
 
+	total, err := query.Count(ctx)
+	if err != nil {
+		return nil, pagination.Response{}, errx.RaiseInternal(ctx, err)
+	}
+
 	limit, offset := pagination.CalculateLimitOffset(pag)
 
 	signatures, err := query.Page(limit, offset).Select(ctx)
 	if err != nil {
-		switch {
-		case errors.Is(err, sql.ErrNoRows):
-			return []models.PetitionSignature{}, pagination.Response{}, nil // No signatures found, return
-		default:
-			return nil, pagination.Response{}, errx.RaiseInternal(ctx, err)
-		}
+		return nil, pagination.Response{}, errx.RaiseInternal(ctx, err)
 	}
 
-	var modelsSignatures []models.PetitionSignature
+	modelsSignatures := make([]models.PetitionSignature, 0, len(signatures))
 	for _, sig := range signatures {
-		modelsSignatures = append(modelsSignatures, petitionSignatureModel(sig))
+		modelsSignatures = append(modelsSignatures, signatureFor(sig, viewer, full))
+	}
+
+	return modelsSignatures, pagination.Response{
+		Page:  pag.Page,
+		Size:  pag.Size,
+		Total: total,
+	}, nil
+}
+
+func (p Petition) checkPermission(ctx context.Context, initiator Initiator, perm rbac.Permission, cityID uuid.UUID) error {
+	if !p.access.Can(initiator.subject(), perm, cityID) {
+		return errx.RaiseNoPermission(
+			ctx,
+			fmt.Errorf("user %s with role %s has no permission %s in city %s", initiator.ID, initiator.Role, perm, cityID),
+			initiator.ID,
+			string(perm),
+			cityID,
+		)
 	}
 
-	return modelsSignatures, pagination.Response{}, errx.RaiseInternal(ctx, err)
+	return nil
+}
+
+// checkDailyCreateLimit fails when the user has already created dailyCreateLimit petitions since UTC midnight.
+func (p Petition) checkDailyCreateLimit(ctx context.Context, userID uuid.UUID) error {
+	if p.dailyCreateLimit <= 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	dayStart := now.Truncate(24 * time.Hour)
+
+	created, err := p.q.New().FilterCreatorID(userID).FilterCreatedAt(dayStart, true).Count(ctx)
+	if err != nil {
+		return errx.RaiseInternal(ctx, err)
+	}
+
+	if created >= uint64(p.dailyCreateLimit) {
+		return errx.RaisePetitionDailyLimitExceeded(
+			ctx,
+			fmt.Errorf("user %s created %d petitions since %s", userID, created, dayStart.Format(time.RFC3339)),
+			userID,
+			p.dailyCreateLimit,
+			dayStart.Add(24*time.Hour).Sub(now),
+		)
+	}
+
+	return nil
+}
+
+func (p Petition) checkResidency(ctx context.Context, cityID, userID uuid.UUID) error {
+	resident, err := p.residency.IsResident(ctx, cityID, userID)
+	if err != nil {
+		return errx.RaiseInternal(ctx, err)
+	}
+
+	if !resident {
+		return errx.RaiseUserNotResident(ctx, fmt.Errorf("user %s is not a resident of city %s", userID, cityID), userID, cityID)
+	}
+
+	return nil
 }
 
 func petitionModel(p dbx.Petition) models.Petition {
+	var mergedInto *uuid.UUID
+	if p.MergedInto.Valid {
+		mergedInto = &p.MergedInto.UUID
+	}
+
 	return models.Petition{
 		ID:          p.ID,
 		CityID:      p.CityID,
@@ -446,6 +1107,7 @@ func petitionModel(p dbx.Petition) models.Petition {
 		EndDate:     p.EndDate,
 		CreatedAt:   p.CreatedAt,
 		UpdatedAt:   p.UpdatedAt,
+		MergedInto:  mergedInto,
 	}
 }
 
@@ -455,5 +1117,12 @@ func petitionSignatureModel(sig dbx.PetitionSignature) models.PetitionSignature
 		PetitionID: sig.PetitionID,
 		UserID:     sig.UserID,
 		CreatedAt:  sig.CreatedAt,
+		Verified:   sig.Verified,
+
+		Invalidated:       sig.Invalidated,
+		InvalidatedReason: sig.InvalidatedReason.String,
+
+		Reason:     sig.Reason.String,
+		Visibility: sig.Visibility,
 	}
 }
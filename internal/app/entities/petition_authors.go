@@ -0,0 +1,475 @@
+package entities
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chains-lab/city-petitions-svc/internal/app/models"
+	"github.com/chains-lab/city-petitions-svc/internal/config"
+	"github.com/chains-lab/city-petitions-svc/internal/constant/enum"
+	"github.com/chains-lab/city-petitions-svc/internal/contentfilter"
+	"github.com/chains-lab/city-petitions-svc/internal/dbx"
+	"github.com/chains-lab/city-petitions-svc/internal/errx"
+	"github.com/chains-lab/city-petitions-svc/internal/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type petitionAuthorsQ interface {
+	New() dbx.PetitionAuthorsQ
+
+	Insert(ctx context.Context, input dbx.PetitionAuthor) error
+	Get(ctx context.Context) (dbx.PetitionAuthor, error)
+	Select(ctx context.Context) ([]dbx.PetitionAuthor, error)
+	Update(ctx context.Context, in dbx.UpdatePetitionAuthorInput) error
+	Delete(ctx context.Context) error
+	AnonymizeUser(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	FilterPetitionID(petitionID uuid.UUID) dbx.PetitionAuthorsQ
+	FilterPetitionIDs(petitionIDs ...uuid.UUID) dbx.PetitionAuthorsQ
+	FilterUserID(userID uuid.UUID) dbx.PetitionAuthorsQ
+	FilterStatus(status ...string) dbx.PetitionAuthorsQ
+
+	OrderByCreated(ascending bool) dbx.PetitionAuthorsQ
+
+	Count(ctx context.Context) (uint64, error)
+}
+
+type authorsPolicy struct {
+	global config.AuthorsPolicy
+	cities map[string]config.AuthorsPolicy
+}
+
+func newAuthorsPolicy(cfg config.Config) authorsPolicy {
+	return authorsPolicy{
+		global: cfg.Petitions.Authors.AuthorsPolicy,
+		cities: cfg.Petitions.Authors.Cities,
+	}
+}
+
+// forCity returns the city override if one is configured, otherwise the global policy.
+func (a authorsPolicy) forCity(cityID uuid.UUID) config.AuthorsPolicy {
+	if policy, ok := a.cities[cityID.String()]; ok {
+		return policy
+	}
+
+	return a.global
+}
+
+// InviteCoAuthor invites userID to author a draft. Only the creator may invite, and a user who
+// declined before may be invited again.
+func (p Petition) InviteCoAuthor(ctx context.Context, initiator Initiator, petitionID, userID uuid.UUID) (models.PetitionAuthor, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.InviteCoAuthor", attribute.String("petition.id", petitionID.String()))
+	defer span.End()
+
+	petition, err := p.getDraft(ctx, petitionID)
+	if err != nil {
+		return models.PetitionAuthor{}, err
+	}
+
+	if petition.CreatorID != initiator.ID {
+		return models.PetitionAuthor{}, errx.RaiseNotPetitionAuthor(ctx, fmt.Errorf("only the creator may invite co-authors"), petitionID, initiator.ID)
+	}
+	if userID == petition.CreatorID {
+		return models.PetitionAuthor{}, errx.RaisePetitionAuthorAlreadyExists(ctx, fmt.Errorf("user %s created the petition", userID), petitionID, userID)
+	}
+
+	existing, err := p.authorsQ.New().FilterPetitionID(petitionID).FilterUserID(userID).Get(ctx)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return models.PetitionAuthor{}, errx.RaiseInternal(ctx, err)
+	case existing.Status != enum.PetitionAuthorDeclined:
+		return models.PetitionAuthor{}, errx.RaisePetitionAuthorAlreadyExists(ctx, fmt.Errorf("user %s is %s", userID, existing.Status), petitionID, userID)
+	}
+
+	if limit := p.authors.forCity(petition.CityID).MaxCoAuthors; limit > 0 {
+		count, err := p.authorsQ.New().
+			FilterPetitionID(petitionID).
+			FilterStatus(enum.PetitionAuthorInvited, enum.PetitionAuthorAccepted).
+			Count(ctx)
+		if err != nil {
+			return models.PetitionAuthor{}, errx.RaiseInternal(ctx, err)
+		}
+
+		if count >= uint64(limit) {
+			return models.PetitionAuthor{}, errx.RaiseTooManyCoAuthors(ctx, fmt.Errorf("petition has %d co-authors", count), petitionID, limit)
+		}
+	}
+
+	author := dbx.PetitionAuthor{
+		PetitionID: petitionID,
+		UserID:     userID,
+		Status:     enum.PetitionAuthorInvited,
+		InvitedBy:  initiator.ID,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	if existing.Status == enum.PetitionAuthorDeclined {
+		err = p.authorsQ.New().FilterPetitionID(petitionID).FilterUserID(userID).Update(ctx, dbx.UpdatePetitionAuthorInput{
+			Status:      &author.Status,
+			InvitedBy:   &author.InvitedBy,
+			RespondedAt: &sql.NullTime{},
+			CreatedAt:   &author.CreatedAt,
+		})
+	} else {
+		err = p.authorsQ.New().Insert(ctx, author)
+	}
+	if err != nil {
+		return models.PetitionAuthor{}, errx.RaiseInternal(ctx, err)
+	}
+
+	p.cache.invalidate(ctx, petitionID)
+
+	return petitionAuthorModel(author), nil
+}
+
+// AcceptCoAuthorInvitation makes the initiator a co-author of a draft they were invited to.
+// Co-authors have to meet the same requirements as creators of petitions in the city.
+func (p Petition) AcceptCoAuthorInvitation(ctx context.Context, initiator Initiator, petitionID uuid.UUID) (models.PetitionAuthor, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.AcceptCoAuthorInvitation", attribute.String("petition.id", petitionID.String()))
+	defer span.End()
+
+	petition, err := p.getDraft(ctx, petitionID)
+	if err != nil {
+		return models.PetitionAuthor{}, err
+	}
+
+	if err := p.verification.check(ctx, petition.CityID, initiator, verificationActionCreate); err != nil {
+		return models.PetitionAuthor{}, err
+	}
+
+	if err := p.checkResidency(ctx, petition.CityID, initiator.ID); err != nil {
+		return models.PetitionAuthor{}, err
+	}
+
+	return p.answerInvitation(ctx, initiator, petitionID, enum.PetitionAuthorAccepted)
+}
+
+// DeclineCoAuthorInvitation declines the initiator's pending invitation to a draft.
+func (p Petition) DeclineCoAuthorInvitation(ctx context.Context, initiator Initiator, petitionID uuid.UUID) (models.PetitionAuthor, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.DeclineCoAuthorInvitation", attribute.String("petition.id", petitionID.String()))
+	defer span.End()
+
+	if _, err := p.getDraft(ctx, petitionID); err != nil {
+		return models.PetitionAuthor{}, err
+	}
+
+	return p.answerInvitation(ctx, initiator, petitionID, enum.PetitionAuthorDeclined)
+}
+
+func (p Petition) answerInvitation(ctx context.Context, initiator Initiator, petitionID uuid.UUID, status string) (models.PetitionAuthor, error) {
+	author, err := p.authorsQ.New().FilterPetitionID(petitionID).FilterUserID(initiator.ID).Get(ctx)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return models.PetitionAuthor{}, errx.RaisePetitionAuthorNotFound(ctx, err, petitionID, initiator.ID)
+		default:
+			return models.PetitionAuthor{}, errx.RaiseInternal(ctx, err)
+		}
+	}
+
+	if author.Status != enum.PetitionAuthorInvited {
+		return models.PetitionAuthor{}, errx.RaisePetitionAuthorNotFound(ctx, fmt.Errorf("invitation is %s", author.Status), petitionID, initiator.ID)
+	}
+
+	respondedAt := sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	err = p.authorsQ.New().
+		FilterPetitionID(petitionID).
+		FilterUserID(initiator.ID).
+		FilterStatus(enum.PetitionAuthorInvited).
+		Update(ctx, dbx.UpdatePetitionAuthorInput{
+			Status:      &status,
+			RespondedAt: &respondedAt,
+		})
+	if err != nil {
+		return models.PetitionAuthor{}, errx.RaiseInternal(ctx, err)
+	}
+
+	p.cache.invalidate(ctx, petitionID)
+
+	author.Status = status
+	author.RespondedAt = respondedAt
+
+	return petitionAuthorModel(author), nil
+}
+
+// RemoveCoAuthor removes a co-author or a pending invitation from a draft. The creator may remove
+// anyone, co-authors may only step down themselves.
+func (p Petition) RemoveCoAuthor(ctx context.Context, initiator Initiator, petitionID, userID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "entities.Petition.RemoveCoAuthor", attribute.String("petition.id", petitionID.String()))
+	defer span.End()
+
+	petition, err := p.getDraft(ctx, petitionID)
+	if err != nil {
+		return err
+	}
+
+	if initiator.ID != petition.CreatorID && initiator.ID != userID {
+		return errx.RaiseNotPetitionAuthor(ctx, fmt.Errorf("only the creator may remove other co-authors"), petitionID, initiator.ID)
+	}
+
+	author, err := p.authorsQ.New().FilterPetitionID(petitionID).FilterUserID(userID).Get(ctx)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return errx.RaisePetitionAuthorNotFound(ctx, err, petitionID, userID)
+		default:
+			return errx.RaiseInternal(ctx, err)
+		}
+	}
+
+	if author.Status == enum.PetitionAuthorDeclined {
+		return errx.RaisePetitionAuthorNotFound(ctx, fmt.Errorf("invitation was declined"), petitionID, userID)
+	}
+
+	if err := p.authorsQ.New().FilterPetitionID(petitionID).FilterUserID(userID).Delete(ctx); err != nil {
+		return errx.RaiseInternal(ctx, err)
+	}
+
+	p.cache.invalidate(ctx, petitionID)
+
+	return nil
+}
+
+type UpdateDraftInput struct {
+	Title       *string
+	Description *string
+}
+
+// UpdateDraft edits the text of a draft. The creator and accepted co-authors may edit it.
+func (p Petition) UpdateDraft(ctx context.Context, initiator Initiator, petitionID uuid.UUID, input UpdateDraftInput) (models.Petition, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.UpdateDraft", attribute.String("petition.id", petitionID.String()))
+	defer span.End()
+
+	petition, err := p.getDraft(ctx, petitionID)
+	if err != nil {
+		return models.Petition{}, err
+	}
+
+	if err := p.checkAuthor(ctx, initiator, petition); err != nil {
+		return models.Petition{}, err
+	}
+
+	var fields []contentfilter.Field
+	if input.Title != nil {
+		fields = append(fields, contentfilter.Field{Name: "title", Text: *input.Title})
+		petition.Title = *input.Title
+	}
+	if input.Description != nil {
+		fields = append(fields, contentfilter.Field{Name: "description", Text: *input.Description})
+		petition.Description = *input.Description
+	}
+
+	// drafts are screened again when published, here only rejected content is refused
+	if _, err := p.screenContent(ctx, fields...); err != nil {
+		return models.Petition{}, err
+	}
+
+	petition.UpdatedAt = time.Now().UTC()
+
+	err = p.q.New().FilterID(petitionID).FilterStatus(enum.PetitionDraft).Update(ctx, dbx.UpdatePetitionInput{
+		Title:       input.Title,
+		Description: input.Description,
+		UpdatedAt:   &petition.UpdatedAt,
+	})
+	if err != nil {
+		return models.Petition{}, errx.RaiseInternal(ctx, err)
+	}
+
+	p.cache.invalidate(ctx, petitionID)
+
+	res := []models.Petition{petitionModel(petition)}
+	if err := p.withCoAuthors(ctx, res); err != nil {
+		return models.Petition{}, err
+	}
+
+	return res[0], nil
+}
+
+// PublishPetition opens a draft for signatures once it has the co-authors the city requires.
+// Only the creator may publish; invitations still pending are dropped.
+func (p Petition) PublishPetition(ctx context.Context, initiator Initiator, petitionID uuid.UUID) (models.Petition, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.PublishPetition", attribute.String("petition.id", petitionID.String()))
+	defer span.End()
+
+	petition, err := p.getDraft(ctx, petitionID)
+	if err != nil {
+		return models.Petition{}, err
+	}
+
+	if petition.CreatorID != initiator.ID {
+		return models.Petition{}, errx.RaiseNotPetitionAuthor(ctx, fmt.Errorf("only the creator may publish the petition"), petitionID, initiator.ID)
+	}
+
+	if required := p.authors.forCity(petition.CityID).MinCoAuthors; required > 0 {
+		accepted, err := p.authorsQ.New().FilterPetitionID(petitionID).FilterStatus(enum.PetitionAuthorAccepted).Count(ctx)
+		if err != nil {
+			return models.Petition{}, errx.RaiseInternal(ctx, err)
+		}
+
+		if accepted < uint64(required) {
+			return models.Petition{}, errx.RaiseNotEnoughCoAuthors(
+				ctx,
+				fmt.Errorf("city %s requires %d co-authors", petition.CityID, required),
+				petitionID,
+				required,
+				int(accepted),
+			)
+		}
+	}
+
+	verdict, err := p.screenContent(ctx,
+		contentfilter.Field{Name: "title", Text: petition.Title},
+		contentfilter.Field{Name: "description", Text: petition.Description},
+	)
+	if err != nil {
+		return models.Petition{}, err
+	}
+
+	petition.Status = enum.PetitionPublished
+	if verdict.NeedsModeration() {
+		petition.Status = enum.PetitionModeration
+	}
+
+	// the petition collects signatures for 30 days from publishing
+	now := time.Now().UTC()
+	petition.EndDate = now.AddDate(0, 0, 30)
+	petition.UpdatedAt = now
+
+	err = dbx.Transaction(ctx, p.db, func(ctx context.Context) error {
+		err := p.q.New().FilterID(petitionID).FilterStatus(enum.PetitionDraft).Update(ctx, dbx.UpdatePetitionInput{
+			Status:    &petition.Status,
+			EndDate:   &petition.EndDate,
+			UpdatedAt: &petition.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := p.authorsQ.New().FilterPetitionID(petitionID).FilterStatus(enum.PetitionAuthorInvited).Delete(ctx); err != nil {
+			return err
+		}
+
+		return p.contentFlagsQ.New().Insert(ctx, contentFlags(petitionID, verdict, now)...)
+	})
+	if err != nil {
+		return models.Petition{}, errx.RaiseInternal(ctx, err)
+	}
+
+	p.cache.invalidate(ctx, petitionID)
+
+	res := []models.Petition{petitionModel(petition)}
+	if err := p.withCoAuthors(ctx, res); err != nil {
+		return models.Petition{}, err
+	}
+
+	return res[0], nil
+}
+
+// getDraft returns the petition if it is still a draft.
+func (p Petition) getDraft(ctx context.Context, petitionID uuid.UUID) (dbx.Petition, error) {
+	petition, err := p.q.New().FilterID(petitionID).Get(ctx)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return dbx.Petition{}, errx.RaisePetitionNotFoundByID(ctx, err, petitionID)
+		default:
+			return dbx.Petition{}, errx.RaiseInternal(ctx, err)
+		}
+	}
+
+	if petition.Status != enum.PetitionDraft {
+		return dbx.Petition{}, errx.RaisePetitionIsNotDraft(ctx, fmt.Errorf("petition status is %s", petition.Status), petitionID)
+	}
+
+	return petition, nil
+}
+
+// checkAuthor fails unless initiator created the petition or is its accepted co-author.
+func (p Petition) checkAuthor(ctx context.Context, initiator Initiator, petition dbx.Petition) error {
+	author, err := p.isAuthor(ctx, initiator.ID, petition)
+	if err != nil {
+		return errx.RaiseInternal(ctx, err)
+	}
+
+	if !author {
+		return errx.RaiseNotPetitionAuthor(ctx, fmt.Errorf("user is not an author"), petition.ID, initiator.ID)
+	}
+
+	return nil
+}
+
+// isAuthor reports whether userID created the petition or is its accepted co-author.
+func (p Petition) isAuthor(ctx context.Context, userID uuid.UUID, petition dbx.Petition) (bool, error) {
+	if petition.CreatorID == userID {
+		return true, nil
+	}
+
+	count, err := p.authorsQ.New().
+		FilterPetitionID(petition.ID).
+		FilterUserID(userID).
+		FilterStatus(enum.PetitionAuthorAccepted).
+		Count(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// withCoAuthors fills the co-authors of petitions. Accepted co-authors are listed for every
+// petition, pending invitations only for drafts.
+func (p Petition) withCoAuthors(ctx context.Context, petitions []models.Petition) error {
+	if len(petitions) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(petitions))
+	for _, petition := range petitions {
+		ids = append(ids, petition.ID)
+	}
+
+	authors, err := p.authorsQ.New().
+		FilterPetitionIDs(ids...).
+		FilterStatus(enum.PetitionAuthorInvited, enum.PetitionAuthorAccepted).
+		OrderByCreated(true).
+		Select(ctx)
+	if err != nil {
+		return errx.RaiseInternal(ctx, err)
+	}
+
+	byPetition := make(map[uuid.UUID][]models.PetitionAuthor, len(petitions))
+	for _, a := range authors {
+		byPetition[a.PetitionID] = append(byPetition[a.PetitionID], petitionAuthorModel(a))
+	}
+
+	for i := range petitions {
+		petitions[i].CoAuthors = nil
+		for _, a := range byPetition[petitions[i].ID] {
+			if a.Status == enum.PetitionAuthorAccepted || petitions[i].Status == enum.PetitionDraft {
+				petitions[i].CoAuthors = append(petitions[i].CoAuthors, a)
+			}
+		}
+	}
+
+	return nil
+}
+
+func petitionAuthorModel(a dbx.PetitionAuthor) models.PetitionAuthor {
+	res := models.PetitionAuthor{
+		PetitionID: a.PetitionID,
+		UserID:     a.UserID,
+		Status:     a.Status,
+		InvitedBy:  a.InvitedBy,
+		CreatedAt:  a.CreatedAt,
+	}
+	if a.RespondedAt.Valid {
+		res.RespondedAt = &a.RespondedAt.Time
+	}
+
+	return res
+}
@@ -0,0 +1,74 @@
+package entities
+
+import (
+	"context"
+	"time"
+
+	"github.com/chains-lab/city-petitions-svc/internal/app/models"
+	"github.com/chains-lab/city-petitions-svc/internal/config"
+	"github.com/chains-lab/city-petitions-svc/internal/constant/enum"
+	"github.com/chains-lab/city-petitions-svc/internal/errx"
+	"github.com/chains-lab/city-petitions-svc/internal/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	defaultDuplicateMinSimilarity = 0.45
+	defaultDuplicateMaxCandidates = 5
+)
+
+type duplicateDetector struct {
+	enabled       bool
+	minSimilarity float64
+	maxCandidates uint64
+}
+
+func newDuplicateDetector(cfg config.Config) duplicateDetector {
+	dup := cfg.Petitions.Duplicates
+
+	d := duplicateDetector{
+		enabled:       dup.Enabled,
+		minSimilarity: dup.MinSimilarity,
+		maxCandidates: uint64(dup.MaxCandidates),
+	}
+	if d.minSimilarity <= 0 {
+		d.minSimilarity = defaultDuplicateMinSimilarity
+	}
+	if d.maxCandidates == 0 {
+		d.maxCandidates = defaultDuplicateMaxCandidates
+	}
+
+	return d
+}
+
+// FindDuplicates lists open petitions of the city whose title resembles title, most similar first.
+// It returns nothing when duplicate detection is disabled.
+func (p Petition) FindDuplicates(ctx context.Context, cityID uuid.UUID, title string) ([]models.DuplicateCandidate, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.FindDuplicates", attribute.String("city.id", cityID.String()))
+	defer span.End()
+
+	if !p.duplicates.enabled {
+		return nil, nil
+	}
+
+	similar, err := p.q.New().
+		FilterCityID(cityID).
+		FilterStatus(enum.PetitionPublished).
+		FilterEndDate(time.Now().UTC(), true).
+		Page(p.duplicates.maxCandidates, 0).
+		SelectSimilarTitles(ctx, title, p.duplicates.minSimilarity)
+	if err != nil {
+		return nil, errx.RaiseInternal(ctx, err)
+	}
+
+	res := make([]models.DuplicateCandidate, 0, len(similar))
+	for _, s := range similar {
+		res = append(res, models.DuplicateCandidate{
+			Petition:   petitionModel(s.Petition),
+			Similarity: s.Similarity,
+		})
+	}
+
+	return res, nil
+}
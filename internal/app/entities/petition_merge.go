@@ -0,0 +1,159 @@
+package entities
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chains-lab/city-petitions-svc/internal/app/models"
+	"github.com/chains-lab/city-petitions-svc/internal/constant/enum"
+	"github.com/chains-lab/city-petitions-svc/internal/dbx"
+	"github.com/chains-lab/city-petitions-svc/internal/errx"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+	"github.com/chains-lab/city-petitions-svc/internal/rbac"
+	"github.com/chains-lab/city-petitions-svc/internal/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// MergePetitions folds the source petitions into the target. Signatures of users who have not
+// signed the target yet are moved to it, counters are recomputed and the sources get the merged
+// status pointing at the target. Watchers of all petitions are notified by the update trigger.
+// It returns the updated target and the number of moved signatures.
+func (p Petition) MergePetitions(ctx context.Context, initiator Initiator, targetID uuid.UUID, sourceIDs []uuid.UUID) (models.Petition, int, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.MergePetitions",
+		attribute.String("petition.id", targetID.String()),
+		attribute.Int("sources.count", len(sourceIDs)),
+	)
+	defer span.End()
+
+	for _, id := range sourceIDs {
+		if id == targetID {
+			return models.Petition{}, 0, errx.RaisePetitionsNotMergeable(ctx, fmt.Errorf("petition %s is both target and source", id), id, "petition can not be merged into itself")
+		}
+	}
+
+	ids := append([]uuid.UUID{targetID}, sourceIDs...)
+
+	var (
+		target   dbx.Petition
+		moved    int64
+		rejected error
+	)
+	err := dbx.Transaction(ctx, p.db, func(ctx context.Context) error {
+		locked, err := p.q.New().FilterIDs(ids...).ForUpdate().Select(ctx)
+		if err != nil {
+			return err
+		}
+
+		target, rejected = p.checkMergeable(ctx, initiator, targetID, ids, locked)
+		if rejected != nil {
+			return rejected
+		}
+
+		moved, err = p.sigQ.New().MoveToPetition(ctx, targetID, sourceIDs...)
+		if err != nil {
+			return err
+		}
+
+		// moved signatures join the target's chain; their entries in the sources' chains stay
+		movedSignatures, err := p.sigQ.New().FilterPetitionID(targetID).FilterMergedFrom(sourceIDs...).OrderByCreated(true).Select(ctx)
+		if err != nil {
+			return err
+		}
+		if _, err := p.appendToChain(ctx, targetID, movedSignatures, time.Now()); err != nil {
+			return err
+		}
+
+		// flags belong to signatures, so they follow the moved ones
+		if err := p.flagsQ.New().FilterPetitionIDs(sourceIDs...).FollowSignatures(ctx); err != nil {
+			return err
+		}
+
+		if err := p.q.New().FilterIDs(ids...).RecountSignatures(ctx); err != nil {
+			return err
+		}
+
+		merged := enum.PetitionMerged
+		now := time.Now().UTC()
+		err = p.q.New().FilterIDs(sourceIDs...).Update(ctx, dbx.UpdatePetitionInput{
+			Status:     &merged,
+			MergedInto: &targetID,
+			UpdatedAt:  &now,
+			ClosedAt:   &now,
+		})
+		if err != nil {
+			return err
+		}
+
+		target, err = p.q.New().FilterID(targetID).Get(ctx)
+		return err
+	})
+	if rejected != nil {
+		return models.Petition{}, 0, rejected
+	}
+	if err != nil {
+		return models.Petition{}, 0, errx.RaiseInternal(ctx, err)
+	}
+
+	logger.Log(ctx).Infof("merged %d petitions into %s, moved %d signatures", len(sourceIDs), targetID, moved)
+	p.cache.invalidate(ctx, ids...)
+
+	res := []models.Petition{petitionModel(target)}
+	if err := p.withCoAuthors(ctx, res); err != nil {
+		return models.Petition{}, 0, err
+	}
+
+	return res[0], int(moved), nil
+}
+
+// checkMergeable checks that all petitions exist, are open, share the target's city and that
+// initiator may administrate petitions there. It returns the target.
+func (p Petition) checkMergeable(
+	ctx context.Context,
+	initiator Initiator,
+	targetID uuid.UUID,
+	ids []uuid.UUID,
+	petitions []dbx.Petition,
+) (dbx.Petition, error) {
+	byID := make(map[uuid.UUID]dbx.Petition, len(petitions))
+	for _, petition := range petitions {
+		byID[petition.ID] = petition
+	}
+
+	target, ok := byID[targetID]
+	if !ok {
+		return dbx.Petition{}, errx.RaisePetitionNotFoundByID(ctx, fmt.Errorf("petition %s not found", targetID), targetID)
+	}
+
+	if err := p.checkPermission(ctx, initiator, rbac.PermissionPetitionAdmin, target.CityID); err != nil {
+		return dbx.Petition{}, err
+	}
+
+	for _, id := range ids {
+		petition, ok := byID[id]
+		if !ok {
+			return dbx.Petition{}, errx.RaisePetitionNotFoundByID(ctx, fmt.Errorf("petition %s not found", id), id)
+		}
+
+		if petition.CityID != target.CityID {
+			return dbx.Petition{}, errx.RaisePetitionsNotMergeable(
+				ctx,
+				fmt.Errorf("petition %s is in city %s, target in %s", id, petition.CityID, target.CityID),
+				id,
+				"petition belongs to another city",
+			)
+		}
+
+		if petition.Status != enum.PetitionPublished {
+			return dbx.Petition{}, errx.RaisePetitionsNotMergeable(
+				ctx,
+				fmt.Errorf("petition %s has status %s", id, petition.Status),
+				id,
+				fmt.Sprintf("only %s petitions can be merged", enum.PetitionPublished),
+			)
+		}
+	}
+
+	return target, nil
+}
@@ -0,0 +1,48 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chains-lab/city-petitions-svc/internal/config"
+	"github.com/spf13/viper"
+)
+
+func TestCheckPseudonymKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		valid bool
+	}{
+		{"empty", "", false},
+		{"short", "pseudonymsuperkey", false},
+		{"31 bytes", strings.Repeat("k", 31), false},
+		{"32 bytes", strings.Repeat("k", 32), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkPseudonymKey(tt.key); (err == nil) != tt.valid {
+				t.Errorf("checkPseudonymKey = %v, want valid %t", err, tt.valid)
+			}
+		})
+	}
+}
+
+// The shipped config must start the service, like its other development secrets.
+func TestShippedConfigPseudonymKey(t *testing.T) {
+	v := viper.New()
+	v.SetConfigFile("../../../config.yaml")
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatalf("reading config.yaml: %v", err)
+	}
+
+	var cfg config.Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		t.Fatalf("unmarshalling config.yaml: %v", err)
+	}
+
+	if err := checkPseudonymKey(cfg.Petitions.PseudonymKey); err != nil {
+		t.Errorf("config.yaml: %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package entities
+
+import (
+	"context"
+	"time"
+
+	"github.com/chains-lab/city-petitions-svc/internal/app/models"
+	"github.com/chains-lab/city-petitions-svc/internal/config"
+	"github.com/chains-lab/city-petitions-svc/internal/constant/enum"
+	"github.com/chains-lab/city-petitions-svc/internal/dbx"
+	"github.com/chains-lab/city-petitions-svc/internal/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const defaultRetentionBatchSize = 1000
+
+type retentionPolicy struct {
+	batchSize uint64
+	global    config.RetentionPolicy
+	cities    map[string]config.RetentionPolicy
+}
+
+func newRetentionPolicy(cfg config.Config) retentionPolicy {
+	batchSize := cfg.Petitions.Retention.BatchSize
+	if batchSize == 0 {
+		batchSize = defaultRetentionBatchSize
+	}
+
+	return retentionPolicy{
+		batchSize: batchSize,
+		global:    cfg.Petitions.Retention.RetentionPolicy,
+		cities:    cfg.Petitions.Retention.Cities,
+	}
+}
+
+// forCity returns the city override if one is configured, otherwise the global policy.
+func (r retentionPolicy) forCity(cityID uuid.UUID) config.RetentionPolicy {
+	if policy, ok := r.cities[cityID.String()]; ok {
+		return policy
+	}
+
+	return r.global
+}
+
+// shortestPeriod returns the shortest configured period, or 0 when signers are kept forever everywhere.
+func (r retentionPolicy) shortestPeriod() time.Duration {
+	shortest := r.global.Period
+	for _, policy := range r.cities {
+		if policy.Period > 0 && (shortest == 0 || policy.Period < shortest) {
+			shortest = policy.Period
+		}
+	}
+
+	return shortest
+}
+
+// pseudonym derives the ID that replaces userID on their signature of petitionID from the
+// signer hash, so it stays unlinkable across petitions and distinct signers of a petition keep
+// distinct pseudonyms, which preserves the one-signature-per-user constraint. It also matches
+// the user hash of the signature's chain entry.
+func (p Petition) pseudonym(petitionID, userID uuid.UUID, salt []byte) uuid.UUID {
+	var id uuid.UUID
+	copy(id[:], p.signerHash(petitionID, userID, salt))
+
+	return id
+}
+
+// ApplyRetention replaces the signers of petitions closed longer than their city's retention
+// period with pseudonyms. Counters are untouched as no signature is removed. On a dry run
+// nothing is changed and the report lists what would be.
+func (p Petition) ApplyRetention(ctx context.Context, dryRun bool) (models.RetentionReport, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.ApplyRetention", attribute.Bool("dry_run", dryRun))
+	defer span.End()
+
+	now := time.Now().UTC()
+	report := models.RetentionReport{StartedAt: now, DryRun: dryRun}
+
+	shortest := p.retention.shortestPeriod()
+	if shortest == 0 {
+		return report, nil
+	}
+
+	petitions, err := p.q.New().FilterClosedBefore(now.Add(-shortest)).Select(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	for _, petition := range petitions {
+		period := p.retention.forCity(petition.CityID).Period
+		closedAt := petitionClosedAt(petition)
+		if period == 0 || closedAt.After(now.Add(-period)) {
+			continue
+		}
+
+		var affected int
+		if dryRun {
+			count, err := p.sigQ.New().FilterPetitionID(petition.ID).FilterPseudonymized(false).Count(ctx)
+			if err != nil {
+				return report, err
+			}
+			affected = int(count)
+		} else {
+			affected, err = p.pseudonymizeSigners(ctx, petition.ID, now)
+			if err != nil {
+				return report, err
+			}
+		}
+
+		if affected == 0 {
+			continue
+		}
+
+		report.Petitions = append(report.Petitions, models.RetainedPetition{
+			PetitionID: petition.ID,
+			CityID:     petition.CityID,
+			ClosedAt:   closedAt,
+			Period:     period,
+			Signatures: affected,
+		})
+		report.Signatures += affected
+	}
+
+	return report, nil
+}
+
+// pseudonymizeSigners pseudonymizes the signatures of petitionID in batches, one transaction
+// each, and returns how many were changed.
+func (p Petition) pseudonymizeSigners(ctx context.Context, petitionID uuid.UUID, now time.Time) (int, error) {
+	var total int
+	for {
+		var (
+			n    int64
+			more bool
+		)
+		err := dbx.Transaction(ctx, p.db, func(ctx context.Context) error {
+			signatures, err := p.sigQ.New().
+				FilterPetitionID(petitionID).
+				FilterPseudonymized(false).
+				Page(p.retention.batchSize, 0).
+				Select(ctx)
+			if err != nil {
+				return err
+			}
+
+			userIDs := make([]uuid.UUID, 0, len(signatures))
+			for _, sig := range signatures {
+				userIDs = append(userIDs, sig.UserID)
+			}
+			salts, err := p.signerSalts(ctx, userIDs...)
+			if err != nil {
+				return err
+			}
+
+			pseudonyms := make(map[uuid.UUID]uuid.UUID, len(signatures))
+			for _, sig := range signatures {
+				pseudonyms[sig.ID] = p.pseudonym(petitionID, sig.UserID, salts[sig.UserID])
+			}
+
+			n, err = p.sigQ.New().Pseudonymize(ctx, pseudonyms, now)
+			more = uint64(len(signatures)) == p.retention.batchSize
+			return err
+		})
+		if err != nil {
+			return total, err
+		}
+
+		total += int(n)
+		if !more {
+			return total, nil
+		}
+	}
+}
+
+// petitionClosedAt returns when petition stopped collecting signatures, as FilterClosedBefore sees it.
+func petitionClosedAt(petition dbx.Petition) time.Time {
+	switch petition.Status {
+	case enum.PetitionApproved, enum.PetitionRejected, enum.PetitionMerged:
+		return petition.ClosedAt.Time
+	default:
+		return petition.EndDate
+	}
+}
@@ -0,0 +1,64 @@
+package entities
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chains-lab/city-petitions-svc/internal/constant/enum"
+	"github.com/chains-lab/city-petitions-svc/internal/dbx"
+)
+
+func TestPetitionClosedAt(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := created.AddDate(0, 1, 0)
+	closed := created.AddDate(0, 0, 20)
+
+	tests := []struct {
+		status string
+		want   time.Time
+	}{
+		{enum.PetitionApproved, closed},
+		{enum.PetitionRejected, closed},
+		{enum.PetitionMerged, closed},
+		{enum.PetitionPublished, endDate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			// updated_at is the last draft edit, long before closing
+			petition := dbx.Petition{
+				Status:    tt.status,
+				EndDate:   endDate,
+				CreatedAt: created,
+				UpdatedAt: created,
+				ClosedAt:  sql.NullTime{Time: closed, Valid: tt.status != enum.PetitionPublished},
+			}
+
+			if got := petitionClosedAt(petition); !got.Equal(tt.want) {
+				t.Errorf("petitionClosedAt = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterClosedBeforeUsesClosedAt(t *testing.T) {
+	db, fake := openFakeDB(t, func(string, []driver.NamedValue) ([]string, [][]driver.Value) {
+		return nil, nil
+	})
+
+	if _, err := dbx.NewPetitionsQ(db).FilterClosedBefore(time.Now()).Select(context.Background()); err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+
+	query := fake.last().query
+	if !strings.Contains(query, "closed_at < $") || !strings.Contains(query, "end_date < $") {
+		t.Errorf("query does not compare closed_at and end_date: %s", query)
+	}
+	if strings.Contains(query, "updated_at <") {
+		t.Errorf("query compares updated_at: %s", query)
+	}
+}
@@ -0,0 +1,108 @@
+package entities
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chains-lab/city-petitions-svc/internal/constant/enum"
+	"github.com/chains-lab/city-petitions-svc/internal/dbx"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type everyoneResident struct{}
+
+func (everyoneResident) IsResident(context.Context, uuid.UUID, uuid.UUID) (bool, error) {
+	return true, nil
+}
+
+var signSalt = []byte("0123456789abcdef")
+
+// newSignPetition returns a Petition over a fake database holding visPetitionID with the given
+// status and end date. When pseudonymized, the database holds a signature of visSignerID whose
+// signer was replaced by its pseudonym.
+func newSignPetition(t *testing.T, petitionStatus string, endDate time.Time, pseudonymized bool) (Petition, *fakeDB) {
+	t.Helper()
+
+	p := Petition{
+		pseudonymKey: []byte("0123456789abcdef0123456789abcdef"),
+		residency:    everyoneResident{},
+		content:      allowContent{},
+	}
+	pseudonym := p.pseudonym(visPetitionID, visSignerID, signSalt)
+
+	now := time.Now().UTC()
+	db, fake := openFakeDB(t, func(query string, args []driver.NamedValue) ([]string, [][]driver.Value) {
+		switch {
+		case strings.Contains(query, "FROM petitions "):
+			return []string{"id", "city_id", "creator_id", "title", "description", "status", "signatures", "goal", "reply", "end_date", "created_at", "updated_at", "merged_into", "closed_at"},
+				[][]driver.Value{{
+					visPetitionID.String(), visCityID.String(), visCreatorID.String(), "title", "description",
+					petitionStatus, int64(4), int64(100), "", endDate, now, now, nil, nil,
+				}}
+		case strings.Contains(query, "FROM signer_salts"):
+			return []string{"user_id", "salt", "created_at"}, [][]driver.Value{{visSignerID.String(), signSalt, now}}
+		case strings.Contains(query, "FROM petition_signatures") && pseudonymized && hasArg(args, pseudonym.String()):
+			return []string{"id", "petition_id", "user_id", "created_at", "verified", "session_id", "invalidated", "invalidated_reason",
+					"invalidated_by", "invalidated_at", "merged_from", "reason", "reason_hidden", "visibility", "pseudonymized_at"},
+				[][]driver.Value{{
+					uuid.NewString(), visPetitionID.String(), pseudonym.String(), now, true, nil, false, nil,
+					nil, nil, nil, nil, false, enum.SignatureAnonymous, now,
+				}}
+		}
+
+		return nil, nil
+	})
+
+	p.db = db
+	p.q = dbx.NewPetitionsQ(db)
+	p.sigQ = dbx.NewPetitionSignaturesQ(db)
+	p.saltsQ = dbx.NewSignerSaltsQ(db)
+	p.chainQ = dbx.NewSignatureChainQ(db)
+
+	return p, fake
+}
+
+func TestSignPetition(t *testing.T) {
+	open := time.Now().Add(time.Hour)
+	ended := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name          string
+		status        string
+		endDate       time.Time
+		pseudonymized bool
+		want          codes.Code
+	}{
+		{"published", enum.PetitionPublished, open, false, codes.OK},
+		{"published past its end date", enum.PetitionPublished, ended, false, codes.FailedPrecondition},
+		{"draft", enum.PetitionDraft, open, false, codes.FailedPrecondition},
+		{"moderation", enum.PetitionModeration, open, false, codes.FailedPrecondition},
+		{"approved", enum.PetitionApproved, open, false, codes.FailedPrecondition},
+		{"rejected", enum.PetitionRejected, open, false, codes.FailedPrecondition},
+		{"merged", enum.PetitionMerged, open, false, codes.FailedPrecondition},
+		{"signed before being pseudonymized", enum.PetitionPublished, open, true, codes.AlreadyExists},
+	}
+
+	signer := Initiator{ID: visSignerID, Verified: true, Role: enum.UserRoleUser}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, fake := newSignPetition(t, tt.status, tt.endDate, tt.pseudonymized)
+
+			_, err := p.SignPetition(context.Background(), signer, visPetitionID, SignPetitionInput{})
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("code = %s, want %s (err: %v)", got, tt.want, err)
+			}
+
+			inserted := len(fake.find("INSERT INTO petition_signatures")) > 0
+			if inserted != (tt.want == codes.OK) {
+				t.Errorf("signature inserted = %t, want %t", inserted, tt.want == codes.OK)
+			}
+		})
+	}
+}
@@ -0,0 +1,446 @@
+package entities
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/chains-lab/city-petitions-svc/internal/app/models"
+	"github.com/chains-lab/city-petitions-svc/internal/dbx"
+	"github.com/chains-lab/city-petitions-svc/internal/errx"
+	"github.com/chains-lab/city-petitions-svc/internal/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// chainBatchSize is how many chain entries are written or verified at once.
+const chainBatchSize = 1000
+
+// signerSaltSize is the length in bytes of the random salt of a user's signer hashes.
+const signerSaltSize = 32
+
+type signatureChainQ interface {
+	New() dbx.SignatureChainQ
+
+	Insert(ctx context.Context, entries ...dbx.SignatureChainEntry) error
+	Get(ctx context.Context) (dbx.SignatureChainEntry, error)
+	Select(ctx context.Context) ([]dbx.SignatureChainEntry, error)
+
+	FilterPetitionID(petitionID uuid.UUID) dbx.SignatureChainQ
+	FilterSignatureID(signatureID uuid.UUID) dbx.SignatureChainQ
+	FilterSeq(seq int64) dbx.SignatureChainQ
+	FilterSeqAfter(seq int64) dbx.SignatureChainQ
+
+	OrderBySeq(ascending bool) dbx.SignatureChainQ
+
+	Count(ctx context.Context) (uint64, error)
+	Page(limit, offset uint64) dbx.SignatureChainQ
+}
+
+type signerSaltsQ interface {
+	New() dbx.SignerSaltsQ
+
+	Insert(ctx context.Context, salts ...dbx.SignerSalt) error
+	Select(ctx context.Context) ([]dbx.SignerSalt, error)
+	Delete(ctx context.Context) error
+
+	FilterUserID(userIDs ...uuid.UUID) dbx.SignerSaltsQ
+}
+
+// signerHash is the keyed hash of userID as signer of petitionID. It is salted with the petition,
+// so one user's signatures can not be linked across petitions, and with the user's own salt,
+// so it can not be recomputed from the user ID once erasure has deleted that salt. Its first
+// half is the pseudonym retention gives the signer.
+func (p Petition) signerHash(petitionID, userID uuid.UUID, salt []byte) []byte {
+	mac := hmac.New(sha256.New, p.pseudonymKey)
+	mac.Write(salt)
+	mac.Write(petitionID[:])
+	mac.Write(userID[:])
+
+	return mac.Sum(nil)
+}
+
+// signerSalts returns the salts of userIDs, creating the missing ones.
+func (p Petition) signerSalts(ctx context.Context, userIDs ...uuid.UUID) (map[uuid.UUID][]byte, error) {
+	salts := make(map[uuid.UUID][]byte, len(userIDs))
+	if len(userIDs) == 0 {
+		return salts, nil
+	}
+
+	stored, err := p.saltsQ.New().FilterUserID(userIDs...).Select(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("selecting signer salts: %w", err)
+	}
+	for _, s := range stored {
+		salts[s.UserID] = s.Salt
+	}
+
+	now := time.Now().UTC()
+	var missing []uuid.UUID
+	var created []dbx.SignerSalt
+	for _, userID := range userIDs {
+		if _, ok := salts[userID]; ok || slices.Contains(missing, userID) {
+			continue
+		}
+
+		salt := make([]byte, signerSaltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, fmt.Errorf("generating signer salt: %w", err)
+		}
+		missing = append(missing, userID)
+		created = append(created, dbx.SignerSalt{UserID: userID, Salt: salt, CreatedAt: now})
+	}
+	if len(missing) == 0 {
+		return salts, nil
+	}
+
+	if err := p.saltsQ.New().Insert(ctx, created...); err != nil {
+		return nil, fmt.Errorf("inserting signer salts: %w", err)
+	}
+
+	// a concurrent signature of the same user may have stored its salt first
+	stored, err = p.saltsQ.New().FilterUserID(missing...).Select(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("selecting signer salts: %w", err)
+	}
+	for _, s := range stored {
+		salts[s.UserID] = s.Salt
+	}
+
+	for _, userID := range missing {
+		if _, ok := salts[userID]; !ok {
+			return nil, fmt.Errorf("signer salt of user %s was not stored", userID)
+		}
+	}
+
+	return salts, nil
+}
+
+// chainGenesis is the prev_hash of a petition's first entry, binding the chain to the petition.
+func chainGenesis(petitionID uuid.UUID) []byte {
+	sum := sha256.Sum256(petitionID[:])
+
+	return sum[:]
+}
+
+// chainHash links an entry to prevHash. Time is hashed in microseconds, as stored by Postgres.
+func chainHash(prevHash []byte, signatureID uuid.UUID, userHash []byte, at time.Time) []byte {
+	h := sha256.New()
+	h.Write(prevHash)
+	h.Write(signatureID[:])
+	h.Write(userHash)
+
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(at.UnixMicro()))
+	h.Write(ts[:])
+
+	return h.Sum(nil)
+}
+
+// appendToChain appends signatures, in order, to the chain of petitionID, all stamped with at.
+// It must run in a transaction holding the petition's row lock, which keeps concurrent appends
+// in line.
+func (p Petition) appendToChain(ctx context.Context, petitionID uuid.UUID, signatures []dbx.PetitionSignature, at time.Time) ([]dbx.SignatureChainEntry, error) {
+	return p.appendToChainAt(ctx, petitionID, signatures, func(dbx.PetitionSignature) time.Time { return at })
+}
+
+// appendToChainAt is appendToChain with each entry stamped with atOf of its signature.
+func (p Petition) appendToChainAt(
+	ctx context.Context,
+	petitionID uuid.UUID,
+	signatures []dbx.PetitionSignature,
+	atOf func(dbx.PetitionSignature) time.Time,
+) ([]dbx.SignatureChainEntry, error) {
+	if len(signatures) == 0 {
+		return nil, nil
+	}
+
+	seq, prevHash := int64(0), chainGenesis(petitionID)
+	head, err := p.chainQ.New().FilterPetitionID(petitionID).OrderBySeq(false).Get(ctx)
+	switch {
+	case err == nil:
+		seq, prevHash = head.Seq, head.Hash
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, err
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(signatures))
+	for _, sig := range signatures {
+		userIDs = append(userIDs, sig.UserID)
+	}
+	salts, err := p.signerSalts(ctx, userIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]dbx.SignatureChainEntry, 0, len(signatures))
+	for _, sig := range signatures {
+		seq++
+		at := atOf(sig).UTC().Truncate(time.Microsecond)
+		userHash := p.signerHash(petitionID, sig.UserID, salts[sig.UserID])
+		entry := dbx.SignatureChainEntry{
+			PetitionID:  petitionID,
+			Seq:         seq,
+			SignatureID: sig.ID,
+			UserHash:    userHash,
+			PrevHash:    prevHash,
+			Hash:        chainHash(prevHash, sig.ID, userHash, at),
+			CreatedAt:   at,
+		}
+		entries = append(entries, entry)
+		prevHash = entry.Hash
+	}
+
+	for start := 0; start < len(entries); start += chainBatchSize {
+		end := min(start+chainBatchSize, len(entries))
+		if err := p.chainQ.New().Insert(ctx, entries[start:end]...); err != nil {
+			return nil, err
+		}
+	}
+
+	return entries, nil
+}
+
+// VerifySignatureReceipt checks a receipt against the petition's chain: the signature's entry
+// must carry the receipt's hash, hash to it from its own fields and be linked to both of its
+// neighbours. For a valid receipt the entry and the chain's head are returned as well, so callers
+// can watch the chain only grow; otherwise only the problem is.
+func (p Petition) VerifySignatureReceipt(ctx context.Context, petitionID, signatureID uuid.UUID, hash []byte) (models.ReceiptVerification, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.VerifySignatureReceipt",
+		attribute.String("petition.id", petitionID.String()),
+		attribute.String("signature.id", signatureID.String()),
+	)
+	defer span.End()
+
+	if _, err := p.q.New().FilterID(petitionID).Get(ctx); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return models.ReceiptVerification{}, errx.RaisePetitionNotFoundByID(ctx, err, petitionID)
+		default:
+			return models.ReceiptVerification{}, errx.RaiseInternal(ctx, err)
+		}
+	}
+
+	entry, err := p.chainQ.New().FilterPetitionID(petitionID).FilterSignatureID(signatureID).Get(ctx)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return models.ReceiptVerification{Problem: models.ChainEntryMissing}, nil
+		default:
+			return models.ReceiptVerification{}, errx.RaiseInternal(ctx, err)
+		}
+	}
+
+	if !bytes.Equal(entry.Hash, hash) {
+		return models.ReceiptVerification{Problem: models.ChainHashMismatch}, nil
+	}
+
+	problem, err := p.checkLinks(ctx, entry)
+	if err != nil {
+		return models.ReceiptVerification{}, errx.RaiseInternal(ctx, err)
+	}
+	if problem != "" {
+		return models.ReceiptVerification{Problem: problem}, nil
+	}
+
+	head, err := p.chainQ.New().FilterPetitionID(petitionID).OrderBySeq(false).Get(ctx)
+	if err != nil {
+		return models.ReceiptVerification{}, errx.RaiseInternal(ctx, err)
+	}
+
+	return models.ReceiptVerification{
+		Valid: true,
+		Entry: receiptModel(entry),
+		Head:  &models.ChainHead{Seq: head.Seq, Hash: head.Hash},
+	}, nil
+}
+
+// checkLinks checks that entry hashes to its stored hash and is linked to its neighbours.
+func (p Petition) checkLinks(ctx context.Context, entry dbx.SignatureChainEntry) (string, error) {
+	if !bytes.Equal(chainHash(entry.PrevHash, entry.SignatureID, entry.UserHash, entry.CreatedAt), entry.Hash) {
+		return models.ChainEntryTampered, nil
+	}
+
+	prevHash := chainGenesis(entry.PetitionID)
+	if entry.Seq > 1 {
+		prev, err := p.chainQ.New().FilterPetitionID(entry.PetitionID).FilterSeq(entry.Seq - 1).Get(ctx)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return models.ChainSeqGap, nil
+		case err != nil:
+			return "", err
+		}
+		prevHash = prev.Hash
+	}
+	if !bytes.Equal(entry.PrevHash, prevHash) {
+		return models.ChainLinkBroken, nil
+	}
+
+	next, err := p.chainQ.New().FilterPetitionID(entry.PetitionID).FilterSeq(entry.Seq + 1).Get(ctx)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return "", nil
+	case err != nil:
+		return "", err
+	}
+	if !bytes.Equal(next.PrevHash, entry.Hash) {
+		return models.ChainLinkBroken, nil
+	}
+
+	return "", nil
+}
+
+// VerifySignatureChain re-verifies the whole chain of petitionID from its genesis and compares
+// it with the petition's counted signatures.
+func (p Petition) VerifySignatureChain(ctx context.Context, petitionID uuid.UUID) (models.ChainVerification, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.VerifySignatureChain", attribute.String("petition.id", petitionID.String()))
+	defer span.End()
+
+	petition, err := p.q.New().FilterID(petitionID).Get(ctx)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return models.ChainVerification{}, errx.RaisePetitionNotFoundByID(ctx, err, petitionID)
+		default:
+			return models.ChainVerification{}, errx.RaiseInternal(ctx, err)
+		}
+	}
+
+	res := models.ChainVerification{
+		PetitionID: petitionID,
+		Valid:      true,
+		HeadHash:   chainGenesis(petitionID),
+		Signatures: petition.Signatures,
+	}
+
+walk:
+	for {
+		entries, err := p.chainQ.New().
+			FilterPetitionID(petitionID).
+			FilterSeqAfter(res.Entries).
+			OrderBySeq(true).
+			Page(chainBatchSize, 0).
+			Select(ctx)
+		if err != nil {
+			return models.ChainVerification{}, errx.RaiseInternal(ctx, err)
+		}
+
+		for _, entry := range entries {
+			problem := ""
+			switch {
+			case entry.Seq != res.Entries+1:
+				problem = models.ChainSeqGap
+			case !bytes.Equal(entry.PrevHash, res.HeadHash):
+				problem = models.ChainLinkBroken
+			case !bytes.Equal(chainHash(entry.PrevHash, entry.SignatureID, entry.UserHash, entry.CreatedAt), entry.Hash):
+				problem = models.ChainEntryTampered
+			}
+			if problem != "" {
+				res.Valid, res.Problem, res.BrokenAt = false, problem, res.Entries+1
+				break walk
+			}
+
+			res.Entries, res.HeadHash = entry.Seq, entry.Hash
+		}
+
+		if len(entries) < chainBatchSize {
+			break
+		}
+	}
+
+	res.Unchained, err = p.sigQ.New().FilterPetitionID(petitionID).FilterInvalidated(false).FilterUnchained().Count(ctx)
+	if err != nil {
+		return models.ChainVerification{}, errx.RaiseInternal(ctx, fmt.Errorf("counting unchained signatures: %w", err))
+	}
+
+	return res, nil
+}
+
+// BackfillSignatureChain appends every signature missing from its petition's chain, such as the
+// ones given before chaining started, oldest first. Entries are stamped with the signature's own
+// created_at and follow whatever the chain already holds. Each batch is appended under the
+// petition's row lock, as SignPetition does, so it is safe while signatures keep coming.
+func (p Petition) BackfillSignatureChain(ctx context.Context) (models.ChainBackfill, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.BackfillSignatureChain")
+	defer span.End()
+
+	var res models.ChainBackfill
+	for offset := uint64(0); ; offset += chainBatchSize {
+		petitions, err := p.q.New().OrderByCreated(true).Page(chainBatchSize, offset).Select(ctx)
+		if err != nil {
+			return res, fmt.Errorf("selecting petitions: %w", err)
+		}
+
+		for _, petition := range petitions {
+			n, err := p.backfillPetitionChain(ctx, petition.ID)
+			if err != nil {
+				return res, fmt.Errorf("backfilling chain of petition %s: %w", petition.ID, err)
+			}
+			if n > 0 {
+				res.Petitions++
+				res.Signatures += n
+			}
+		}
+
+		if len(petitions) < chainBatchSize {
+			return res, nil
+		}
+	}
+}
+
+// backfillPetitionChain appends the unchained signatures of petitionID in batches, one
+// transaction each, and returns how many were appended.
+func (p Petition) backfillPetitionChain(ctx context.Context, petitionID uuid.UUID) (int, error) {
+	var total int
+	for {
+		var appended []dbx.SignatureChainEntry
+		err := dbx.Transaction(ctx, p.db, func(ctx context.Context) error {
+			if _, err := p.q.New().FilterID(petitionID).ForUpdate().Get(ctx); err != nil {
+				return err
+			}
+
+			signatures, err := p.sigQ.New().
+				FilterPetitionID(petitionID).
+				FilterUnchained().
+				OrderByCreated(true).
+				Page(chainBatchSize, 0).
+				Select(ctx)
+			if err != nil {
+				return err
+			}
+
+			appended, err = p.appendToChainAt(ctx, petitionID, signatures, func(sig dbx.PetitionSignature) time.Time {
+				return sig.CreatedAt
+			})
+			return err
+		})
+		if err != nil {
+			return total, err
+		}
+
+		total += len(appended)
+		if len(appended) < chainBatchSize {
+			return total, nil
+		}
+	}
+}
+
+func receiptModel(entry dbx.SignatureChainEntry) *models.SignatureReceipt {
+	return &models.SignatureReceipt{
+		PetitionID:  entry.PetitionID,
+		SignatureID: entry.SignatureID,
+		Seq:         entry.Seq,
+		UserHash:    entry.UserHash,
+		PrevHash:    entry.PrevHash,
+		Hash:        entry.Hash,
+		CreatedAt:   entry.CreatedAt,
+	}
+}
@@ -0,0 +1,351 @@
+package entities
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/chains-lab/city-petitions-svc/internal/app/models"
+	"github.com/chains-lab/city-petitions-svc/internal/config"
+	"github.com/chains-lab/city-petitions-svc/internal/constant/enum"
+	"github.com/chains-lab/city-petitions-svc/internal/dbx"
+	"github.com/chains-lab/city-petitions-svc/internal/errx"
+	"github.com/chains-lab/city-petitions-svc/internal/pagination"
+	"github.com/chains-lab/city-petitions-svc/internal/rbac"
+	"github.com/chains-lab/city-petitions-svc/internal/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Reasons a signature is flagged for review.
+const (
+	FraudReasonBurstVelocity = "burst_velocity" // the petition receives signatures unusually fast
+	FraudReasonNewSession    = "new_session"    // a fresh session signs many petitions right away
+	FraudReasonSharedSession = "shared_session" // one session signs on behalf of several users
+)
+
+// maxSharedSessionFlags bounds how many earlier signatures of a shared session are flagged at once.
+const maxSharedSessionFlags = 100
+
+type fraudDetector struct {
+	enabled bool
+
+	burstWindow time.Duration
+	burstLimit  uint64
+
+	newSessionWindow time.Duration
+	newSessionLimit  uint64
+}
+
+func newFraudDetector(cfg config.Config) fraudDetector {
+	fraud := cfg.Petitions.Fraud
+
+	return fraudDetector{
+		enabled:          fraud.Enabled,
+		burstWindow:      fraud.BurstWindow,
+		burstLimit:       uint64(fraud.BurstLimit),
+		newSessionWindow: fraud.NewSessionWindow,
+		newSessionLimit:  uint64(fraud.NewSessionLimit),
+	}
+}
+
+// analyzeSignature flags a just stored signature when it matches a fraud pattern.
+// Flags only mark signatures for review; nothing is invalidated automatically.
+func (p Petition) analyzeSignature(ctx context.Context, sig dbx.PetitionSignature) error {
+	if !p.fraud.enabled {
+		return nil
+	}
+
+	ctx, span := tracing.Start(ctx, "entities.Petition.analyzeSignature", attribute.String("signature.id", sig.ID.String()))
+	defer span.End()
+
+	var flags []dbx.SignatureFlag
+	flag := func(s dbx.PetitionSignature, reason, details string) {
+		flags = append(flags, dbx.SignatureFlag{
+			ID:          uuid.New(),
+			SignatureID: s.ID,
+			PetitionID:  s.PetitionID,
+			Reason:      reason,
+			Details:     details,
+			Status:      enum.SignatureFlagOpen,
+			CreatedAt:   sig.CreatedAt,
+		})
+	}
+
+	if p.fraud.burstLimit > 0 && p.fraud.burstWindow > 0 {
+		recent, err := p.sigQ.New().
+			FilterPetitionID(sig.PetitionID).
+			FilterCreatedAt(sig.CreatedAt.Add(-p.fraud.burstWindow), true).
+			Count(ctx)
+		if err != nil {
+			return fmt.Errorf("counting recent signatures of petition %s: %w", sig.PetitionID, err)
+		}
+
+		if recent > p.fraud.burstLimit {
+			flag(sig, FraudReasonBurstVelocity, fmt.Sprintf("%d signatures within %s", recent, p.fraud.burstWindow))
+		}
+	}
+
+	if !sig.SessionID.Valid {
+		return p.flagsQ.New().Insert(ctx, flags...)
+	}
+	sessionID := sig.SessionID.UUID
+
+	shared, err := p.sigQ.New().
+		FilterSessionID(sessionID).
+		FilterUserIDNot(sig.UserID).
+		OrderByCreated(false).
+		Page(maxSharedSessionFlags, 0).
+		Select(ctx)
+	if err != nil {
+		return fmt.Errorf("selecting signatures of session %s: %w", sessionID, err)
+	}
+
+	if len(shared) > 0 {
+		details := fmt.Sprintf("session %s also signed for other users", sessionID)
+		flag(sig, FraudReasonSharedSession, details)
+		for _, s := range shared {
+			flag(s, FraudReasonSharedSession, details)
+		}
+	}
+
+	if p.fraud.newSessionLimit > 0 && p.fraud.newSessionWindow > 0 {
+		first, err := p.sigQ.New().FilterSessionID(sessionID).OrderByCreated(true).Get(ctx)
+		if err != nil {
+			return fmt.Errorf("getting first signature of session %s: %w", sessionID, err)
+		}
+
+		if sig.CreatedAt.Sub(first.CreatedAt) <= p.fraud.newSessionWindow {
+			count, err := p.sigQ.New().FilterSessionID(sessionID).Count(ctx)
+			if err != nil {
+				return fmt.Errorf("counting signatures of session %s: %w", sessionID, err)
+			}
+
+			if count > p.fraud.newSessionLimit {
+				flag(sig, FraudReasonNewSession, fmt.Sprintf("%d signatures within %s of the session's first one", count, sig.CreatedAt.Sub(first.CreatedAt).Round(time.Second)))
+			}
+		}
+	}
+
+	return p.flagsQ.New().Insert(ctx, flags...)
+}
+
+type ListSignatureFlagsFilter struct {
+	CityID     uuid.UUID
+	PetitionID *uuid.UUID
+	Status     *string
+}
+
+// ListSignatureFlags lists fraud flags of petitions in a city, newest first.
+func (p Petition) ListSignatureFlags(
+	ctx context.Context,
+	initiator Initiator,
+	filter ListSignatureFlagsFilter,
+	pag pagination.Request,
+) ([]models.SignatureFlag, pagination.Response, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.ListSignatureFlags", attribute.String("city.id", filter.CityID.String()))
+	defer span.End()
+
+	if err := p.checkPermission(ctx, initiator, rbac.PermissionPetitionModerate, filter.CityID); err != nil {
+		return nil, pagination.Response{}, err
+	}
+
+	query := p.flagsQ.New().FilterCityID(filter.CityID)
+	if filter.PetitionID != nil {
+		query = query.FilterPetitionID(*filter.PetitionID)
+	}
+	if filter.Status != nil {
+		query = query.FilterStatus(*filter.Status)
+	}
+
+	total, err := query.Count(ctx)
+	if err != nil {
+		return nil, pagination.Response{}, errx.RaiseInternal(ctx, err)
+	}
+
+	limit, offset := pagination.CalculateLimitOffset(pag)
+
+	flags, err := query.OrderByCreated(false).Page(limit, offset).Select(ctx)
+	if err != nil {
+		return nil, pagination.Response{}, errx.RaiseInternal(ctx, err)
+	}
+
+	res := make([]models.SignatureFlag, 0, len(flags))
+	for _, f := range flags {
+		res = append(res, signatureFlagModel(f))
+	}
+
+	return res, pagination.Response{
+		Page:  pag.Page,
+		Size:  pag.Size,
+		Total: total,
+	}, nil
+}
+
+// DismissSignatureFlags closes open flags as false positives and returns how many were dismissed.
+func (p Petition) DismissSignatureFlags(ctx context.Context, initiator Initiator, flagIDs []uuid.UUID) (int, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.DismissSignatureFlags", attribute.Int("flags.count", len(flagIDs)))
+	defer span.End()
+
+	flags, err := p.flagsQ.New().FilterIDs(flagIDs...).Select(ctx)
+	if err != nil {
+		return 0, errx.RaiseInternal(ctx, err)
+	}
+
+	found := make(map[uuid.UUID]struct{}, len(flags))
+	petitionIDs := make([]uuid.UUID, 0, len(flags))
+	open := make([]uuid.UUID, 0, len(flags))
+	for _, f := range flags {
+		found[f.ID] = struct{}{}
+		petitionIDs = append(petitionIDs, f.PetitionID)
+		if f.Status == enum.SignatureFlagOpen {
+			open = append(open, f.ID)
+		}
+	}
+
+	for _, id := range flagIDs {
+		if _, ok := found[id]; !ok {
+			return 0, errx.RaiseSignatureFlagNotFoundByID(ctx, sql.ErrNoRows, id)
+		}
+	}
+
+	if err := p.checkPetitionsPermission(ctx, initiator, rbac.PermissionPetitionModerate, petitionIDs); err != nil {
+		return 0, err
+	}
+
+	if len(open) == 0 {
+		return 0, nil
+	}
+
+	status := enum.SignatureFlagDismissed
+	now := time.Now().UTC()
+
+	dismissed, err := p.flagsQ.New().FilterIDs(open...).FilterStatus(enum.SignatureFlagOpen).Update(ctx, dbx.UpdateSignatureFlagInput{
+		Status:     &status,
+		ReviewedBy: &initiator.ID,
+		ReviewedAt: &now,
+	})
+	if err != nil {
+		return 0, errx.RaiseInternal(ctx, err)
+	}
+
+	return int(dismissed), nil
+}
+
+// InvalidateSignatures stops counting the given signatures and confirms their open flags.
+// Signatures that are already invalidated are skipped; the number of newly invalidated ones is returned.
+func (p Petition) InvalidateSignatures(ctx context.Context, initiator Initiator, signatureIDs []uuid.UUID, reason string) (int, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.InvalidateSignatures", attribute.Int("signatures.count", len(signatureIDs)))
+	defer span.End()
+
+	signatures, err := p.sigQ.New().FilterIDs(signatureIDs...).Select(ctx)
+	if err != nil {
+		return 0, errx.RaiseInternal(ctx, err)
+	}
+
+	found := make(map[uuid.UUID]struct{}, len(signatures))
+	petitionIDs := make([]uuid.UUID, 0, len(signatures))
+	valid := make([]uuid.UUID, 0, len(signatures))
+	for _, sig := range signatures {
+		found[sig.ID] = struct{}{}
+		petitionIDs = append(petitionIDs, sig.PetitionID)
+		if !sig.Invalidated {
+			valid = append(valid, sig.ID)
+		}
+	}
+
+	for _, id := range signatureIDs {
+		if _, ok := found[id]; !ok {
+			return 0, errx.RaisePetitionSignaturesNotFoundByID(ctx, sql.ErrNoRows, id)
+		}
+	}
+
+	if err := p.checkPetitionsPermission(ctx, initiator, rbac.PermissionPetitionModerate, petitionIDs); err != nil {
+		return 0, err
+	}
+
+	if len(valid) == 0 {
+		return 0, nil
+	}
+
+	invalidated := true
+	now := time.Now().UTC()
+
+	var invalidatedCount int64
+	err = dbx.Transaction(ctx, p.db, func(ctx context.Context) error {
+		invalidatedCount, err = p.sigQ.New().FilterIDs(valid...).FilterInvalidated(false).Update(ctx, dbx.UpdatePetitionSignatureInput{
+			Invalidated:       &invalidated,
+			InvalidatedReason: &reason,
+			InvalidatedBy:     &initiator.ID,
+			InvalidatedAt:     &now,
+		})
+		if err != nil {
+			return fmt.Errorf("invalidating signatures: %w", err)
+		}
+
+		confirmed := enum.SignatureFlagConfirmed
+		_, err = p.flagsQ.New().FilterSignatureIDs(valid...).FilterStatus(enum.SignatureFlagOpen).Update(ctx, dbx.UpdateSignatureFlagInput{
+			Status:     &confirmed,
+			ReviewedBy: &initiator.ID,
+			ReviewedAt: &now,
+		})
+		if err != nil {
+			return fmt.Errorf("confirming signature flags: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, errx.RaiseInternal(ctx, err)
+	}
+
+	p.cache.invalidateCounts(ctx, petitionIDs...)
+
+	return int(invalidatedCount), nil
+}
+
+// checkPetitionsPermission checks perm in the city of every given petition.
+func (p Petition) checkPetitionsPermission(ctx context.Context, initiator Initiator, perm rbac.Permission, petitionIDs []uuid.UUID) error {
+	if len(petitionIDs) == 0 {
+		return nil
+	}
+
+	petitions, err := p.q.New().FilterIDs(petitionIDs...).Select(ctx)
+	if err != nil {
+		return errx.RaiseInternal(ctx, err)
+	}
+
+	checked := make(map[uuid.UUID]struct{}, len(petitions))
+	for _, petition := range petitions {
+		if _, ok := checked[petition.CityID]; ok {
+			continue
+		}
+		if err := p.checkPermission(ctx, initiator, perm, petition.CityID); err != nil {
+			return err
+		}
+		checked[petition.CityID] = struct{}{}
+	}
+
+	return nil
+}
+
+func signatureFlagModel(f dbx.SignatureFlag) models.SignatureFlag {
+	res := models.SignatureFlag{
+		ID:          f.ID,
+		SignatureID: f.SignatureID,
+		PetitionID:  f.PetitionID,
+		Reason:      f.Reason,
+		Details:     f.Details,
+		Status:      f.Status,
+		CreatedAt:   f.CreatedAt,
+	}
+	if f.ReviewedBy.Valid {
+		res.ReviewedBy = &f.ReviewedBy.UUID
+	}
+	if f.ReviewedAt.Valid {
+		res.ReviewedAt = &f.ReviewedAt.Time
+	}
+
+	return res
+}
@@ -0,0 +1,153 @@
+package entities
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/chains-lab/city-petitions-svc/internal/app/models"
+	"github.com/chains-lab/city-petitions-svc/internal/constant/enum"
+	"github.com/chains-lab/city-petitions-svc/internal/dbx"
+	"github.com/chains-lab/city-petitions-svc/internal/errx"
+	"github.com/chains-lab/city-petitions-svc/internal/rbac"
+	"github.com/chains-lab/city-petitions-svc/internal/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ListSignatureReasons returns up to limit reasons signers gave for petitionID, the ones of
+// verified signers first, newest first within each group. Reasons of private signatures and
+// reasons held by the content filter are never listed; anonymous signers are not revealed.
+func (p Petition) ListSignatureReasons(ctx context.Context, petitionID uuid.UUID, limit uint64) ([]models.PetitionSignature, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.ListSignatureReasons", attribute.String("petition.id", petitionID.String()))
+	defer span.End()
+
+	if _, err := p.q.New().FilterID(petitionID).Get(ctx); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, errx.RaisePetitionNotFoundByID(ctx, err, petitionID)
+		default:
+			return nil, errx.RaiseInternal(ctx, err)
+		}
+	}
+
+	signatures, err := p.sigQ.New().
+		FilterPetitionID(petitionID).
+		FilterInvalidated(false).
+		FilterVisibility(enum.SignaturePublic, enum.SignatureAnonymous).
+		FilterShownReason().
+		OrderByVerifiedFirst().
+		Page(limit, 0).
+		Select(ctx)
+	if err != nil {
+		return nil, errx.RaiseInternal(ctx, err)
+	}
+
+	res := make([]models.PetitionSignature, 0, len(signatures))
+	for _, sig := range signatures {
+		res = append(res, signatureFor(sig, nil, false))
+	}
+
+	return res, nil
+}
+
+// GetSignatureStats returns aggregate counts of the signatures of petitionID. It is meant for
+// the petition's authors, who do not get the full signer list; officials and admins may read
+// it as well.
+func (p Petition) GetSignatureStats(ctx context.Context, initiator Initiator, petitionID uuid.UUID) (models.SignatureStats, error) {
+	ctx, span := tracing.Start(ctx, "entities.Petition.GetSignatureStats", attribute.String("petition.id", petitionID.String()))
+	defer span.End()
+
+	petition, err := p.q.New().FilterID(petitionID).Get(ctx)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return models.SignatureStats{}, errx.RaisePetitionNotFoundByID(ctx, err, petitionID)
+		default:
+			return models.SignatureStats{}, errx.RaiseInternal(ctx, err)
+		}
+	}
+
+	if !p.seesAllSigners(initiator.subject(), petition.CityID) {
+		if err := p.checkAuthor(ctx, initiator, petition); err != nil {
+			return models.SignatureStats{}, err
+		}
+	}
+
+	st, err := p.sigQ.New().Stats(ctx, petitionID)
+	if err != nil {
+		return models.SignatureStats{}, errx.RaiseInternal(ctx, err)
+	}
+
+	return models.SignatureStats{
+		PetitionID:  petitionID,
+		Total:       int(st.Total),
+		Verified:    int(st.Verified),
+		Invalidated: int(st.Invalidated),
+		Public:      int(st.Public),
+		Anonymous:   int(st.Anonymous),
+		Private:     int(st.Private),
+		WithReason:  int(st.WithReason),
+	}, nil
+}
+
+// seesAllSigners reports whether subject is an official or an admin in cityID.
+func (p Petition) seesAllSigners(subject rbac.Subject, cityID uuid.UUID) bool {
+	return p.access.Can(subject, rbac.PermissionPetitionAnswer, cityID) ||
+		p.access.Can(subject, rbac.PermissionPetitionAdmin, cityID)
+}
+
+// listsAllSigners reports whether viewer gets the full signer list of petitionID. Without a
+// petition there is no city to check, so nobody does.
+func (p Petition) listsAllSigners(ctx context.Context, viewer *Initiator, petitionID *uuid.UUID) (bool, error) {
+	if viewer == nil || petitionID == nil {
+		return false, nil
+	}
+
+	petition, err := p.q.New().FilterID(*petitionID).Get(ctx)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return false, nil
+		default:
+			return false, errx.RaiseInternal(ctx, err)
+		}
+	}
+
+	return p.seesAllSigners(viewer.subject(), petition.CityID), nil
+}
+
+// visibleSignatures narrows query to public signatures and, for a signed in viewer, their own.
+// Anonymous and private signatures are left out entirely, so neither a petition_id nor a
+// user_id filter can reveal who signed.
+func visibleSignatures(query dbx.PetitionSignaturesQ, viewer *Initiator) dbx.PetitionSignaturesQ {
+	if viewer != nil {
+		return query.FilterVisibilityOrUserID(viewer.ID, enum.SignaturePublic)
+	}
+
+	return query.FilterVisibility(enum.SignaturePublic)
+}
+
+// signatureFor converts sig to the model, leaving out what viewer may not see. full is set
+// for viewers who see all signers.
+func signatureFor(sig dbx.PetitionSignature, viewer *Initiator, full bool) models.PetitionSignature {
+	res := petitionSignatureModel(sig)
+	if full || (viewer != nil && viewer.ID == sig.UserID) {
+		return res
+	}
+
+	if sig.ReasonHidden {
+		res.Reason = ""
+	}
+
+	switch sig.Visibility {
+	case enum.SignaturePublic:
+	case enum.SignatureAnonymous:
+		res.UserID = uuid.Nil
+	default:
+		res.UserID = uuid.Nil
+		res.Reason = ""
+	}
+
+	return res
+}
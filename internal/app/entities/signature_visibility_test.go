@@ -0,0 +1,272 @@
+package entities
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chains-lab/city-petitions-svc/internal/config"
+	"github.com/chains-lab/city-petitions-svc/internal/constant/enum"
+	"github.com/chains-lab/city-petitions-svc/internal/dbx"
+	"github.com/chains-lab/city-petitions-svc/internal/rbac"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	visCityID     = uuid.MustParse("00000000-0000-0000-0000-0000000000c1")
+	visPetitionID = uuid.MustParse("00000000-0000-0000-0000-0000000000b1")
+	visCreatorID  = uuid.MustParse("00000000-0000-0000-0000-0000000000a1")
+	visCoAuthorID = uuid.MustParse("00000000-0000-0000-0000-0000000000a2")
+	visSignerID   = uuid.MustParse("00000000-0000-0000-0000-0000000000a3")
+	visOfficialID = uuid.MustParse("00000000-0000-0000-0000-0000000000a4")
+)
+
+// seen is what a caller learns about a signature: the signer and the reason.
+type seen struct {
+	user, reason bool
+}
+
+var (
+	seesAll    = [4]seen{{true, true}, {true, true}, {true, true}, {true, true}}
+	seesPublic = [4]seen{{true, true}, {true, false}, {false, true}, {false, false}}
+)
+
+// visCallers are the callers every visibility rule is checked for. sees lists, for the
+// signatures of visSignatures in order, what the caller gets from signatureFor.
+var visCallers = []struct {
+	name     string
+	viewer   *Initiator
+	listsAll bool
+	sees     [4]seen
+	stats    codes.Code
+}{
+	{"anonymous", nil, false, seesPublic, codes.PermissionDenied},
+	{"non-signer", &Initiator{ID: uuid.MustParse("00000000-0000-0000-0000-0000000000a5"), Role: enum.UserRoleUser}, false, seesPublic, codes.PermissionDenied},
+	{"signer", &Initiator{ID: visSignerID, Role: enum.UserRoleUser}, false, seesAll, codes.PermissionDenied},
+	{"creator", &Initiator{ID: visCreatorID, Role: enum.UserRoleUser}, false, seesPublic, codes.OK},
+	{"co-author", &Initiator{ID: visCoAuthorID, Role: enum.UserRoleUser}, false, seesPublic, codes.OK},
+	{"moderator", &Initiator{ID: uuid.MustParse("00000000-0000-0000-0000-0000000000a6"), Role: enum.UserRoleModerator}, true, seesAll, codes.OK},
+	{"official", &Initiator{ID: visOfficialID, Role: enum.UserRoleUser}, true, seesAll, codes.OK},
+	{"admin", &Initiator{ID: uuid.MustParse("00000000-0000-0000-0000-0000000000a7"), Role: enum.UserRoleAdmin}, true, seesAll, codes.OK},
+}
+
+// visSignatures are signatures of visSignerID: public, public with a held reason, anonymous
+// and private, each with a reason.
+func visSignatures() []dbx.PetitionSignature {
+	sig := func(visibility string, reasonHidden bool) dbx.PetitionSignature {
+		return dbx.PetitionSignature{
+			ID:           uuid.New(),
+			PetitionID:   visPetitionID,
+			UserID:       visSignerID,
+			Reason:       sql.NullString{String: "because", Valid: true},
+			ReasonHidden: reasonHidden,
+			Visibility:   visibility,
+		}
+	}
+
+	return []dbx.PetitionSignature{
+		sig(enum.SignaturePublic, false),
+		sig(enum.SignaturePublic, true),
+		sig(enum.SignatureAnonymous, false),
+		sig(enum.SignaturePrivate, false),
+	}
+}
+
+// newVisibilityPetition returns a Petition over a fake database holding visPetitionID, created
+// by visCreatorID with visCoAuthorID as accepted co-author, in a city where visOfficialID may
+// answer petitions.
+func newVisibilityPetition(t *testing.T) (Petition, *fakeDB) {
+	t.Helper()
+
+	v := viper.New()
+	v.SetConfigType("yaml")
+	err := v.ReadConfig(strings.NewReader(`
+grants:
+  - user_id: "` + visOfficialID.String() + `"
+    city_id: "` + visCityID.String() + `"
+    permissions: ["petition.answer"]
+`))
+	if err != nil {
+		t.Fatalf("reading rbac config: %v", err)
+	}
+
+	var cfg config.RBACConfig
+	if err := v.Unmarshal(&cfg); err != nil {
+		t.Fatalf("unmarshalling rbac config: %v", err)
+	}
+
+	access, err := rbac.New(cfg)
+	if err != nil {
+		t.Fatalf("rbac.New: %v", err)
+	}
+
+	now := time.Now().UTC()
+	db, fake := openFakeDB(t, func(query string, args []driver.NamedValue) ([]string, [][]driver.Value) {
+		switch {
+		case strings.Contains(query, "FROM petitions "):
+			columns := []string{"id", "city_id", "creator_id", "title", "description", "status", "signatures", "goal", "reply", "end_date", "created_at", "updated_at", "merged_into", "closed_at"}
+			if len(args) == 0 || args[0].Value != visPetitionID.String() {
+				return columns, nil
+			}
+
+			return columns, [][]driver.Value{{
+				visPetitionID.String(), visCityID.String(), visCreatorID.String(), "title", "description",
+				enum.PetitionPublished, int64(4), int64(100), "", now.Add(time.Hour), now, now, nil, nil,
+			}}
+		case strings.Contains(query, "FROM petition_authors"):
+			count := int64(0)
+			if hasArg(args, visCoAuthorID.String()) && hasArg(args, enum.PetitionAuthorAccepted) {
+				count = 1
+			}
+
+			return []string{"count"}, [][]driver.Value{{count}}
+		case strings.Contains(query, "FILTER (WHERE"):
+			return []string{"total", "verified", "invalidated", "public", "anonymous", "private", "with_reason"},
+				[][]driver.Value{{int64(4), int64(3), int64(1), int64(2), int64(1), int64(1), int64(4)}}
+		case strings.Contains(query, "FROM petition_signatures"):
+			return []string{"count"}, [][]driver.Value{{int64(0)}}
+		}
+
+		return nil, nil
+	})
+
+	return Petition{
+		db:       db,
+		q:        dbx.NewPetitionsQ(db),
+		sigQ:     dbx.NewPetitionSignaturesQ(db),
+		authorsQ: dbx.NewPetitionAuthorsQ(db),
+		access:   access,
+	}, fake
+}
+
+func hasArg(args []driver.NamedValue, value driver.Value) bool {
+	return slices.ContainsFunc(args, func(a driver.NamedValue) bool { return a.Value == value })
+}
+
+func TestListsAllSigners(t *testing.T) {
+	p, _ := newVisibilityPetition(t)
+	ctx := context.Background()
+
+	for _, tt := range visCallers {
+		t.Run(tt.name, func(t *testing.T) {
+			petitionID := visPetitionID
+
+			got, err := p.listsAllSigners(ctx, tt.viewer, &petitionID)
+			if err != nil {
+				t.Fatalf("listsAllSigners: %v", err)
+			}
+			if got != tt.listsAll {
+				t.Errorf("listsAllSigners = %t, want %t", got, tt.listsAll)
+			}
+		})
+	}
+
+	admin := visCallers[len(visCallers)-1].viewer
+	unknown := uuid.New()
+
+	t.Run("admin without petition", func(t *testing.T) {
+		if got, err := p.listsAllSigners(ctx, admin, nil); err != nil || got {
+			t.Errorf("listsAllSigners = %t, %v, want false", got, err)
+		}
+	})
+	t.Run("admin on unknown petition", func(t *testing.T) {
+		if got, err := p.listsAllSigners(ctx, admin, &unknown); err != nil || got {
+			t.Errorf("listsAllSigners = %t, %v, want false", got, err)
+		}
+	})
+}
+
+func TestVisibleSignatures(t *testing.T) {
+	p, fake := newVisibilityPetition(t)
+	ctx := context.Background()
+
+	for _, tt := range visCallers {
+		t.Run(tt.name, func(t *testing.T) {
+			query := visibleSignatures(p.sigQ.New().FilterPetitionID(visPetitionID), tt.viewer)
+			if _, err := query.Count(ctx); err != nil {
+				t.Fatalf("Count: %v", err)
+			}
+
+			got := fake.last()
+			if !slices.Contains(got.args, driver.Value(enum.SignaturePublic)) {
+				t.Errorf("public signatures are not selected: %s %v", got.query, got.args)
+			}
+			for _, hidden := range []string{enum.SignatureAnonymous, enum.SignaturePrivate} {
+				if slices.Contains(got.args, driver.Value(hidden)) {
+					t.Errorf("%s signatures are selected: %s %v", hidden, got.query, got.args)
+				}
+			}
+
+			own := tt.viewer != nil && slices.Contains(got.args, driver.Value(tt.viewer.ID.String()))
+			if own != (tt.viewer != nil) {
+				t.Errorf("own signatures selected = %t, want %t: %s %v", own, tt.viewer != nil, got.query, got.args)
+			}
+		})
+	}
+}
+
+func TestSignatureFor(t *testing.T) {
+	kinds := []string{"public", "public with held reason", "anonymous", "private"}
+
+	for _, tt := range visCallers {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, sig := range visSignatures() {
+				res := signatureFor(sig, tt.viewer, tt.listsAll)
+
+				got := seen{user: res.UserID == sig.UserID, reason: res.Reason == sig.Reason.String}
+				if got != tt.sees[i] {
+					t.Errorf("%s signature: sees %+v, want %+v", kinds[i], got, tt.sees[i])
+				}
+				if !got.user && res.UserID != uuid.Nil {
+					t.Errorf("%s signature: hidden signer replaced by %s, want nil UUID", kinds[i], res.UserID)
+				}
+				if res.ID != sig.ID || res.PetitionID != sig.PetitionID {
+					t.Errorf("%s signature: identity of the signature changed", kinds[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetSignatureStats(t *testing.T) {
+	p, _ := newVisibilityPetition(t)
+	ctx := context.Background()
+
+	for _, tt := range visCallers {
+		t.Run(tt.name, func(t *testing.T) {
+			// the RPC needs a user token; a zero initiator stands in for a caller without one
+			var initiator Initiator
+			if tt.viewer != nil {
+				initiator = *tt.viewer
+			}
+
+			st, err := p.GetSignatureStats(ctx, initiator, visPetitionID)
+			if got := status.Code(err); got != tt.stats {
+				t.Fatalf("code = %s, want %s (err: %v)", got, tt.stats, err)
+			}
+			if err != nil {
+				return
+			}
+
+			want := [7]int{4, 3, 1, 2, 1, 1, 4}
+			got := [7]int{st.Total, st.Verified, st.Invalidated, st.Public, st.Anonymous, st.Private, st.WithReason}
+			if got != want || st.PetitionID != visPetitionID {
+				t.Errorf("stats = %v of %s, want %v of %s", got, st.PetitionID, want, visPetitionID)
+			}
+		})
+	}
+
+	t.Run("unknown petition", func(t *testing.T) {
+		_, err := p.GetSignatureStats(ctx, Initiator{ID: visCreatorID, Role: enum.UserRoleUser}, uuid.New())
+		if got := status.Code(err); got != codes.NotFound {
+			t.Errorf("code = %s, want %s", got, codes.NotFound)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package entities
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chains-lab/city-petitions-svc/internal/config"
+	"github.com/chains-lab/city-petitions-svc/internal/errx"
+	"github.com/google/uuid"
+)
+
+const (
+	verificationActionCreate = "create"
+	verificationActionSign   = "sign"
+)
+
+type verificationPolicy struct {
+	global config.VerificationPolicy
+	cities map[string]config.VerificationPolicy
+}
+
+func newVerificationPolicy(cfg config.Config) verificationPolicy {
+	return verificationPolicy{
+		global: cfg.Petitions.Verification.VerificationPolicy,
+		cities: cfg.Petitions.Verification.Cities,
+	}
+}
+
+// forCity returns the city override if one is configured, otherwise the global policy.
+func (v verificationPolicy) forCity(cityID uuid.UUID) config.VerificationPolicy {
+	if policy, ok := v.cities[cityID.String()]; ok {
+		return policy
+	}
+
+	return v.global
+}
+
+func (v verificationPolicy) check(ctx context.Context, cityID uuid.UUID, initiator Initiator, action string) error {
+	if initiator.Verified {
+		return nil
+	}
+
+	policy := v.forCity(cityID)
+
+	required := false
+	switch action {
+	case verificationActionCreate:
+		required = policy.Create
+	case verificationActionSign:
+		required = policy.Sign
+	}
+
+	if required {
+		return errx.RaiseUserNotVerified(
+			ctx,
+			fmt.Errorf("user %s is not verified, city %s requires verification to %s", initiator.ID, cityID, action),
+			initiator.ID,
+			action,
+		)
+	}
+
+	return nil
+}
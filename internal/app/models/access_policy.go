@@ -0,0 +1,12 @@
+package models
+
+import "github.com/google/uuid"
+
+// AccessRule is a permission held by a role, or granted to a single user when UserID is set.
+// Empty CityIDs means every city.
+type AccessRule struct {
+	Role       string
+	UserID     *uuid.UUID
+	Permission string
+	CityIDs    []uuid.UUID
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Comment is a post in the discussion thread of a petition. Bodies of deleted and removed
+// comments are blanked for everyone but moderators.
+type Comment struct {
+	ID         uuid.UUID
+	PetitionID uuid.UUID
+	ParentID   *uuid.UUID
+	Depth      int
+	AuthorID   uuid.UUID
+	Body       string
+	Status     string
+	Replies    int
+	Deleted    bool
+	EditedAt   *time.Time
+	CreatedAt  time.Time
+}
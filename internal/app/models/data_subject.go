@@ -0,0 +1,106 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit actions a user may have taken on content of other users.
+const (
+	AuditSignatureInvalidated  = "signature_invalidated"
+	AuditSignatureFlagReviewed = "signature_flag_reviewed"
+	AuditContentFlagReviewed   = "content_flag_reviewed"
+	AuditCommentReportReviewed = "comment_report_reviewed"
+	AuditCommentDeleted        = "comment_deleted"
+)
+
+// UserDataExport is everything the service stores about one user, as handed out on a data
+// subject access request. It is serialized to JSON as is.
+type UserDataExport struct {
+	UserID      uuid.UUID `json:"user_id"`
+	GeneratedAt time.Time `json:"generated_at"`
+
+	Petitions      []ExportedPetition      `json:"petitions"`
+	CoAuthorships  []ExportedCoAuthorship  `json:"co_authorships"`
+	Signatures     []ExportedSignature     `json:"signatures"`
+	Comments       []ExportedComment       `json:"comments"`
+	CommentReports []ExportedCommentReport `json:"comment_reports"`
+	// Audit lists moderation actions the user took as an official or moderator.
+	Audit []AuditEntry `json:"audit"`
+}
+
+type ExportedPetition struct {
+	ID          uuid.UUID  `json:"id"`
+	CityID      uuid.UUID  `json:"city_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	Signatures  int        `json:"signatures"`
+	Reply       string     `json:"reply,omitempty"`
+	MergedInto  *uuid.UUID `json:"merged_into,omitempty"`
+	EndDate     time.Time  `json:"end_date"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+type ExportedCoAuthorship struct {
+	PetitionID  uuid.UUID  `json:"petition_id"`
+	Status      string     `json:"status"`
+	InvitedBy   uuid.UUID  `json:"invited_by"`
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type ExportedSignature struct {
+	ID                uuid.UUID  `json:"id"`
+	PetitionID        uuid.UUID  `json:"petition_id"`
+	SessionID         *uuid.UUID `json:"session_id,omitempty"`
+	Verified          bool       `json:"verified"`
+	Reason            string     `json:"reason,omitempty"`
+	Visibility        string     `json:"visibility"`
+	Invalidated       bool       `json:"invalidated"`
+	InvalidatedReason string     `json:"invalidated_reason,omitempty"`
+	MergedFrom        *uuid.UUID `json:"merged_from,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+type ExportedComment struct {
+	ID         uuid.UUID  `json:"id"`
+	PetitionID uuid.UUID  `json:"petition_id"`
+	ParentID   *uuid.UUID `json:"parent_id,omitempty"`
+	Body       string     `json:"body"`
+	Status     string     `json:"status"`
+	EditedAt   *time.Time `json:"edited_at,omitempty"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type ExportedCommentReport struct {
+	ID        uuid.UUID `json:"id"`
+	CommentID uuid.UUID `json:"comment_id"`
+	Reason    string    `json:"reason"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AuditEntry is one moderation action. Outcome is the resulting status where the action has one.
+type AuditEntry struct {
+	Action     string     `json:"action"`
+	TargetID   uuid.UUID  `json:"target_id"`
+	PetitionID *uuid.UUID `json:"petition_id,omitempty"`
+	Outcome    string     `json:"outcome,omitempty"`
+	At         time.Time  `json:"at"`
+}
+
+// UserDataErasure counts the rows anonymized by an erasure request, per kind of data.
+type UserDataErasure struct {
+	UserID         uuid.UUID
+	Petitions      int
+	Signatures     int
+	CoAuthorships  int
+	Comments       int
+	CommentReports int
+	SignatureFlags int
+	ContentFlags   int
+}
@@ -19,11 +19,83 @@ type Petition struct {
 	EndDate     time.Time
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+	MergedInto  *uuid.UUID
+	CoAuthors   []PetitionAuthor
+}
+
+// PetitionAuthor is a co-author invited by the creator of a petition.
+type PetitionAuthor struct {
+	PetitionID  uuid.UUID
+	UserID      uuid.UUID
+	Status      string
+	InvitedBy   uuid.UUID
+	RespondedAt *time.Time
+	CreatedAt   time.Time
+}
+
+// DuplicateCandidate is an open petition that resembles a new one.
+type DuplicateCandidate struct {
+	Petition   Petition
+	Similarity float64 // trigram similarity of the titles, from 0 to 1
 }
 
 type PetitionSignature struct {
 	ID         uuid.UUID
 	PetitionID uuid.UUID
-	UserID     uuid.UUID
+	UserID     uuid.UUID // uuid.Nil when the viewer may not see the signer
+	CreatedAt  time.Time
+	Verified   bool
+
+	Invalidated       bool
+	InvalidatedReason string
+
+	Reason     string // empty when there is none or the viewer may not see it
+	Visibility string
+
+	Receipt *SignatureReceipt // set only for the signer, right after signing
+}
+
+// SignatureStats is the aggregate view of a petition's signatures given to its authors.
+type SignatureStats struct {
+	PetitionID  uuid.UUID
+	Total       int
+	Verified    int
+	Invalidated int
+	Public      int
+	Anonymous   int
+	Private     int
+	WithReason  int
+}
+
+type PetitionUpdate struct {
+	PetitionID uuid.UUID
+	Signatures int
+	Status     string
+	UpdatedAt  time.Time
+	MergedInto *uuid.UUID
+}
+
+type SignatureFlag struct {
+	ID          uuid.UUID
+	SignatureID uuid.UUID
+	PetitionID  uuid.UUID
+	Reason      string
+	Details     string
+	Status      string
+	ReviewedBy  *uuid.UUID
+	ReviewedAt  *time.Time
+	CreatedAt   time.Time
+}
+
+type ContentFlag struct {
+	ID         uuid.UUID
+	PetitionID uuid.UUID
+	Field      string
+	Filter     string
+	Action     string
+	Details    string
+	Status     string
+	ReviewedBy *uuid.UUID
+	ReviewedAt *time.Time
 	CreatedAt  time.Time
 }
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RetentionReport describes one run of the signature retention job.
+type RetentionReport struct {
+	StartedAt  time.Time
+	DryRun     bool
+	Petitions  []RetainedPetition // petitions with signatures pseudonymized, or due to be on a dry run
+	Signatures int
+}
+
+// RetainedPetition is a closed petition whose signers were pseudonymized by a retention run.
+type RetainedPetition struct {
+	PetitionID uuid.UUID
+	CityID     uuid.UUID
+	ClosedAt   time.Time
+	Period     time.Duration
+	Signatures int
+}
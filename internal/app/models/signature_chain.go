@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Problems found while verifying a signature chain or a receipt.
+const (
+	ChainEntryMissing  = "entry_missing"  // the receipt's signature is not in the chain
+	ChainHashMismatch  = "hash_mismatch"  // the receipt's hash differs from the chain's
+	ChainEntryTampered = "entry_tampered" // an entry does not hash to its stored hash
+	ChainLinkBroken    = "link_broken"    // an entry does not point at the hash of the previous one
+	ChainSeqGap        = "seq_gap"        // entries are missing from the chain
+)
+
+// SignatureReceipt is a signature's entry in its petition's hash chain. Signers keep it to prove
+// later that their signature was recorded and has not been dropped or altered.
+type SignatureReceipt struct {
+	PetitionID  uuid.UUID
+	SignatureID uuid.UUID
+	Seq         int64
+	UserHash    []byte
+	PrevHash    []byte
+	Hash        []byte
+	CreatedAt   time.Time
+}
+
+// ChainHead identifies the last entry of a petition's chain.
+type ChainHead struct {
+	Seq  int64
+	Hash []byte
+}
+
+// ReceiptVerification is the outcome of checking a receipt against the chain. Entry and Head are
+// only set for a valid receipt, so a guessed hash reveals nothing about the chain.
+type ReceiptVerification struct {
+	Valid   bool
+	Problem string            // one of the Chain* problems when not valid
+	Entry   *SignatureReceipt // the chain's entry of the signature
+	Head    *ChainHead        // last entry of the chain
+}
+
+// ChainVerification is the outcome of re-verifying a petition's chain from its first entry.
+type ChainVerification struct {
+	PetitionID uuid.UUID
+	Valid      bool
+	Problem    string // one of the Chain* problems when not valid
+	BrokenAt   int64  // seq of the first bad entry when not valid
+	Entries    int64
+	HeadHash   []byte
+	Signatures int    // the petition's counter
+	Unchained  uint64 // counted signatures of the petition missing from the chain, such as ones given before chaining started; see BackfillSignatureChain
+}
+
+// ChainBackfill reports the signatures appended to chains by a backfill.
+type ChainBackfill struct {
+	Petitions  int // petitions whose chain grew
+	Signatures int
+}
@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chains-lab/city-petitions-svc/internal/config"
+)
+
+const (
+	BackendRedis  = "redis"
+	BackendMemory = "memory"
+)
+
+// Cache is a byte-oriented key/value store with per-entry expiry.
+// A zero ttl keeps the entry until it is deleted.
+type Cache interface {
+	// Get returns the value stored under key; ok is false on a miss.
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	// Incr atomically increments the integer stored under key and returns the new value.
+	Incr(ctx context.Context, key string) (int64, error)
+}
+
+// New builds the cache selected by cfg.Cache.Backend.
+// It returns nil when caching is disabled.
+func New(cfg config.Config) (Cache, error) {
+	if !cfg.Cache.Enabled {
+		return nil, nil
+	}
+
+	switch cfg.Cache.Backend {
+	case BackendRedis, "":
+		return NewRedis(cfg.Database.Redis.Addr, cfg.Database.Redis.Password, cfg.Database.Redis.DB), nil
+	case BackendMemory:
+		return NewMemory(), nil
+	default:
+		return nil, fmt.Errorf("unknown cache backend '%s'", cfg.Cache.Backend)
+	}
+}
@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// memorySweepInterval is how often Set drops expired entries that were never read again.
+const memorySweepInterval = time.Minute
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time // zero means no expiry
+}
+
+// Memory is an in-process Cache. Entries are not shared between
+// replicas, so it suits single-instance deployments and development.
+type Memory struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	nextSweep time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		entries: make(map[string]memoryEntry),
+	}
+}
+
+func (m *Memory) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.lookup(key)
+	if !ok {
+		return nil, false, nil
+	}
+
+	return entry.value, true, nil
+}
+
+func (m *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	entry := memoryEntry{value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+
+	m.mu.Lock()
+	m.sweep()
+	m.entries[key] = entry
+	m.mu.Unlock()
+
+	return nil
+}
+
+func (m *Memory) Delete(_ context.Context, keys ...string) error {
+	m.mu.Lock()
+	for _, key := range keys {
+		delete(m.entries, key)
+	}
+	m.mu.Unlock()
+
+	return nil
+}
+
+func (m *Memory) Incr(_ context.Context, key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var n int64
+	entry, ok := m.lookup(key)
+	if ok {
+		var err error
+		n, err = strconv.ParseInt(string(entry.value), 10, 64)
+		if err != nil {
+			return 0, err
+		}
+	}
+	n++
+
+	entry.value = []byte(strconv.FormatInt(n, 10))
+	m.entries[key] = entry
+
+	return n, nil
+}
+
+// lookup returns the live entry under key, dropping it if expired. m.mu must be held.
+func (m *Memory) lookup(key string) (memoryEntry, bool) {
+	entry, ok := m.entries[key]
+	if ok && !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		delete(m.entries, key)
+		return memoryEntry{}, false
+	}
+	return entry, ok
+}
+
+// sweep drops every expired entry once per memorySweepInterval. m.mu must be held.
+func (m *Memory) sweep() {
+	now := time.Now()
+	if now.Before(m.nextSweep) {
+		return
+	}
+
+	for key, entry := range m.entries {
+		if !entry.expiresAt.IsZero() && now.After(entry.expiresAt) {
+			delete(m.entries, key)
+		}
+	}
+	m.nextSweep = now.Add(memorySweepInterval)
+}
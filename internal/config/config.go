@@ -10,14 +10,32 @@ import (
 )
 
 type ServerConfig struct {
-	Name     string `mapstructure:"name"`
-	Port     string `mapstructure:"port"`
-	BasePath string `mapstructure:"base_path"`
-	TestMode bool   `mapstructure:"test_mode"`
-	Log      struct {
+	Name       string `mapstructure:"name"`
+	Port       string `mapstructure:"port"`
+	BasePath   string `mapstructure:"base_path"`
+	TestMode   bool   `mapstructure:"test_mode"`
+	Reflection bool   `mapstructure:"reflection"`
+	Health     struct {
+		Interval time.Duration `mapstructure:"interval"`
+		Timeout  time.Duration `mapstructure:"timeout"`
+	} `mapstructure:"health"`
+	Log struct {
 		Level  string `mapstructure:"level"`
 		Format string `mapstructure:"format"`
 	} `mapstructure:"log"`
+	GRPC struct {
+		MaxRecvMsgSize int `mapstructure:"max_recv_msg_size"` // bytes, 0 keeps the grpc default
+		MaxSendMsgSize int `mapstructure:"max_send_msg_size"` // bytes, 0 keeps the grpc default
+		Keepalive      struct {
+			Time                time.Duration `mapstructure:"time"`
+			Timeout             time.Duration `mapstructure:"timeout"`
+			MinTime             time.Duration `mapstructure:"min_time"`
+			PermitWithoutStream bool          `mapstructure:"permit_without_stream"`
+		} `mapstructure:"keepalive"`
+		MaxConnectionIdle     time.Duration `mapstructure:"max_connection_idle"`
+		MaxConnectionAge      time.Duration `mapstructure:"max_connection_age"`
+		MaxConnectionAgeGrace time.Duration `mapstructure:"max_connection_age_grace"`
+	} `mapstructure:"grpc"`
 }
 
 type DatabaseConfig struct {
@@ -68,20 +86,169 @@ type RabbitConfig struct {
 	Password string `mapstructure:"password"`
 }
 
+type CacheConfig struct {
+	Enabled   bool          `mapstructure:"enabled"`
+	Backend   string        `mapstructure:"backend"`    // "redis" or "memory"
+	ListPages uint64        `mapstructure:"list_pages"` // how many first pages of petition lists are cached
+	ListTTL   time.Duration `mapstructure:"list_ttl"`   // how long a cached list page lives; signing does not drop pages, so counts may lag by this much
+}
+
+type RateLimitConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Backend string `mapstructure:"backend"` // "redis" or "memory"
+	Methods []struct {
+		Method string        `mapstructure:"method"` // RPC name, e.g. "SignPetition"
+		Limit  int64         `mapstructure:"limit"`
+		Window time.Duration `mapstructure:"window"`
+	} `mapstructure:"methods"`
+}
+
+type ContentFilterConfig struct {
+	Enabled  bool `mapstructure:"enabled"`
+	Wordlist struct {
+		Action string            `mapstructure:"action"` // "allow", "flag", "moderate" or "reject"
+		Files  map[string]string `mapstructure:"files"`  // wordlist file per language code
+	} `mapstructure:"wordlist"`
+	LinkSpam struct {
+		Enabled         bool     `mapstructure:"enabled"`
+		Action          string   `mapstructure:"action"`
+		BlockedDomains  []string `mapstructure:"blocked_domains"`
+		Shorteners      []string `mapstructure:"shorteners"` // replaces the built-in list when set
+		MinWordsPerLink int      `mapstructure:"min_words_per_link"`
+		MaxSameDomain   int      `mapstructure:"max_same_domain"`
+	} `mapstructure:"link_spam"`
+}
+
+type IdempotencyConfig struct {
+	TTL            time.Duration `mapstructure:"ttl"`             // how long responses are replayed for a key
+	PendingTimeout time.Duration `mapstructure:"pending_timeout"` // after which an unfinished request no longer holds its key
+	PurgeInterval  time.Duration `mapstructure:"purge_interval"`  // how often expired keys are deleted
+}
+
+type GatewayConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Port    string `mapstructure:"port"`
+}
+
+type MetricsConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Port    string `mapstructure:"port"`
+	Path    string `mapstructure:"path"`
+}
+
+type TracingConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	Exporter    string  `mapstructure:"exporter"` // "stdout" or "otlp"
+	Endpoint    string  `mapstructure:"endpoint"` // OTLP gRPC collector address
+	Insecure    bool    `mapstructure:"insecure"`
+	SampleRatio float64 `mapstructure:"sample_ratio"` // 0 means sample everything
+}
+
 type SwaggerConfig struct {
 	Enabled bool   `mapstructure:"enabled"`
 	URL     string `mapstructure:"url"`
 	Port    string `mapstructure:"port"`
 }
 
+type PropertiesConfig struct {
+	Residence struct {
+		Mode     string        `mapstructure:"mode"` // "http" or "fake"
+		URL      string        `mapstructure:"url"`
+		APIKey   string        `mapstructure:"api_key"`
+		Timeout  time.Duration `mapstructure:"timeout"`
+		CacheTTL time.Duration `mapstructure:"cache_ttl"`
+	} `mapstructure:"residence"`
+}
+
+type VerificationPolicy struct {
+	Create bool `mapstructure:"create"`
+	Sign   bool `mapstructure:"sign"`
+}
+
+type PetitionsConfig struct {
+	DailyCreateLimit int    `mapstructure:"daily_create_limit"` // petitions a user may create per UTC day, 0 means unlimited
+	PseudonymKey     string `mapstructure:"pseudonym_key"`      // HMAC key of signer hashes in receipts and retention, must never change once used
+
+	Verification struct {
+		VerificationPolicy `mapstructure:",squash"`
+		Cities             map[string]VerificationPolicy `mapstructure:"cities"` // per-city overrides keyed by city ID
+	} `mapstructure:"verification"`
+
+	Fraud struct {
+		Enabled          bool          `mapstructure:"enabled"`
+		BurstWindow      time.Duration `mapstructure:"burst_window"`
+		BurstLimit       int           `mapstructure:"burst_limit"` // signatures of one petition within burst_window above which new ones are flagged
+		NewSessionWindow time.Duration `mapstructure:"new_session_window"`
+		NewSessionLimit  int           `mapstructure:"new_session_limit"` // signatures of a session within new_session_window of its first one above which it is flagged
+	} `mapstructure:"fraud"`
+
+	Duplicates struct {
+		Enabled       bool    `mapstructure:"enabled"`
+		MinSimilarity float64 `mapstructure:"min_similarity"` // trigram similarity of titles from 0 to 1
+		MaxCandidates int     `mapstructure:"max_candidates"`
+	} `mapstructure:"duplicates"`
+
+	Authors struct {
+		AuthorsPolicy `mapstructure:",squash"`
+		Cities        map[string]AuthorsPolicy `mapstructure:"cities"` // per-city overrides keyed by city ID
+	} `mapstructure:"authors"`
+
+	Comments struct {
+		EditWindow      time.Duration `mapstructure:"edit_window"`      // how long after posting a comment may be edited, 0 means forever
+		MaxDepth        int           `mapstructure:"max_depth"`        // deepest reply level, 0 allows top-level comments only
+		ReportThreshold int           `mapstructure:"report_threshold"` // open reports that hold a comment for moderation, 0 never holds
+		MaxPageSize     uint64        `mapstructure:"max_page_size"`
+	} `mapstructure:"comments"`
+
+	Retention struct {
+		Enabled         bool          `mapstructure:"enabled"`
+		DryRun          bool          `mapstructure:"dry_run"` // only report what would be pseudonymized
+		Interval        time.Duration `mapstructure:"interval"`
+		BatchSize       uint64        `mapstructure:"batch_size"` // signatures pseudonymized per transaction
+		RetentionPolicy `mapstructure:",squash"`
+		Cities          map[string]RetentionPolicy `mapstructure:"cities"` // per-city overrides keyed by city ID
+	} `mapstructure:"retention"`
+}
+
+type RetentionPolicy struct {
+	Period time.Duration `mapstructure:"period"` // how long signers of a closed petition are kept, 0 keeps them forever
+}
+
+type AuthorsPolicy struct {
+	MinCoAuthors int `mapstructure:"min_co_authors"` // accepted co-authors a draft needs before it can be published
+	MaxCoAuthors int `mapstructure:"max_co_authors"` // invited and accepted co-authors of a petition, 0 means unlimited
+}
+
+type RBACConfig struct {
+	Roles map[string]struct {
+		Permissions []string `mapstructure:"permissions"`
+		Cities      []string `mapstructure:"cities"` // empty means every city
+	} `mapstructure:"roles"`
+	Grants []struct {
+		UserID      string   `mapstructure:"user_id"`
+		CityID      string   `mapstructure:"city_id"` // empty means every city
+		Permissions []string `mapstructure:"permissions"`
+	} `mapstructure:"grants"`
+}
+
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
-	OAuth    OAuthConfig    `mapstructure:"oauth"`
-	Rabbit   RabbitConfig   `mapstructure:"rabbit"`
-	Kafka    KafkaConfig    `mapstructure:"kafka"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Swagger  SwaggerConfig  `mapstructure:"swagger"`
+	Server        ServerConfig        `mapstructure:"server"`
+	JWT           JWTConfig           `mapstructure:"jwt"`
+	OAuth         OAuthConfig         `mapstructure:"oauth"`
+	Rabbit        RabbitConfig        `mapstructure:"rabbit"`
+	Kafka         KafkaConfig         `mapstructure:"kafka"`
+	Database      DatabaseConfig      `mapstructure:"database"`
+	Cache         CacheConfig         `mapstructure:"cache"`
+	RateLimit     RateLimitConfig     `mapstructure:"rate_limit"`
+	Idempotency   IdempotencyConfig   `mapstructure:"idempotency"`
+	ContentFilter ContentFilterConfig `mapstructure:"content_filter"`
+	Swagger       SwaggerConfig       `mapstructure:"swagger"`
+	Gateway       GatewayConfig       `mapstructure:"gateway"`
+	Metrics       MetricsConfig       `mapstructure:"metrics"`
+	Tracing       TracingConfig       `mapstructure:"tracing"`
+	Properties    PropertiesConfig    `mapstructure:"properties"`
+	Petitions     PetitionsConfig     `mapstructure:"petitions"`
+	RBAC          RBACConfig          `mapstructure:"rbac"`
 }
 
 func LoadConfig() (Config, error) {
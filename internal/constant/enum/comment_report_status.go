@@ -0,0 +1,31 @@
+package enum
+
+import "fmt"
+
+const (
+	CommentReportOpen      = "open"
+	CommentReportDismissed = "dismissed"
+	CommentReportConfirmed = "confirmed"
+)
+
+var commentReportStatus = []string{
+	CommentReportOpen,
+	CommentReportDismissed,
+	CommentReportConfirmed,
+}
+
+var ErrorInvalidCommentReportStatus = fmt.Errorf("invalid comment report status must be one of: %s", GetAllCommentReportStatus())
+
+func ParseCommentReportStatus(status string) (string, error) {
+	for _, s := range commentReportStatus {
+		if s == status {
+			return s, nil
+		}
+	}
+
+	return "", fmt.Errorf("'%s', %w", status, ErrorInvalidCommentReportStatus)
+}
+
+func GetAllCommentReportStatus() []string {
+	return commentReportStatus
+}
@@ -0,0 +1,31 @@
+package enum
+
+import "fmt"
+
+const (
+	CommentPublished  = "published"
+	CommentModeration = "moderation"
+	CommentRemoved    = "removed"
+)
+
+var commentStatus = []string{
+	CommentPublished,
+	CommentModeration,
+	CommentRemoved,
+}
+
+var ErrorInvalidCommentStatus = fmt.Errorf("invalid comment status must be one of: %s", GetAllCommentStatus())
+
+func ParseCommentStatus(status string) (string, error) {
+	for _, s := range commentStatus {
+		if s == status {
+			return s, nil
+		}
+	}
+
+	return "", fmt.Errorf("'%s', %w", status, ErrorInvalidCommentStatus)
+}
+
+func GetAllCommentStatus() []string {
+	return commentStatus
+}
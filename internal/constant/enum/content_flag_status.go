@@ -0,0 +1,31 @@
+package enum
+
+import "fmt"
+
+const (
+	ContentFlagOpen      = "open"
+	ContentFlagDismissed = "dismissed"
+	ContentFlagConfirmed = "confirmed"
+)
+
+var contentFlagStatus = []string{
+	ContentFlagOpen,
+	ContentFlagDismissed,
+	ContentFlagConfirmed,
+}
+
+var ErrorInvalidContentFlagStatus = fmt.Errorf("invalid content flag status must be one of: %s", GetAllContentFlagStatus())
+
+func ParseContentFlagStatus(status string) (string, error) {
+	for _, s := range contentFlagStatus {
+		if s == status {
+			return s, nil
+		}
+	}
+
+	return "", fmt.Errorf("'%s', %w", status, ErrorInvalidContentFlagStatus)
+}
+
+func GetAllContentFlagStatus() []string {
+	return contentFlagStatus
+}
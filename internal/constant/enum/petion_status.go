@@ -6,12 +6,21 @@ const (
 	PetitionPublished = "published"
 	PetitionApproved  = "approved"
 	PetitionRejected  = "rejected"
+	// PetitionModeration is held by the content filter until a moderator reviews it.
+	PetitionModeration = "moderation"
+	// PetitionMerged was folded into another petition, see Petition.MergedInto.
+	PetitionMerged = "merged"
+	// PetitionDraft is prepared by its authors and not published yet.
+	PetitionDraft = "draft"
 )
 
 var petitionStatus = []string{
 	PetitionPublished,
 	PetitionApproved,
 	PetitionRejected,
+	PetitionModeration,
+	PetitionMerged,
+	PetitionDraft,
 }
 
 var ErrorInvalidPetitionStatus = fmt.Errorf("invalid petition status mus be one of: %s", GetAllPetitionStatus())
@@ -0,0 +1,31 @@
+package enum
+
+import "fmt"
+
+const (
+	PetitionAuthorInvited  = "invited"
+	PetitionAuthorAccepted = "accepted"
+	PetitionAuthorDeclined = "declined"
+)
+
+var petitionAuthorStatus = []string{
+	PetitionAuthorInvited,
+	PetitionAuthorAccepted,
+	PetitionAuthorDeclined,
+}
+
+var ErrorInvalidPetitionAuthorStatus = fmt.Errorf("invalid petition author status must be one of: %s", GetAllPetitionAuthorStatus())
+
+func ParsePetitionAuthorStatus(status string) (string, error) {
+	for _, s := range petitionAuthorStatus {
+		if s == status {
+			return s, nil
+		}
+	}
+
+	return "", fmt.Errorf("'%s', %w", status, ErrorInvalidPetitionAuthorStatus)
+}
+
+func GetAllPetitionAuthorStatus() []string {
+	return petitionAuthorStatus
+}
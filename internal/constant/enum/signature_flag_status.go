@@ -0,0 +1,31 @@
+package enum
+
+import "fmt"
+
+const (
+	SignatureFlagOpen      = "open"
+	SignatureFlagDismissed = "dismissed"
+	SignatureFlagConfirmed = "confirmed"
+)
+
+var signatureFlagStatus = []string{
+	SignatureFlagOpen,
+	SignatureFlagDismissed,
+	SignatureFlagConfirmed,
+}
+
+var ErrorInvalidSignatureFlagStatus = fmt.Errorf("invalid signature flag status must be one of: %s", GetAllSignatureFlagStatus())
+
+func ParseSignatureFlagStatus(status string) (string, error) {
+	for _, s := range signatureFlagStatus {
+		if s == status {
+			return s, nil
+		}
+	}
+
+	return "", fmt.Errorf("'%s', %w", status, ErrorInvalidSignatureFlagStatus)
+}
+
+func GetAllSignatureFlagStatus() []string {
+	return signatureFlagStatus
+}
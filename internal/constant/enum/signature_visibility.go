@@ -0,0 +1,31 @@
+package enum
+
+import "fmt"
+
+const (
+	SignaturePublic    = "public"
+	SignatureAnonymous = "anonymous"
+	SignaturePrivate   = "private"
+)
+
+var signatureVisibility = []string{
+	SignaturePublic,
+	SignatureAnonymous,
+	SignaturePrivate,
+}
+
+var ErrorInvalidSignatureVisibility = fmt.Errorf("invalid signature visibility must be one of: %s", GetAllSignatureVisibility())
+
+func ParseSignatureVisibility(visibility string) (string, error) {
+	for _, v := range signatureVisibility {
+		if v == visibility {
+			return v, nil
+		}
+	}
+
+	return "", fmt.Errorf("'%s', %w", visibility, ErrorInvalidSignatureVisibility)
+}
+
+func GetAllSignatureVisibility() []string {
+	return signatureVisibility
+}
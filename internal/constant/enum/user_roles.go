@@ -0,0 +1,33 @@
+package enum
+
+import "fmt"
+
+const (
+	UserRoleUser      = "user"
+	UserRoleModerator = "moderator"
+	UserRoleAdmin     = "admin"
+	UserRoleSuperUser = "super_user"
+)
+
+var userRoles = []string{
+	UserRoleUser,
+	UserRoleModerator,
+	UserRoleAdmin,
+	UserRoleSuperUser,
+}
+
+var ErrorInvalidUserRole = fmt.Errorf("invalid user role must be one of: %s", GetAllUserRoles())
+
+func ParseUserRole(role string) (string, error) {
+	for _, r := range userRoles {
+		if r == role {
+			return r, nil
+		}
+	}
+
+	return "", fmt.Errorf("'%s', %w", role, ErrorInvalidUserRole)
+}
+
+func GetAllUserRoles() []string {
+	return userRoles
+}
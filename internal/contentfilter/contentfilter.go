@@ -0,0 +1,150 @@
+package contentfilter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chains-lab/city-petitions-svc/internal/config"
+)
+
+// Actions taken on content matched by a filter, from the mildest to the strictest.
+const (
+	ActionAllow    = "allow"    // the match is ignored
+	ActionFlag     = "flag"     // the content is accepted and flagged for moderators
+	ActionModerate = "moderate" // the content is held until a moderator reviews it
+	ActionReject   = "reject"   // the content is refused
+)
+
+var severity = map[string]int{
+	ActionAllow:    0,
+	ActionFlag:     1,
+	ActionModerate: 2,
+	ActionReject:   3,
+}
+
+func ParseAction(action string) (string, error) {
+	if _, ok := severity[action]; !ok {
+		return "", fmt.Errorf("unknown content filter action '%s'", action)
+	}
+
+	return action, nil
+}
+
+// Field is a named piece of user supplied text, e.g. a petition title.
+type Field struct {
+	Name string
+	Text string
+}
+
+// Finding is something objectionable a filter found in a field.
+type Finding struct {
+	Field   string
+	Details string
+}
+
+// Filter inspects content. Implementations must be safe for concurrent use.
+type Filter interface {
+	Name() string
+	Check(ctx context.Context, fields []Field) ([]Finding, error)
+}
+
+// Match is a finding together with the filter that reported it and the configured action.
+type Match struct {
+	Finding
+	Filter string
+	Action string
+}
+
+// Verdict is the outcome of running the pipeline over some content.
+type Verdict struct {
+	Action  string // the strictest action of all matches, ActionAllow when nothing matched
+	Matches []Match
+}
+
+func (v Verdict) Rejected() bool {
+	return v.Action == ActionReject
+}
+
+func (v Verdict) NeedsModeration() bool {
+	return v.Action == ActionModerate
+}
+
+type stage struct {
+	filter Filter
+	action string
+}
+
+// Pipeline runs filters in order. The zero value has no filters and allows everything.
+type Pipeline struct {
+	stages []stage
+}
+
+// Use appends filter to the pipeline; its findings get action.
+func (p *Pipeline) Use(filter Filter, action string) {
+	p.stages = append(p.stages, stage{filter: filter, action: action})
+}
+
+// New builds the pipeline of built-in filters from cfg.ContentFilter.
+func New(cfg config.Config) (*Pipeline, error) {
+	p := &Pipeline{}
+
+	fc := cfg.ContentFilter
+	if !fc.Enabled {
+		return p, nil
+	}
+
+	if len(fc.Wordlist.Files) > 0 {
+		action, err := ParseAction(fc.Wordlist.Action)
+		if err != nil {
+			return nil, fmt.Errorf("wordlist filter: %w", err)
+		}
+
+		wordlist, err := LoadWordlist(fc.Wordlist.Files)
+		if err != nil {
+			return nil, err
+		}
+
+		p.Use(wordlist, action)
+	}
+
+	if fc.LinkSpam.Enabled {
+		action, err := ParseAction(fc.LinkSpam.Action)
+		if err != nil {
+			return nil, fmt.Errorf("link spam filter: %w", err)
+		}
+
+		p.Use(NewLinkSpam(LinkSpamRules{
+			BlockedDomains:  fc.LinkSpam.BlockedDomains,
+			Shorteners:      fc.LinkSpam.Shorteners,
+			MinWordsPerLink: fc.LinkSpam.MinWordsPerLink,
+			MaxSameDomain:   fc.LinkSpam.MaxSameDomain,
+		}), action)
+	}
+
+	return p, nil
+}
+
+// Check runs every filter over fields. Filters with ActionAllow still run, so their matches can be logged.
+func (p *Pipeline) Check(ctx context.Context, fields ...Field) (Verdict, error) {
+	verdict := Verdict{Action: ActionAllow}
+
+	for _, s := range p.stages {
+		findings, err := s.filter.Check(ctx, fields)
+		if err != nil {
+			return Verdict{}, fmt.Errorf("content filter %s: %w", s.filter.Name(), err)
+		}
+
+		for _, f := range findings {
+			verdict.Matches = append(verdict.Matches, Match{
+				Finding: f,
+				Filter:  s.filter.Name(),
+				Action:  s.action,
+			})
+			if severity[s.action] > severity[verdict.Action] {
+				verdict.Action = s.action
+			}
+		}
+	}
+
+	return verdict, nil
+}
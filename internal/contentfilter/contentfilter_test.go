@@ -0,0 +1,134 @@
+package contentfilter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/chains-lab/city-petitions-svc/internal/config"
+)
+
+// fixedFilter reports one finding per field containing match.
+type fixedFilter struct {
+	name  string
+	match string
+	err   error
+}
+
+func (f fixedFilter) Name() string { return f.name }
+
+func (f fixedFilter) Check(_ context.Context, fields []Field) ([]Finding, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	var findings []Finding
+	for _, field := range fields {
+		if field.Text == f.match {
+			findings = append(findings, Finding{Field: field.Name, Details: f.name})
+		}
+	}
+
+	return findings, nil
+}
+
+func TestVerdictPrecedence(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions []string // one filter per action, all matching
+		want    string
+	}{
+		{"no filters", nil, ActionAllow},
+		{"allow", []string{ActionAllow}, ActionAllow},
+		{"flag over allow", []string{ActionAllow, ActionFlag}, ActionFlag},
+		{"moderate over flag", []string{ActionFlag, ActionModerate}, ActionModerate},
+		{"reject over moderate", []string{ActionModerate, ActionReject}, ActionReject},
+		{"order does not matter", []string{ActionReject, ActionFlag, ActionAllow}, ActionReject},
+		{"all", []string{ActionAllow, ActionFlag, ActionModerate, ActionReject}, ActionReject},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Pipeline{}
+			for i, action := range tt.actions {
+				p.Use(fixedFilter{name: string(rune('a' + i)), match: "bad"}, action)
+			}
+
+			verdict, err := p.Check(context.Background(), Field{Name: "title", Text: "bad"})
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if verdict.Action != tt.want {
+				t.Errorf("action = %s, want %s", verdict.Action, tt.want)
+			}
+			// allowed matches are still reported so they can be logged
+			if len(verdict.Matches) != len(tt.actions) {
+				t.Errorf("got %d matches, want %d", len(verdict.Matches), len(tt.actions))
+			}
+			if verdict.Rejected() != (tt.want == ActionReject) || verdict.NeedsModeration() != (tt.want == ActionModerate) {
+				t.Errorf("Rejected = %t, NeedsModeration = %t for action %s", verdict.Rejected(), verdict.NeedsModeration(), verdict.Action)
+			}
+		})
+	}
+}
+
+func TestPipelineIgnoresFiltersWithoutFindings(t *testing.T) {
+	p := &Pipeline{}
+	p.Use(fixedFilter{name: "strict", match: "other"}, ActionReject)
+	p.Use(fixedFilter{name: "mild", match: "bad"}, ActionFlag)
+
+	verdict, err := p.Check(context.Background(), Field{Name: "title", Text: "bad"})
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if verdict.Action != ActionFlag || len(verdict.Matches) != 1 || verdict.Matches[0].Filter != "mild" {
+		t.Errorf("verdict = %+v, want one flag match of mild", verdict)
+	}
+}
+
+func TestPipelineFilterError(t *testing.T) {
+	p := &Pipeline{}
+	p.Use(fixedFilter{name: "broken", err: errors.New("boom")}, ActionFlag)
+
+	if _, err := p.Check(context.Background(), Field{Name: "title", Text: "bad"}); err == nil {
+		t.Error("Check succeeded, want an error")
+	}
+}
+
+func TestParseAction(t *testing.T) {
+	for _, action := range []string{ActionAllow, ActionFlag, ActionModerate, ActionReject} {
+		if got, err := ParseAction(action); err != nil || got != action {
+			t.Errorf("ParseAction(%s) = %s, %v", action, got, err)
+		}
+	}
+
+	if _, err := ParseAction("block"); err == nil {
+		t.Error("ParseAction accepted an unknown action")
+	}
+}
+
+func TestNew(t *testing.T) {
+	var disabled config.Config
+	disabled.ContentFilter.LinkSpam.Enabled = true
+	disabled.ContentFilter.LinkSpam.Action = ActionReject
+
+	p, err := New(disabled)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if len(p.stages) != 0 {
+		t.Errorf("disabled filter has %d stages, want none", len(p.stages))
+	}
+
+	enabled := disabled
+	enabled.ContentFilter.Enabled = true
+	if p, err = New(enabled); err != nil || len(p.stages) != 1 {
+		t.Errorf("New = %d stages, %v, want one link spam stage", len(p.stages), err)
+	}
+
+	invalid := enabled
+	invalid.ContentFilter.LinkSpam.Action = "block"
+	if _, err := New(invalid); err == nil {
+		t.Error("New accepted an unknown action")
+	}
+}
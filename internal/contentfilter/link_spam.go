@@ -0,0 +1,138 @@
+package contentfilter
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"']+`)
+
+// defaultShorteners hide the real target of a link and are common in spam.
+var defaultShorteners = []string{
+	"bit.ly",
+	"cutt.ly",
+	"goo.gl",
+	"is.gd",
+	"ow.ly",
+	"rebrand.ly",
+	"shorturl.at",
+	"t.co",
+	"tinyurl.com",
+}
+
+type LinkSpamRules struct {
+	BlockedDomains  []string // a link to one of these or their subdomains always matches
+	Shorteners      []string // replaces defaultShorteners when set
+	MinWordsPerLink int      // text with fewer words per link is considered link spam; 0 disables the check
+	MaxSameDomain   int      // more links to a single domain are considered link spam; 0 disables the check
+}
+
+// LinkSpam recognises text that mostly advertises links.
+type LinkSpam struct {
+	blocked    []string
+	shorteners []string
+	rules      LinkSpamRules
+}
+
+func NewLinkSpam(rules LinkSpamRules) *LinkSpam {
+	shorteners := rules.Shorteners
+	if len(shorteners) == 0 {
+		shorteners = defaultShorteners
+	}
+
+	return &LinkSpam{
+		blocked:    normalizeDomains(rules.BlockedDomains),
+		shorteners: normalizeDomains(shorteners),
+		rules:      rules,
+	}
+}
+
+func (l *LinkSpam) Name() string {
+	return "link_spam"
+}
+
+func (l *LinkSpam) Check(_ context.Context, fields []Field) ([]Finding, error) {
+	var findings []Finding
+
+	for _, field := range fields {
+		links := linkPattern.FindAllString(field.Text, -1)
+		if len(links) == 0 {
+			continue
+		}
+
+		perDomain := map[string]int{}
+		reported := map[string]struct{}{}
+		report := func(details string) {
+			if _, ok := reported[details]; ok {
+				return
+			}
+			reported[details] = struct{}{}
+			findings = append(findings, Finding{Field: field.Name, Details: details})
+		}
+
+		for _, link := range links {
+			host := linkHost(link)
+			if host == "" {
+				continue
+			}
+			perDomain[host]++
+
+			if matchesDomain(host, l.blocked) {
+				report(fmt.Sprintf("links to blocked domain %s", host))
+			}
+			if matchesDomain(host, l.shorteners) {
+				report(fmt.Sprintf("uses link shortener %s", host))
+			}
+			if l.rules.MaxSameDomain > 0 && perDomain[host] > l.rules.MaxSameDomain {
+				report(fmt.Sprintf("links to %s more than %d times", host, l.rules.MaxSameDomain))
+			}
+		}
+
+		if l.rules.MinWordsPerLink > 0 {
+			words := len(strings.Fields(linkPattern.ReplaceAllString(field.Text, " ")))
+			if words < l.rules.MinWordsPerLink*len(links) {
+				report(fmt.Sprintf("has %d links for %d words of text", len(links), words))
+			}
+		}
+	}
+
+	return findings, nil
+}
+
+func linkHost(link string) string {
+	if !strings.Contains(link, "://") {
+		link = "http://" + link
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+func matchesDomain(host string, domains []string) bool {
+	for _, d := range domains {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func normalizeDomains(domains []string) []string {
+	res := make([]string, 0, len(domains))
+	for _, d := range domains {
+		d = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(d)), "www.")
+		if d != "" {
+			res = append(res, d)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,55 @@
+package contentfilter
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestLinkSpam(t *testing.T) {
+	rules := LinkSpamRules{
+		BlockedDomains:  []string{"Casino.example", "www.pills.example"},
+		MinWordsPerLink: 3,
+		MaxSameDomain:   2,
+	}
+
+	tests := []struct {
+		name  string
+		rules LinkSpamRules
+		text  string
+		want  []string
+	}{
+		{"no links", rules, "repair the playground", nil},
+		{"plain link", rules, "see the plan at https://city.example/plan for details", nil},
+		{"blocked domain", rules, "win big at https://casino.example today, really", []string{"links to blocked domain casino.example"}},
+		{"blocked subdomain", rules, "win big at http://eu.casino.example today, really", []string{"links to blocked domain eu.casino.example"}},
+		{"blocked domain without scheme", rules, "cheap stuff at www.pills.example right now", []string{"links to blocked domain pills.example"}},
+		{"lookalike domain", rules, "read https://notcasino.example before voting please", nil},
+		{"default shortener", rules, "the full report https://bit.ly/abc is here", []string{"uses link shortener bit.ly"}},
+		{"custom shorteners replace defaults", LinkSpamRules{Shorteners: []string{"sho.rt"}}, "https://bit.ly/a and https://sho.rt/b", []string{"uses link shortener sho.rt"}},
+		{"too few words", rules, "look https://a.example/1", []string{"has 1 links for 1 words of text"}},
+		{"word check disabled", LinkSpamRules{}, "https://a.example/1", nil},
+		{
+			"same domain too often", LinkSpamRules{MaxSameDomain: 2},
+			"first https://a.example/1 second https://a.example/2 third https://a.example/3 and https://a.example/4",
+			[]string{"links to a.example more than 2 times"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := NewLinkSpam(tt.rules).Check(context.Background(), []Field{{Name: "description", Text: tt.text}})
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+
+			var got []string
+			for _, f := range findings {
+				got = append(got, f.Details)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("findings = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package contentfilter
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Wordlist matches words and phrases from per-language lists.
+// Petitions carry no language, so every list is checked; the finding names the list that matched.
+type Wordlist struct {
+	terms     map[string]string // normalized term -> language
+	maxTokens int               // length of the longest phrase in words
+}
+
+// LoadWordlist reads one list per language from files keyed by language code.
+// Each line holds a word or a phrase; empty lines and lines starting with '#' are skipped.
+func LoadWordlist(files map[string]string) (*Wordlist, error) {
+	w := &Wordlist{terms: map[string]string{}}
+
+	languages := make([]string, 0, len(files))
+	for lang := range files {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+
+	for _, lang := range languages {
+		if err := w.load(lang, files[lang]); err != nil {
+			return nil, err
+		}
+	}
+
+	return w, nil
+}
+
+func (w *Wordlist) load(lang, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening %s wordlist: %w", lang, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		tokens := tokenize(line)
+		if len(tokens) == 0 {
+			continue
+		}
+
+		term := strings.Join(tokens, " ")
+		if _, ok := w.terms[term]; !ok {
+			w.terms[term] = lang
+		}
+		w.maxTokens = max(w.maxTokens, len(tokens))
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading %s wordlist: %w", lang, err)
+	}
+
+	return nil
+}
+
+func (w *Wordlist) Name() string {
+	return "wordlist"
+}
+
+// Check reports each listed term once per field.
+func (w *Wordlist) Check(_ context.Context, fields []Field) ([]Finding, error) {
+	var findings []Finding
+
+	for _, field := range fields {
+		tokens := tokenize(field.Text)
+		seen := map[string]struct{}{}
+
+		for i := range tokens {
+			for n := 1; n <= w.maxTokens && i+n <= len(tokens); n++ {
+				term := strings.Join(tokens[i:i+n], " ")
+
+				lang, ok := w.terms[term]
+				if !ok {
+					continue
+				}
+				if _, ok := seen[term]; ok {
+					continue
+				}
+				seen[term] = struct{}{}
+
+				findings = append(findings, Finding{
+					Field:   field.Name,
+					Details: fmt.Sprintf("contains '%s' from the %s wordlist", term, lang),
+				})
+			}
+		}
+	}
+
+	return findings, nil
+}
+
+// leetSymbols are kept inside words so that tokenize can fold them.
+const leetSymbols = "@$"
+
+// tokenize lowercases s and splits it into words. Digits and symbols inside words that
+// also contain letters are folded into the letters they imitate, so "sp4m" or "$cam" match
+// "spam" and "scam" while plain numbers stay as they are.
+func tokenize(s string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && !strings.ContainsRune(leetSymbols, r)
+	})
+
+	tokens := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if strings.IndexFunc(f, unicode.IsLetter) >= 0 {
+			f = strings.Map(unleet, f)
+		}
+
+		f = strings.Trim(f, "'"+leetSymbols)
+		if f != "" {
+			tokens = append(tokens, f)
+		}
+	}
+
+	return tokens
+}
+
+func unleet(r rune) rune {
+	switch r {
+	case '0':
+		return 'o'
+	case '1':
+		return 'i'
+	case '3':
+		return 'e'
+	case '4', '@':
+		return 'a'
+	case '5', '$':
+		return 's'
+	case '7':
+		return 't'
+	}
+
+	return r
+}
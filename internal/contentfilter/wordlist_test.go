@@ -0,0 +1,98 @@
+package contentfilter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func writeWordlist(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("writing wordlist: %v", err)
+	}
+
+	return path
+}
+
+func TestWordlist(t *testing.T) {
+	w, err := LoadWordlist(map[string]string{
+		"en": writeWordlist(t, "en.txt", "# english\n\nspam\nscam\nbuy now\n"),
+		"uk": writeWordlist(t, "uk.txt", "шахрай\nspam\n"),
+	})
+	if err != nil {
+		t.Fatalf("LoadWordlist: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"clean text", "fix the road on main street", nil},
+		{"word", "this is spam", []string{"contains 'spam' from the en wordlist"}},
+		{"case is ignored", "SPAM", []string{"contains 'spam' from the en wordlist"}},
+		{"leet is folded", "sp4m and $cam", []string{"contains 'spam' from the en wordlist", "contains 'scam' from the en wordlist"}},
+		{"numbers are not folded", "road 5 is 10 km", nil},
+		{"phrase", "please Buy, now!", []string{"contains 'buy now' from the en wordlist"}},
+		{"part of a phrase", "buy bread", nil},
+		{"part of a word", "spammer", nil},
+		{"reported once per field", "spam spam spam", []string{"contains 'spam' from the en wordlist"}},
+		{"other language", "він шахрай", []string{"contains 'шахрай' from the uk wordlist"}},
+		{"comments are not terms", "english", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := w.Check(context.Background(), []Field{{Name: "title", Text: tt.text}})
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+
+			var got []string
+			for _, f := range findings {
+				if f.Field != "title" {
+					t.Errorf("finding for field %s, want title", f.Field)
+				}
+				got = append(got, f.Details)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("findings = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWordlistChecksEveryField(t *testing.T) {
+	w, err := LoadWordlist(map[string]string{"en": writeWordlist(t, "en.txt", "spam\n")})
+	if err != nil {
+		t.Fatalf("LoadWordlist: %v", err)
+	}
+
+	findings, err := w.Check(context.Background(), []Field{
+		{Name: "title", Text: "spam"},
+		{Name: "description", Text: "nothing"},
+		{Name: "reply", Text: "more spam"},
+	})
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	var fields []string
+	for _, f := range findings {
+		fields = append(fields, f.Field)
+	}
+	if want := []string{"title", "reply"}; !slices.Equal(fields, want) {
+		t.Errorf("fields = %v, want %v", fields, want)
+	}
+}
+
+func TestLoadWordlistMissingFile(t *testing.T) {
+	if _, err := LoadWordlist(map[string]string{"en": filepath.Join(t.TempDir(), "missing.txt")}); err == nil {
+		t.Error("LoadWordlist succeeded, want an error")
+	}
+}
@@ -0,0 +1,53 @@
+package dbx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/chains-lab/city-petitions-svc/internal/metrics"
+)
+
+// anonymousID replaces a user ID with a fresh random one per row, so the rows stay in place
+// (and keep counting) but can no longer be linked to the user or to each other.
+var anonymousID = sq.Expr("uuid_generate_v4()")
+
+// execUpdates runs the update queries of table in order and returns the number of rows they affected.
+func execUpdates(ctx context.Context, db *sql.DB, table, op string, queries ...sq.UpdateBuilder) (int64, error) {
+	defer metrics.ObserveDBQuery(table, op, time.Now())
+
+	var total int64
+	for _, b := range queries {
+		n, err := execUpdate(ctx, db, table, op, b)
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+
+	return total, nil
+}
+
+func execUpdate(ctx context.Context, db *sql.DB, table, op string, b sq.UpdateBuilder) (int64, error) {
+	query, args, err := b.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("building %s query for table: %s: %w", op, table, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, table, op, query)
+	defer func() { endQuerySpan(span, err) }()
+
+	var res sql.Result
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		res, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		res, err = db.ExecContext(ctx, query, args...)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
@@ -0,0 +1,253 @@
+package dbx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/chains-lab/city-petitions-svc/internal/metrics"
+	"github.com/google/uuid"
+)
+
+const commentReportsTable = "comment_reports"
+
+type CommentReport struct {
+	ID         uuid.UUID     `db:"id"`
+	CommentID  uuid.UUID     `db:"comment_id"`
+	ReporterID uuid.UUID     `db:"reporter_id"`
+	Reason     string        `db:"reason"`
+	Status     string        `db:"status"`
+	ReviewedBy uuid.NullUUID `db:"reviewed_by"`
+	ReviewedAt sql.NullTime  `db:"reviewed_at"`
+	CreatedAt  time.Time     `db:"created_at"`
+}
+
+type CommentReportsQ struct {
+	db       *sql.DB
+	selector sq.SelectBuilder
+	inserter sq.InsertBuilder
+	updater  sq.UpdateBuilder
+	counter  sq.SelectBuilder
+}
+
+func NewCommentReportsQ(db *sql.DB) CommentReportsQ {
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	selectCols := []string{
+		"id",
+		"comment_id",
+		"reporter_id",
+		"reason",
+		"status",
+		"reviewed_by",
+		"reviewed_at",
+		"created_at",
+	}
+
+	return CommentReportsQ{
+		db:       db,
+		selector: builder.Select(selectCols...).From(commentReportsTable),
+		inserter: builder.Insert(commentReportsTable),
+		updater:  builder.Update(commentReportsTable),
+		counter:  builder.Select("COUNT(*) AS count").From(commentReportsTable),
+	}
+}
+
+func (q CommentReportsQ) New() CommentReportsQ {
+	return NewCommentReportsQ(q.db)
+}
+
+// Insert stores a report and tells whether it was new; a user reports a comment only once.
+func (q CommentReportsQ) Insert(ctx context.Context, input CommentReport) (bool, error) {
+	defer metrics.ObserveDBQuery(commentReportsTable, "insert", time.Now())
+
+	values := map[string]interface{}{
+		"id":          input.ID,
+		"comment_id":  input.CommentID,
+		"reporter_id": input.ReporterID,
+		"reason":      input.Reason,
+		"status":      input.Status,
+		"created_at":  input.CreatedAt,
+	}
+
+	query, args, err := q.inserter.SetMap(values).Suffix("ON CONFLICT (comment_id, reporter_id) DO NOTHING").ToSql()
+	if err != nil {
+		return false, fmt.Errorf("building inserter query for table: %s: %w", commentReportsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, commentReportsTable, "insert", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	var res sql.Result
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		res, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		res, err = q.db.ExecContext(ctx, query, args...)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	inserted, err := res.RowsAffected()
+
+	return inserted > 0, err
+}
+
+func (q CommentReportsQ) Select(ctx context.Context) ([]CommentReport, error) {
+	defer metrics.ObserveDBQuery(commentReportsTable, "select", time.Now())
+
+	query, args, err := q.selector.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building selector query for table: %s: %w", commentReportsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, commentReportsTable, "select", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	var rows *sql.Rows
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		rows, err = tx.QueryContext(ctx, query, args...)
+	} else {
+		rows, err = q.db.QueryContext(ctx, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []CommentReport
+	for rows.Next() {
+		var r CommentReport
+		if err = rows.Scan(
+			&r.ID,
+			&r.CommentID,
+			&r.ReporterID,
+			&r.Reason,
+			&r.Status,
+			&r.ReviewedBy,
+			&r.ReviewedAt,
+			&r.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+
+	return out, nil
+}
+
+type UpdateCommentReportInput struct {
+	Status     *string
+	ReviewedBy *uuid.UUID
+	ReviewedAt *time.Time
+}
+
+func (q CommentReportsQ) Update(ctx context.Context, in UpdateCommentReportInput) error {
+	defer metrics.ObserveDBQuery(commentReportsTable, "update", time.Now())
+
+	updates := map[string]interface{}{}
+
+	if in.Status != nil {
+		updates["status"] = *in.Status
+	}
+	if in.ReviewedBy != nil {
+		updates["reviewed_by"] = *in.ReviewedBy
+	}
+	if in.ReviewedAt != nil {
+		updates["reviewed_at"] = *in.ReviewedAt
+	}
+
+	if len(updates) == 0 {
+		return nil
+	}
+
+	query, args, err := q.updater.SetMap(updates).ToSql()
+	if err != nil {
+		return fmt.Errorf("building updater query for table: %s: %w", commentReportsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, commentReportsTable, "update", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = q.db.ExecContext(ctx, query, args...)
+	}
+
+	return err
+}
+
+func (q CommentReportsQ) Count(ctx context.Context) (uint64, error) {
+	defer metrics.ObserveDBQuery(commentReportsTable, "count", time.Now())
+
+	query, args, err := q.counter.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("building count query for table: %s: %w", commentReportsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, commentReportsTable, "count", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	var count uint64
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		err = tx.QueryRowContext(ctx, query, args...).Scan(&count)
+	} else {
+		err = q.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	}
+
+	return count, err
+}
+
+// AnonymizeUser detaches the reports filed by userID from them, dropping their reason, and
+// forgets which reports they reviewed.
+func (q CommentReportsQ) AnonymizeUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return execUpdates(ctx, q.db, commentReportsTable, "anonymize",
+		q.updater.Set("reporter_id", anonymousID).Set("reason", "").Where(sq.Eq{"reporter_id": userID}),
+		q.updater.Set("reviewed_by", nil).Where(sq.Eq{"reviewed_by": userID}),
+	)
+}
+
+func (q CommentReportsQ) FilterCommentID(commentID uuid.UUID) CommentReportsQ {
+	q.selector = q.selector.Where(sq.Eq{"comment_id": commentID})
+	q.counter = q.counter.Where(sq.Eq{"comment_id": commentID})
+	q.updater = q.updater.Where(sq.Eq{"comment_id": commentID})
+
+	return q
+}
+
+func (q CommentReportsQ) FilterReporterID(userID uuid.UUID) CommentReportsQ {
+	q.selector = q.selector.Where(sq.Eq{"reporter_id": userID})
+	q.counter = q.counter.Where(sq.Eq{"reporter_id": userID})
+	q.updater = q.updater.Where(sq.Eq{"reporter_id": userID})
+
+	return q
+}
+
+func (q CommentReportsQ) FilterReviewedBy(userID uuid.UUID) CommentReportsQ {
+	q.selector = q.selector.Where(sq.Eq{"reviewed_by": userID})
+	q.counter = q.counter.Where(sq.Eq{"reviewed_by": userID})
+	q.updater = q.updater.Where(sq.Eq{"reviewed_by": userID})
+
+	return q
+}
+
+func (q CommentReportsQ) FilterStatus(status string) CommentReportsQ {
+	q.selector = q.selector.Where(sq.Eq{"status": status})
+	q.counter = q.counter.Where(sq.Eq{"status": status})
+	q.updater = q.updater.Where(sq.Eq{"status": status})
+
+	return q
+}
+
+func (q CommentReportsQ) OrderByCreated(ascending bool) CommentReportsQ {
+	if ascending {
+		q.selector = q.selector.OrderBy("created_at ASC")
+	} else {
+		q.selector = q.selector.OrderBy("created_at DESC")
+	}
+
+	return q
+}
@@ -0,0 +1,284 @@
+package dbx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/chains-lab/city-petitions-svc/internal/metrics"
+	"github.com/google/uuid"
+)
+
+const contentFlagsTable = "content_flags"
+
+type ContentFlag struct {
+	ID         uuid.UUID     `db:"id"`
+	PetitionID uuid.UUID     `db:"petition_id"`
+	Field      string        `db:"field"`
+	Filter     string        `db:"filter"`
+	Action     string        `db:"action"`
+	Details    string        `db:"details"`
+	Status     string        `db:"status"`
+	ReviewedBy uuid.NullUUID `db:"reviewed_by"`
+	ReviewedAt sql.NullTime  `db:"reviewed_at"`
+	CreatedAt  time.Time     `db:"created_at"`
+}
+
+type ContentFlagsQ struct {
+	db       *sql.DB
+	selector sq.SelectBuilder
+	inserter sq.InsertBuilder
+	updater  sq.UpdateBuilder
+	deleter  sq.DeleteBuilder
+	counter  sq.SelectBuilder
+}
+
+func NewContentFlagsQ(db *sql.DB) ContentFlagsQ {
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	selectCols := []string{
+		"id",
+		"petition_id",
+		"field",
+		"filter",
+		"action",
+		"details",
+		"status",
+		"reviewed_by",
+		"reviewed_at",
+		"created_at",
+	}
+
+	return ContentFlagsQ{
+		db:       db,
+		selector: builder.Select(selectCols...).From(contentFlagsTable),
+		inserter: builder.Insert(contentFlagsTable),
+		updater:  builder.Update(contentFlagsTable),
+		deleter:  builder.Delete(contentFlagsTable),
+		counter:  builder.Select("COUNT(*) AS count").From(contentFlagsTable),
+	}
+}
+
+func (q ContentFlagsQ) New() ContentFlagsQ {
+	return NewContentFlagsQ(q.db)
+}
+
+// Insert stores flags.
+func (q ContentFlagsQ) Insert(ctx context.Context, flags ...ContentFlag) error {
+	defer metrics.ObserveDBQuery(contentFlagsTable, "insert", time.Now())
+
+	if len(flags) == 0 {
+		return nil
+	}
+
+	inserter := q.inserter.Columns("id", "petition_id", "field", "filter", "action", "details", "status", "created_at")
+	for _, f := range flags {
+		inserter = inserter.Values(f.ID, f.PetitionID, f.Field, f.Filter, f.Action, f.Details, f.Status, f.CreatedAt)
+	}
+
+	query, args, err := inserter.ToSql()
+	if err != nil {
+		return fmt.Errorf("building inserter query for table: %s: %w", contentFlagsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, contentFlagsTable, "insert", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = q.db.ExecContext(ctx, query, args...)
+	}
+
+	return err
+}
+
+func (q ContentFlagsQ) Select(ctx context.Context) ([]ContentFlag, error) {
+	defer metrics.ObserveDBQuery(contentFlagsTable, "select", time.Now())
+
+	query, args, err := q.selector.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building selector query for table: %s: %w", contentFlagsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, contentFlagsTable, "select", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	var rows *sql.Rows
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		rows, err = tx.QueryContext(ctx, query, args...)
+	} else {
+		rows, err = q.db.QueryContext(ctx, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []ContentFlag
+	for rows.Next() {
+		var f ContentFlag
+		if err = rows.Scan(
+			&f.ID,
+			&f.PetitionID,
+			&f.Field,
+			&f.Filter,
+			&f.Action,
+			&f.Details,
+			&f.Status,
+			&f.ReviewedBy,
+			&f.ReviewedAt,
+			&f.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+
+	return out, nil
+}
+
+type UpdateContentFlagInput struct {
+	Status     *string
+	ReviewedBy *uuid.UUID
+	ReviewedAt *time.Time
+}
+
+func (q ContentFlagsQ) Update(ctx context.Context, in UpdateContentFlagInput) error {
+	defer metrics.ObserveDBQuery(contentFlagsTable, "update", time.Now())
+
+	updates := map[string]interface{}{}
+
+	if in.Status != nil {
+		updates["status"] = *in.Status
+	}
+	if in.ReviewedBy != nil {
+		updates["reviewed_by"] = *in.ReviewedBy
+	}
+	if in.ReviewedAt != nil {
+		updates["reviewed_at"] = *in.ReviewedAt
+	}
+
+	if len(updates) == 0 {
+		return nil
+	}
+
+	query, args, err := q.updater.SetMap(updates).ToSql()
+	if err != nil {
+		return fmt.Errorf("building updater query for table: %s: %w", contentFlagsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, contentFlagsTable, "update", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = q.db.ExecContext(ctx, query, args...)
+	}
+
+	return err
+}
+
+// AnonymizeUser forgets that userID reviewed flags; the review outcome stays.
+func (q ContentFlagsQ) AnonymizeUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return execUpdates(ctx, q.db, contentFlagsTable, "anonymize",
+		q.updater.Set("reviewed_by", nil).Where(sq.Eq{"reviewed_by": userID}),
+	)
+}
+
+func (q ContentFlagsQ) Count(ctx context.Context) (uint64, error) {
+	defer metrics.ObserveDBQuery(contentFlagsTable, "count", time.Now())
+
+	query, args, err := q.counter.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("building count query for table: %s: %w", contentFlagsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, contentFlagsTable, "count", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	var count uint64
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		err = tx.QueryRowContext(ctx, query, args...).Scan(&count)
+	} else {
+		err = q.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	}
+
+	return count, err
+}
+
+func (q ContentFlagsQ) FilterIDs(ids ...uuid.UUID) ContentFlagsQ {
+	q.selector = q.selector.Where(sq.Eq{"id": ids})
+	q.counter = q.counter.Where(sq.Eq{"id": ids})
+	q.updater = q.updater.Where(sq.Eq{"id": ids})
+	q.deleter = q.deleter.Where(sq.Eq{"id": ids})
+
+	return q
+}
+
+func (q ContentFlagsQ) FilterPetitionIDs(petitionIDs ...uuid.UUID) ContentFlagsQ {
+	q.selector = q.selector.Where(sq.Eq{"petition_id": petitionIDs})
+	q.counter = q.counter.Where(sq.Eq{"petition_id": petitionIDs})
+	q.updater = q.updater.Where(sq.Eq{"petition_id": petitionIDs})
+	q.deleter = q.deleter.Where(sq.Eq{"petition_id": petitionIDs})
+
+	return q
+}
+
+func (q ContentFlagsQ) FilterPetitionID(petitionID uuid.UUID) ContentFlagsQ {
+	q.selector = q.selector.Where(sq.Eq{"petition_id": petitionID})
+	q.counter = q.counter.Where(sq.Eq{"petition_id": petitionID})
+	q.updater = q.updater.Where(sq.Eq{"petition_id": petitionID})
+	q.deleter = q.deleter.Where(sq.Eq{"petition_id": petitionID})
+
+	return q
+}
+
+// FilterCityID keeps flags of petitions addressed to cityID.
+func (q ContentFlagsQ) FilterCityID(cityID uuid.UUID) ContentFlagsQ {
+	cond := sq.Expr("petition_id IN (SELECT id FROM "+petitionsTable+" WHERE city_id = ?)", cityID)
+
+	q.selector = q.selector.Where(cond)
+	q.counter = q.counter.Where(cond)
+	q.updater = q.updater.Where(cond)
+	q.deleter = q.deleter.Where(cond)
+
+	return q
+}
+
+func (q ContentFlagsQ) FilterStatus(status string) ContentFlagsQ {
+	q.selector = q.selector.Where(sq.Eq{"status": status})
+	q.counter = q.counter.Where(sq.Eq{"status": status})
+	q.updater = q.updater.Where(sq.Eq{"status": status})
+	q.deleter = q.deleter.Where(sq.Eq{"status": status})
+
+	return q
+}
+
+func (q ContentFlagsQ) FilterReviewedBy(userID uuid.UUID) ContentFlagsQ {
+	q.selector = q.selector.Where(sq.Eq{"reviewed_by": userID})
+	q.counter = q.counter.Where(sq.Eq{"reviewed_by": userID})
+	q.updater = q.updater.Where(sq.Eq{"reviewed_by": userID})
+	q.deleter = q.deleter.Where(sq.Eq{"reviewed_by": userID})
+
+	return q
+}
+
+func (q ContentFlagsQ) OrderByCreated(ascending bool) ContentFlagsQ {
+	if ascending {
+		q.selector = q.selector.OrderBy("created_at ASC")
+	} else {
+		q.selector = q.selector.OrderBy("created_at DESC")
+	}
+
+	return q
+}
+
+func (q ContentFlagsQ) Page(limit, offset uint64) ContentFlagsQ {
+	q.selector = q.selector.Limit(limit).Offset(offset)
+
+	return q
+}
@@ -0,0 +1,253 @@
+package dbx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/chains-lab/city-petitions-svc/internal/metrics"
+	"github.com/google/uuid"
+)
+
+const idempotencyKeysTable = "idempotency_keys"
+
+type IdempotencyKey struct {
+	UserID       uuid.UUID `db:"user_id"`
+	Method       string    `db:"method"`
+	Key          string    `db:"key"`
+	RequestHash  []byte    `db:"request_hash"`
+	Status       string    `db:"status"`
+	ResponseType string    `db:"response_type"`
+	Response     []byte    `db:"response"`
+	CreatedAt    time.Time `db:"created_at"`
+	ExpiresAt    time.Time `db:"expires_at"`
+}
+
+type IdempotencyKeysQ struct {
+	db       *sql.DB
+	selector sq.SelectBuilder
+	inserter sq.InsertBuilder
+	updater  sq.UpdateBuilder
+	deleter  sq.DeleteBuilder
+}
+
+func NewIdempotencyKeysQ(db *sql.DB) IdempotencyKeysQ {
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	selectCols := []string{
+		"user_id",
+		"method",
+		"key",
+		"request_hash",
+		"status",
+		"response_type",
+		"response",
+		"created_at",
+		"expires_at",
+	}
+
+	return IdempotencyKeysQ{
+		db:       db,
+		selector: builder.Select(selectCols...).From(idempotencyKeysTable),
+		inserter: builder.Insert(idempotencyKeysTable),
+		updater:  builder.Update(idempotencyKeysTable),
+		deleter:  builder.Delete(idempotencyKeysTable),
+	}
+}
+
+func (q IdempotencyKeysQ) New() IdempotencyKeysQ {
+	return NewIdempotencyKeysQ(q.db)
+}
+
+// Insert stores input unless the key is already taken; it reports whether the row was inserted.
+func (q IdempotencyKeysQ) Insert(ctx context.Context, input IdempotencyKey) (bool, error) {
+	defer metrics.ObserveDBQuery(idempotencyKeysTable, "insert", time.Now())
+
+	values := map[string]interface{}{
+		"user_id":       input.UserID,
+		"method":        input.Method,
+		"key":           input.Key,
+		"request_hash":  input.RequestHash,
+		"status":        input.Status,
+		"response_type": input.ResponseType,
+		"response":      input.Response,
+		"created_at":    input.CreatedAt,
+		"expires_at":    input.ExpiresAt,
+	}
+
+	query, args, err := q.inserter.SetMap(values).Suffix("ON CONFLICT (user_id, method, key) DO NOTHING").ToSql()
+	if err != nil {
+		return false, fmt.Errorf("building inserter query for table: %s: %w", idempotencyKeysTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, idempotencyKeysTable, "insert", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	var res sql.Result
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		res, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		res, err = q.db.ExecContext(ctx, query, args...)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	inserted, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return inserted == 1, nil
+}
+
+func (q IdempotencyKeysQ) Get(ctx context.Context) (IdempotencyKey, error) {
+	defer metrics.ObserveDBQuery(idempotencyKeysTable, "get", time.Now())
+
+	query, args, err := q.selector.Limit(1).ToSql()
+	if err != nil {
+		return IdempotencyKey{}, fmt.Errorf("building selector query for table: %s: %w", idempotencyKeysTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, idempotencyKeysTable, "get", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	var row *sql.Row
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		row = tx.QueryRowContext(ctx, query, args...)
+	} else {
+		row = q.db.QueryRowContext(ctx, query, args...)
+	}
+
+	var k IdempotencyKey
+	err = row.Scan(
+		&k.UserID,
+		&k.Method,
+		&k.Key,
+		&k.RequestHash,
+		&k.Status,
+		&k.ResponseType,
+		&k.Response,
+		&k.CreatedAt,
+		&k.ExpiresAt,
+	)
+
+	return k, err
+}
+
+type UpdateIdempotencyKeyInput struct {
+	Status       *string
+	ResponseType *string
+	Response     []byte
+}
+
+func (q IdempotencyKeysQ) Update(ctx context.Context, in UpdateIdempotencyKeyInput) error {
+	defer metrics.ObserveDBQuery(idempotencyKeysTable, "update", time.Now())
+
+	updates := map[string]interface{}{}
+
+	if in.Status != nil {
+		updates["status"] = *in.Status
+	}
+	if in.ResponseType != nil {
+		updates["response_type"] = *in.ResponseType
+	}
+	if in.Response != nil {
+		updates["response"] = in.Response
+	}
+
+	if len(updates) == 0 {
+		return nil
+	}
+
+	query, args, err := q.updater.SetMap(updates).ToSql()
+	if err != nil {
+		return fmt.Errorf("building updater query for table: %s: %w", idempotencyKeysTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, idempotencyKeysTable, "update", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = q.db.ExecContext(ctx, query, args...)
+	}
+
+	return err
+}
+
+func (q IdempotencyKeysQ) Delete(ctx context.Context) error {
+	defer metrics.ObserveDBQuery(idempotencyKeysTable, "delete", time.Now())
+
+	query, args, err := q.deleter.ToSql()
+	if err != nil {
+		return fmt.Errorf("building deleter query for table: %s: %w", idempotencyKeysTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, idempotencyKeysTable, "delete", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = q.db.ExecContext(ctx, query, args...)
+	}
+
+	return err
+}
+
+// FilterKey selects the key of one user and method.
+func (q IdempotencyKeysQ) FilterKey(userID uuid.UUID, method, key string) IdempotencyKeysQ {
+	cond := sq.Eq{"user_id": userID, "method": method, "key": key}
+
+	q.selector = q.selector.Where(cond)
+	q.updater = q.updater.Where(cond)
+	q.deleter = q.deleter.Where(cond)
+
+	return q
+}
+
+func (q IdempotencyKeysQ) FilterUserID(userID uuid.UUID) IdempotencyKeysQ {
+	q.selector = q.selector.Where(sq.Eq{"user_id": userID})
+	q.updater = q.updater.Where(sq.Eq{"user_id": userID})
+	q.deleter = q.deleter.Where(sq.Eq{"user_id": userID})
+
+	return q
+}
+
+func (q IdempotencyKeysQ) FilterStatus(status string) IdempotencyKeysQ {
+	q.selector = q.selector.Where(sq.Eq{"status": status})
+	q.updater = q.updater.Where(sq.Eq{"status": status})
+	q.deleter = q.deleter.Where(sq.Eq{"status": status})
+
+	return q
+}
+
+func (q IdempotencyKeysQ) FilterExpiresAt(t time.Time, after bool) IdempotencyKeysQ {
+	query := "expires_at > ?"
+	if !after {
+		query = "expires_at < ?"
+	}
+
+	q.selector = q.selector.Where(query, t)
+	q.updater = q.updater.Where(query, t)
+	q.deleter = q.deleter.Where(query, t)
+
+	return q
+}
+
+func (q IdempotencyKeysQ) FilterCreatedAt(t time.Time, after bool) IdempotencyKeysQ {
+	query := "created_at > ?"
+	if !after {
+		query = "created_at < ?"
+	}
+
+	q.selector = q.selector.Where(query, t)
+	q.updater = q.updater.Where(query, t)
+	q.deleter = q.deleter.Where(query, t)
+
+	return q
+}
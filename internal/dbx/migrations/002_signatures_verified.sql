@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE "petition_signatures"
+    ADD COLUMN "verified" BOOLEAN NOT NULL DEFAULT FALSE; -- signer had a verified account at signing time
+
+-- +migrate Down
+ALTER TABLE "petition_signatures"
+    DROP COLUMN IF EXISTS "verified";
@@ -0,0 +1,28 @@
+-- +migrate Up
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION notify_petition_update()
+RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify(
+        'petition_updates',
+        json_build_object(
+            'petition_id', NEW.id,
+            'signatures',  NEW.signatures,
+            'status',      NEW.status,
+            'updated_at',  to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"')
+        )::text
+    );
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER petitions_after_upd_notify
+    AFTER UPDATE ON petitions
+    FOR EACH ROW
+    WHEN (OLD.signatures IS DISTINCT FROM NEW.signatures OR OLD.status IS DISTINCT FROM NEW.status)
+    EXECUTE FUNCTION notify_petition_update();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS petitions_after_upd_notify ON petitions;
+DROP FUNCTION IF EXISTS notify_petition_update();
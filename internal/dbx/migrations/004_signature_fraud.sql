@@ -0,0 +1,111 @@
+-- +migrate Up
+ALTER TABLE "petition_signatures"
+    ADD COLUMN "session_id"         UUID,                           -- session of the signer's token, used for fraud analysis
+    ADD COLUMN "invalidated"        BOOLEAN NOT NULL DEFAULT FALSE, -- invalidated signatures are not counted
+    ADD COLUMN "invalidated_reason" VARCHAR(1024),
+    ADD COLUMN "invalidated_by"     UUID,
+    ADD COLUMN "invalidated_at"     TIMESTAMP;
+
+CREATE INDEX "petition_signatures_session_id_idx" ON "petition_signatures" ("session_id");
+CREATE INDEX "petition_signatures_petition_id_created_at_idx" ON "petition_signatures" ("petition_id", "created_at");
+
+CREATE TYPE signature_flag_status AS ENUM (
+    'open',      -- waiting for review
+    'dismissed', -- reviewed, signature is fine
+    'confirmed'  -- reviewed, signature was invalidated
+);
+
+CREATE TABLE "signature_flags" (
+    "id"           UUID                  PRIMARY KEY NOT NULL,
+    "signature_id" UUID                  NOT NULL REFERENCES "petition_signatures" ("id") ON DELETE CASCADE,
+    "petition_id"  UUID                  NOT NULL REFERENCES "petitions" ("id") ON DELETE CASCADE,
+    "reason"       VARCHAR(64)           NOT NULL,
+    "details"      VARCHAR(1024)         NOT NULL DEFAULT '',
+    "status"       signature_flag_status NOT NULL DEFAULT 'open',
+    "reviewed_by"  UUID,
+    "reviewed_at"  TIMESTAMP,
+    "created_at"   TIMESTAMP             NOT NULL,
+    UNIQUE ("signature_id", "reason")
+);
+
+CREATE INDEX "signature_flags_petition_id_status_idx" ON "signature_flags" ("petition_id", "status");
+
+-- also repairs the function from 001, which is missing its END IF
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION sync_petition_signatures_counter()
+RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        IF NOT NEW.invalidated THEN
+            UPDATE petitions
+                SET signatures = signatures + 1
+                WHERE id = NEW.petition_id;
+        END IF;
+        RETURN NEW;
+
+    ELSIF TG_OP = 'DELETE' THEN
+        IF NOT OLD.invalidated THEN
+            UPDATE petitions
+                SET signatures = GREATEST(signatures - 1, 0)
+                WHERE id = OLD.petition_id;
+        END IF;
+        RETURN OLD;
+
+    ELSIF TG_OP = 'UPDATE' THEN
+        IF NEW.invalidated AND NOT OLD.invalidated THEN
+            UPDATE petitions
+                SET signatures = GREATEST(signatures - 1, 0)
+                WHERE id = NEW.petition_id;
+        ELSIF OLD.invalidated AND NOT NEW.invalidated THEN
+            UPDATE petitions
+                SET signatures = signatures + 1
+                WHERE id = NEW.petition_id;
+        END IF;
+        RETURN NEW;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER petition_signatures_after_upd
+    AFTER UPDATE OF invalidated ON petition_signatures
+    FOR EACH ROW
+    EXECUTE FUNCTION sync_petition_signatures_counter();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS petition_signatures_after_upd ON petition_signatures;
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION sync_petition_signatures_counter()
+RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE petitions
+            SET signatures = signatures + 1
+            WHERE id = NEW.petition_id;
+        RETURN NEW;
+
+    ELSIF TG_OP = 'DELETE' THEN
+        UPDATE petitions
+            SET signatures = GREATEST(signatures - 1, 0)
+            WHERE id = OLD.petition_id;
+        RETURN OLD;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+DROP TABLE IF EXISTS "signature_flags";
+DROP TYPE IF EXISTS signature_flag_status;
+
+DROP INDEX IF EXISTS "petition_signatures_petition_id_created_at_idx";
+DROP INDEX IF EXISTS "petition_signatures_session_id_idx";
+
+ALTER TABLE "petition_signatures"
+    DROP COLUMN IF EXISTS "invalidated_at",
+    DROP COLUMN IF EXISTS "invalidated_by",
+    DROP COLUMN IF EXISTS "invalidated_reason",
+    DROP COLUMN IF EXISTS "invalidated",
+    DROP COLUMN IF EXISTS "session_id";
@@ -0,0 +1,24 @@
+-- +migrate Up
+CREATE TYPE idempotency_key_status AS ENUM (
+    'pending',   -- the first request with the key is being handled
+    'completed'  -- the response is stored and replayed to retries
+);
+
+CREATE TABLE "idempotency_keys" (
+    "user_id"       UUID                   NOT NULL,
+    "method"        VARCHAR(255)           NOT NULL,
+    "key"           VARCHAR(255)           NOT NULL,
+    "request_hash"  BYTEA                  NOT NULL,
+    "status"        idempotency_key_status NOT NULL DEFAULT 'pending',
+    "response_type" VARCHAR(255)           NOT NULL DEFAULT '',
+    "response"      BYTEA,
+    "created_at"    TIMESTAMP              NOT NULL,
+    "expires_at"    TIMESTAMP              NOT NULL,
+    PRIMARY KEY ("user_id", "method", "key")
+);
+
+CREATE INDEX "idempotency_keys_expires_at_idx" ON "idempotency_keys" ("expires_at");
+
+-- +migrate Down
+DROP TABLE IF EXISTS "idempotency_keys";
+DROP TYPE IF EXISTS idempotency_key_status;
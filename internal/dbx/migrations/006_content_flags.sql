@@ -0,0 +1,30 @@
+-- +migrate Up
+ALTER TYPE petition_status ADD VALUE IF NOT EXISTS 'moderation'; -- held by the content filter until reviewed
+
+CREATE TYPE content_flag_status AS ENUM (
+    'open',      -- waiting for review
+    'dismissed', -- reviewed, content is fine
+    'confirmed'  -- reviewed, content is not acceptable
+);
+
+CREATE TABLE "content_flags" (
+    "id"          UUID                PRIMARY KEY NOT NULL,
+    "petition_id" UUID                NOT NULL REFERENCES "petitions" ("id") ON DELETE CASCADE,
+    "field"       VARCHAR(64)         NOT NULL,
+    "filter"      VARCHAR(64)         NOT NULL,
+    "action"      VARCHAR(16)         NOT NULL,
+    "details"     VARCHAR(1024)       NOT NULL DEFAULT '',
+    "status"      content_flag_status NOT NULL DEFAULT 'open',
+    "reviewed_by" UUID,
+    "reviewed_at" TIMESTAMP,
+    "created_at"  TIMESTAMP           NOT NULL
+);
+
+CREATE INDEX "content_flags_petition_id_status_idx" ON "content_flags" ("petition_id", "status");
+
+-- +migrate Down
+DROP TABLE IF EXISTS "content_flags";
+DROP TYPE IF EXISTS content_flag_status;
+
+-- enum values can not be dropped, so petitions held for moderation are published
+UPDATE "petitions" SET "status" = 'published' WHERE "status" = 'moderation';
@@ -0,0 +1,10 @@
+-- +migrate Up
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- duplicate detection compares titles of open petitions within a city
+CREATE INDEX "petitions_city_id_status_idx" ON "petitions" ("city_id", "status");
+
+-- +migrate Down
+DROP INDEX IF EXISTS "petitions_city_id_status_idx";
+
+-- pg_trgm is left installed: it may have existed before this migration or be used by others
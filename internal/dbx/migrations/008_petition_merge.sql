@@ -0,0 +1,55 @@
+-- +migrate Up
+ALTER TYPE petition_status ADD VALUE IF NOT EXISTS 'merged'; -- consolidated into the petition in merged_into
+
+ALTER TABLE "petitions"
+    ADD COLUMN "merged_into" UUID REFERENCES "petitions" ("id") ON DELETE SET NULL;
+
+ALTER TABLE "petition_signatures"
+    ADD COLUMN "merged_from" UUID; -- petition the signature was given to before a merge
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION notify_petition_update()
+RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify(
+        'petition_updates',
+        json_build_object(
+            'petition_id', NEW.id,
+            'signatures',  NEW.signatures,
+            'status',      NEW.status,
+            'merged_into', NEW.merged_into,
+            'updated_at',  to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"')
+        )::text
+    );
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION notify_petition_update()
+RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify(
+        'petition_updates',
+        json_build_object(
+            'petition_id', NEW.id,
+            'signatures',  NEW.signatures,
+            'status',      NEW.status,
+            'updated_at',  to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"')
+        )::text
+    );
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+ALTER TABLE "petition_signatures"
+    DROP COLUMN IF EXISTS "merged_from";
+
+-- enum values can not be dropped, so merged petitions are rejected
+UPDATE "petitions" SET "status" = 'rejected' WHERE "status" = 'merged';
+
+ALTER TABLE "petitions"
+    DROP COLUMN IF EXISTS "merged_into";
@@ -0,0 +1,27 @@
+-- +migrate Up
+ALTER TYPE petition_status ADD VALUE IF NOT EXISTS 'draft'; -- prepared by its authors, not published yet
+
+CREATE TYPE petition_author_status AS ENUM (
+    'invited',  -- waiting for the invitee to answer
+    'accepted', -- listed as co-initiator of the petition
+    'declined'  -- invitation was declined
+);
+
+CREATE TABLE "petition_authors" (
+    "petition_id"  UUID                   NOT NULL REFERENCES "petitions" ("id") ON DELETE CASCADE,
+    "user_id"      UUID                   NOT NULL,
+    "status"       petition_author_status NOT NULL DEFAULT 'invited',
+    "invited_by"   UUID                   NOT NULL,
+    "responded_at" TIMESTAMP,
+    "created_at"   TIMESTAMP              NOT NULL,
+    PRIMARY KEY ("petition_id", "user_id")
+);
+
+CREATE INDEX "petition_authors_user_id_status_idx" ON "petition_authors" ("user_id", "status");
+
+-- +migrate Down
+DROP TABLE IF EXISTS "petition_authors";
+DROP TYPE IF EXISTS petition_author_status;
+
+-- enum values can not be dropped, so drafts are rejected to keep them unlisted
+UPDATE "petitions" SET "status" = 'rejected' WHERE "status" = 'draft';
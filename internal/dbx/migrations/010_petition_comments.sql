@@ -0,0 +1,51 @@
+-- +migrate Up
+CREATE TYPE comment_status AS ENUM (
+    'published',  -- visible to everyone
+    'moderation', -- held by the content filter or by reports until a moderator reviews it
+    'removed'     -- removed by a moderator
+);
+
+CREATE TABLE "petition_comments" (
+    "id"          UUID           PRIMARY KEY NOT NULL,
+    "petition_id" UUID           NOT NULL REFERENCES "petitions" ("id") ON DELETE CASCADE,
+    "parent_id"   UUID           REFERENCES "petition_comments" ("id") ON DELETE CASCADE,
+    "depth"       INT            NOT NULL DEFAULT 0 CHECK (depth >= 0), -- 0 for top-level comments
+    "author_id"   UUID           NOT NULL,
+    "body"        VARCHAR(4096)  NOT NULL,
+    "status"      comment_status NOT NULL DEFAULT 'published',
+    "edited_at"   TIMESTAMP,
+    "deleted_at"  TIMESTAMP, -- soft deleted by its author or a moderator
+    "deleted_by"  UUID,
+    "created_at"  TIMESTAMP      NOT NULL
+);
+
+-- threads are read oldest first and paged by (created_at, id)
+CREATE INDEX "petition_comments_petition_id_parent_id_created_at_idx"
+    ON "petition_comments" ("petition_id", "parent_id", "created_at", "id");
+
+CREATE TYPE comment_report_status AS ENUM (
+    'open',      -- waiting for review
+    'dismissed', -- reviewed, comment is fine
+    'confirmed'  -- reviewed, comment was removed
+);
+
+CREATE TABLE "comment_reports" (
+    "id"          UUID                  PRIMARY KEY NOT NULL,
+    "comment_id"  UUID                  NOT NULL REFERENCES "petition_comments" ("id") ON DELETE CASCADE,
+    "reporter_id" UUID                  NOT NULL,
+    "reason"      VARCHAR(1024)         NOT NULL,
+    "status"      comment_report_status NOT NULL DEFAULT 'open',
+    "reviewed_by" UUID,
+    "reviewed_at" TIMESTAMP,
+    "created_at"  TIMESTAMP             NOT NULL,
+    UNIQUE ("comment_id", "reporter_id")
+);
+
+CREATE INDEX "comment_reports_comment_id_status_idx" ON "comment_reports" ("comment_id", "status");
+
+-- +migrate Down
+DROP TABLE IF EXISTS "comment_reports";
+DROP TYPE IF EXISTS comment_report_status;
+
+DROP TABLE IF EXISTS "petition_comments";
+DROP TYPE IF EXISTS comment_status;
@@ -0,0 +1,27 @@
+-- +migrate Up
+CREATE TYPE signature_visibility AS ENUM (
+    'public',    -- signer and reason are shown to everyone
+    'anonymous', -- reason is shown, signer is not
+    'private'    -- only counted
+);
+
+-- existing signers never chose to be listed by name
+ALTER TABLE "petition_signatures"
+    ADD COLUMN "reason"        VARCHAR(500),
+    ADD COLUMN "reason_hidden" BOOLEAN              NOT NULL DEFAULT FALSE, -- held by the content filter
+    ADD COLUMN "visibility"    signature_visibility NOT NULL DEFAULT 'anonymous';
+
+-- top reasons of a petition are read newest first
+CREATE INDEX "petition_signatures_petition_id_reasons_idx"
+    ON "petition_signatures" ("petition_id", "created_at")
+    WHERE "reason" IS NOT NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS "petition_signatures_petition_id_reasons_idx";
+
+ALTER TABLE "petition_signatures"
+    DROP COLUMN IF EXISTS "visibility",
+    DROP COLUMN IF EXISTS "reason_hidden",
+    DROP COLUMN IF EXISTS "reason";
+
+DROP TYPE IF EXISTS signature_visibility;
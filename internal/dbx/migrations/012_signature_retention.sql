@@ -0,0 +1,14 @@
+-- +migrate Up
+ALTER TABLE "petition_signatures"
+    ADD COLUMN "pseudonymized_at" TIMESTAMP; -- user_id was replaced by a keyed hash by the retention job
+
+-- the retention job looks for signatures of closed petitions that still name their signer
+CREATE INDEX "petition_signatures_not_pseudonymized_idx"
+    ON "petition_signatures" ("petition_id")
+    WHERE "pseudonymized_at" IS NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS "petition_signatures_not_pseudonymized_idx";
+
+ALTER TABLE "petition_signatures"
+    DROP COLUMN IF EXISTS "pseudonymized_at";
@@ -0,0 +1,33 @@
+-- +migrate Up
+-- Every signature given to a petition is appended to the petition's hash chain. Entries are
+-- never changed: invalidation, merges, retention and erasure touch petition_signatures only,
+-- so receipts handed out to signers stay verifiable.
+CREATE TABLE "petition_signature_chain" (
+    "petition_id"  UUID      NOT NULL,
+    "seq"          BIGINT    NOT NULL CHECK (seq > 0),
+    "signature_id" UUID      NOT NULL,
+    "user_hash"    BYTEA     NOT NULL, -- keyed hash of the signer, salted with the petition
+    "prev_hash"    BYTEA     NOT NULL, -- hash of entry seq - 1, or the petition's genesis hash
+    "hash"         BYTEA     NOT NULL, -- sha256 of prev_hash, signature_id, user_hash and created_at
+    "created_at"   TIMESTAMP NOT NULL,
+    PRIMARY KEY ("petition_id", "seq"),
+    UNIQUE ("petition_id", "signature_id")
+);
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION reject_signature_chain_change()
+RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'petition_signature_chain is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER petition_signature_chain_append_only
+    BEFORE UPDATE OR DELETE ON petition_signature_chain
+    FOR EACH ROW
+    EXECUTE FUNCTION reject_signature_chain_change();
+
+-- +migrate Down
+DROP TABLE IF EXISTS "petition_signature_chain";
+DROP FUNCTION IF EXISTS reject_signature_chain_change();
@@ -0,0 +1,8 @@
+-- +migrate Up
+-- duplicate detection filters titles with the pg_trgm % operator, which this index serves
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX "petitions_title_trgm_idx" ON "petitions" USING gin ("title" gin_trgm_ops);
+
+-- +migrate Down
+DROP INDEX IF EXISTS "petitions_title_trgm_idx";
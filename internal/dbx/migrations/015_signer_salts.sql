@@ -0,0 +1,12 @@
+-- +migrate Up
+-- Signer hashes in the signature chain are keyed with a random salt per user. Erasure deletes
+-- the salt, after which the user's chain entries can not be linked back to them, even with the
+-- pseudonym key.
+CREATE TABLE "signer_salts" (
+    "user_id"    UUID      PRIMARY KEY,
+    "salt"       BYTEA     NOT NULL,
+    "created_at" TIMESTAMP NOT NULL
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS "signer_salts";
@@ -0,0 +1,12 @@
+-- +migrate Up
+ALTER TABLE "petitions"
+    ADD COLUMN "closed_at" TIMESTAMP; -- when the petition was answered, merged or rejected by moderation
+
+-- closing did not touch updated_at before, so this is the best known time for petitions closed so far
+UPDATE "petitions"
+    SET "closed_at" = "updated_at"
+    WHERE "status" IN ('approved', 'rejected', 'merged');
+
+-- +migrate Down
+ALTER TABLE "petitions"
+    DROP COLUMN IF EXISTS "closed_at";
@@ -0,0 +1,301 @@
+package dbx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/chains-lab/city-petitions-svc/internal/metrics"
+	"github.com/google/uuid"
+)
+
+const petitionAuthorsTable = "petition_authors"
+
+// PetitionAuthor is a co-author of a petition; the creator is kept on the petition itself.
+type PetitionAuthor struct {
+	PetitionID  uuid.UUID    `db:"petition_id"`
+	UserID      uuid.UUID    `db:"user_id"`
+	Status      string       `db:"status"`
+	InvitedBy   uuid.UUID    `db:"invited_by"`
+	RespondedAt sql.NullTime `db:"responded_at"`
+	CreatedAt   time.Time    `db:"created_at"`
+}
+
+type PetitionAuthorsQ struct {
+	db       *sql.DB
+	selector sq.SelectBuilder
+	inserter sq.InsertBuilder
+	updater  sq.UpdateBuilder
+	deleter  sq.DeleteBuilder
+	counter  sq.SelectBuilder
+}
+
+func NewPetitionAuthorsQ(db *sql.DB) PetitionAuthorsQ {
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	selectCols := []string{
+		"petition_id",
+		"user_id",
+		"status",
+		"invited_by",
+		"responded_at",
+		"created_at",
+	}
+
+	return PetitionAuthorsQ{
+		db:       db,
+		selector: builder.Select(selectCols...).From(petitionAuthorsTable),
+		inserter: builder.Insert(petitionAuthorsTable),
+		updater:  builder.Update(petitionAuthorsTable),
+		deleter:  builder.Delete(petitionAuthorsTable),
+		counter:  builder.Select("COUNT(*) AS count").From(petitionAuthorsTable),
+	}
+}
+
+func (q PetitionAuthorsQ) New() PetitionAuthorsQ {
+	return NewPetitionAuthorsQ(q.db)
+}
+
+func (q PetitionAuthorsQ) Insert(ctx context.Context, input PetitionAuthor) error {
+	defer metrics.ObserveDBQuery(petitionAuthorsTable, "insert", time.Now())
+
+	values := map[string]interface{}{
+		"petition_id": input.PetitionID,
+		"user_id":     input.UserID,
+		"status":      input.Status,
+		"invited_by":  input.InvitedBy,
+		"created_at":  input.CreatedAt,
+	}
+
+	query, args, err := q.inserter.SetMap(values).ToSql()
+	if err != nil {
+		return fmt.Errorf("building inserter query for table: %s: %w", petitionAuthorsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, petitionAuthorsTable, "insert", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = q.db.ExecContext(ctx, query, args...)
+	}
+
+	return err
+}
+
+func (q PetitionAuthorsQ) Get(ctx context.Context) (PetitionAuthor, error) {
+	defer metrics.ObserveDBQuery(petitionAuthorsTable, "get", time.Now())
+
+	query, args, err := q.selector.Limit(1).ToSql()
+	if err != nil {
+		return PetitionAuthor{}, fmt.Errorf("building selector query for table: %s: %w", petitionAuthorsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, petitionAuthorsTable, "get", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	var row *sql.Row
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		row = tx.QueryRowContext(ctx, query, args...)
+	} else {
+		row = q.db.QueryRowContext(ctx, query, args...)
+	}
+
+	var a PetitionAuthor
+	err = row.Scan(
+		&a.PetitionID,
+		&a.UserID,
+		&a.Status,
+		&a.InvitedBy,
+		&a.RespondedAt,
+		&a.CreatedAt,
+	)
+
+	return a, err
+}
+
+func (q PetitionAuthorsQ) Select(ctx context.Context) ([]PetitionAuthor, error) {
+	defer metrics.ObserveDBQuery(petitionAuthorsTable, "select", time.Now())
+
+	query, args, err := q.selector.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building selector query for table: %s: %w", petitionAuthorsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, petitionAuthorsTable, "select", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	var rows *sql.Rows
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		rows, err = tx.QueryContext(ctx, query, args...)
+	} else {
+		rows, err = q.db.QueryContext(ctx, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []PetitionAuthor
+	for rows.Next() {
+		var a PetitionAuthor
+		if err = rows.Scan(
+			&a.PetitionID,
+			&a.UserID,
+			&a.Status,
+			&a.InvitedBy,
+			&a.RespondedAt,
+			&a.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+
+	return out, nil
+}
+
+type UpdatePetitionAuthorInput struct {
+	Status      *string
+	InvitedBy   *uuid.UUID
+	RespondedAt *sql.NullTime
+	CreatedAt   *time.Time
+}
+
+func (q PetitionAuthorsQ) Update(ctx context.Context, in UpdatePetitionAuthorInput) error {
+	defer metrics.ObserveDBQuery(petitionAuthorsTable, "update", time.Now())
+
+	updates := map[string]interface{}{}
+
+	if in.Status != nil {
+		updates["status"] = *in.Status
+	}
+	if in.InvitedBy != nil {
+		updates["invited_by"] = *in.InvitedBy
+	}
+	if in.RespondedAt != nil {
+		updates["responded_at"] = *in.RespondedAt
+	}
+	if in.CreatedAt != nil {
+		updates["created_at"] = *in.CreatedAt
+	}
+
+	if len(updates) == 0 {
+		return nil
+	}
+
+	query, args, err := q.updater.SetMap(updates).ToSql()
+	if err != nil {
+		return fmt.Errorf("building updater query for table: %s: %w", petitionAuthorsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, petitionAuthorsTable, "update", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = q.db.ExecContext(ctx, query, args...)
+	}
+
+	return err
+}
+
+// AnonymizeUser detaches the co-authorships and invitations of userID from them. Accepted
+// co-authors keep counting towards the minimum of their draft.
+func (q PetitionAuthorsQ) AnonymizeUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return execUpdates(ctx, q.db, petitionAuthorsTable, "anonymize",
+		q.updater.Set("user_id", anonymousID).Where(sq.Eq{"user_id": userID}),
+		q.updater.Set("invited_by", anonymousID).Where(sq.Eq{"invited_by": userID}),
+	)
+}
+
+func (q PetitionAuthorsQ) Delete(ctx context.Context) error {
+	defer metrics.ObserveDBQuery(petitionAuthorsTable, "delete", time.Now())
+
+	query, args, err := q.deleter.ToSql()
+	if err != nil {
+		return fmt.Errorf("building deleter query for table: %s: %w", petitionAuthorsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, petitionAuthorsTable, "delete", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = q.db.ExecContext(ctx, query, args...)
+	}
+
+	return err
+}
+
+func (q PetitionAuthorsQ) Count(ctx context.Context) (uint64, error) {
+	defer metrics.ObserveDBQuery(petitionAuthorsTable, "count", time.Now())
+
+	query, args, err := q.counter.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("building count query for table: %s: %w", petitionAuthorsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, petitionAuthorsTable, "count", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	var count uint64
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		err = tx.QueryRowContext(ctx, query, args...).Scan(&count)
+	} else {
+		err = q.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	}
+
+	return count, err
+}
+
+func (q PetitionAuthorsQ) FilterPetitionID(petitionID uuid.UUID) PetitionAuthorsQ {
+	q.selector = q.selector.Where(sq.Eq{"petition_id": petitionID})
+	q.counter = q.counter.Where(sq.Eq{"petition_id": petitionID})
+	q.updater = q.updater.Where(sq.Eq{"petition_id": petitionID})
+	q.deleter = q.deleter.Where(sq.Eq{"petition_id": petitionID})
+
+	return q
+}
+
+func (q PetitionAuthorsQ) FilterPetitionIDs(petitionIDs ...uuid.UUID) PetitionAuthorsQ {
+	q.selector = q.selector.Where(sq.Eq{"petition_id": petitionIDs})
+	q.counter = q.counter.Where(sq.Eq{"petition_id": petitionIDs})
+	q.updater = q.updater.Where(sq.Eq{"petition_id": petitionIDs})
+	q.deleter = q.deleter.Where(sq.Eq{"petition_id": petitionIDs})
+
+	return q
+}
+
+func (q PetitionAuthorsQ) FilterUserID(userID uuid.UUID) PetitionAuthorsQ {
+	q.selector = q.selector.Where(sq.Eq{"user_id": userID})
+	q.counter = q.counter.Where(sq.Eq{"user_id": userID})
+	q.updater = q.updater.Where(sq.Eq{"user_id": userID})
+	q.deleter = q.deleter.Where(sq.Eq{"user_id": userID})
+
+	return q
+}
+
+func (q PetitionAuthorsQ) FilterStatus(status ...string) PetitionAuthorsQ {
+	q.selector = q.selector.Where(sq.Eq{"status": status})
+	q.counter = q.counter.Where(sq.Eq{"status": status})
+	q.updater = q.updater.Where(sq.Eq{"status": status})
+	q.deleter = q.deleter.Where(sq.Eq{"status": status})
+
+	return q
+}
+
+func (q PetitionAuthorsQ) OrderByCreated(ascending bool) PetitionAuthorsQ {
+	if ascending {
+		q.selector = q.selector.OrderBy("created_at ASC")
+	} else {
+		q.selector = q.selector.OrderBy("created_at DESC")
+	}
+
+	return q
+}
@@ -0,0 +1,370 @@
+package dbx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/chains-lab/city-petitions-svc/internal/metrics"
+	"github.com/google/uuid"
+)
+
+const petitionCommentsTable = "petition_comments"
+
+type PetitionComment struct {
+	ID         uuid.UUID     `db:"id"`
+	PetitionID uuid.UUID     `db:"petition_id"`
+	ParentID   uuid.NullUUID `db:"parent_id"`
+	Depth      int           `db:"depth"`
+	AuthorID   uuid.UUID     `db:"author_id"`
+	Body       string        `db:"body"`
+	Status     string        `db:"status"`
+	EditedAt   sql.NullTime  `db:"edited_at"`
+	DeletedAt  sql.NullTime  `db:"deleted_at"`
+	DeletedBy  uuid.NullUUID `db:"deleted_by"`
+	CreatedAt  time.Time     `db:"created_at"`
+
+	// Replies is the number of direct replies, it is only read.
+	Replies int `db:"replies"`
+}
+
+type PetitionCommentsQ struct {
+	db       *sql.DB
+	selector sq.SelectBuilder
+	inserter sq.InsertBuilder
+	updater  sq.UpdateBuilder
+	counter  sq.SelectBuilder
+}
+
+func NewPetitionCommentsQ(db *sql.DB) PetitionCommentsQ {
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	selectCols := []string{
+		"id",
+		"petition_id",
+		"parent_id",
+		"depth",
+		"author_id",
+		"body",
+		"status",
+		"edited_at",
+		"deleted_at",
+		"deleted_by",
+		"created_at",
+		"(SELECT COUNT(*) FROM " + petitionCommentsTable + " r WHERE r.parent_id = " + petitionCommentsTable + ".id) AS replies",
+	}
+
+	return PetitionCommentsQ{
+		db:       db,
+		selector: builder.Select(selectCols...).From(petitionCommentsTable),
+		inserter: builder.Insert(petitionCommentsTable),
+		updater:  builder.Update(petitionCommentsTable),
+		counter:  builder.Select("COUNT(*) AS count").From(petitionCommentsTable),
+	}
+}
+
+func (q PetitionCommentsQ) New() PetitionCommentsQ {
+	return NewPetitionCommentsQ(q.db)
+}
+
+func (q PetitionCommentsQ) Insert(ctx context.Context, input PetitionComment) error {
+	defer metrics.ObserveDBQuery(petitionCommentsTable, "insert", time.Now())
+
+	values := map[string]interface{}{
+		"id":          input.ID,
+		"petition_id": input.PetitionID,
+		"parent_id":   input.ParentID,
+		"depth":       input.Depth,
+		"author_id":   input.AuthorID,
+		"body":        input.Body,
+		"status":      input.Status,
+		"created_at":  input.CreatedAt,
+	}
+
+	query, args, err := q.inserter.SetMap(values).ToSql()
+	if err != nil {
+		return fmt.Errorf("building inserter query for table: %s: %w", petitionCommentsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, petitionCommentsTable, "insert", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = q.db.ExecContext(ctx, query, args...)
+	}
+
+	return err
+}
+
+func (q PetitionCommentsQ) Get(ctx context.Context) (PetitionComment, error) {
+	defer metrics.ObserveDBQuery(petitionCommentsTable, "get", time.Now())
+
+	query, args, err := q.selector.Limit(1).ToSql()
+	if err != nil {
+		return PetitionComment{}, fmt.Errorf("building selector query for table: %s: %w", petitionCommentsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, petitionCommentsTable, "get", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	var row *sql.Row
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		row = tx.QueryRowContext(ctx, query, args...)
+	} else {
+		row = q.db.QueryRowContext(ctx, query, args...)
+	}
+
+	var c PetitionComment
+	err = row.Scan(
+		&c.ID,
+		&c.PetitionID,
+		&c.ParentID,
+		&c.Depth,
+		&c.AuthorID,
+		&c.Body,
+		&c.Status,
+		&c.EditedAt,
+		&c.DeletedAt,
+		&c.DeletedBy,
+		&c.CreatedAt,
+		&c.Replies,
+	)
+
+	return c, err
+}
+
+func (q PetitionCommentsQ) Select(ctx context.Context) ([]PetitionComment, error) {
+	defer metrics.ObserveDBQuery(petitionCommentsTable, "select", time.Now())
+
+	query, args, err := q.selector.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building selector query for table: %s: %w", petitionCommentsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, petitionCommentsTable, "select", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	var rows *sql.Rows
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		rows, err = tx.QueryContext(ctx, query, args...)
+	} else {
+		rows, err = q.db.QueryContext(ctx, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []PetitionComment
+	for rows.Next() {
+		var c PetitionComment
+		if err = rows.Scan(
+			&c.ID,
+			&c.PetitionID,
+			&c.ParentID,
+			&c.Depth,
+			&c.AuthorID,
+			&c.Body,
+			&c.Status,
+			&c.EditedAt,
+			&c.DeletedAt,
+			&c.DeletedBy,
+			&c.CreatedAt,
+			&c.Replies,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+
+	return out, nil
+}
+
+type UpdatePetitionCommentInput struct {
+	Body      *string
+	Status    *string
+	EditedAt  *time.Time
+	DeletedAt *time.Time
+	DeletedBy *uuid.UUID
+}
+
+func (q PetitionCommentsQ) Update(ctx context.Context, in UpdatePetitionCommentInput) error {
+	defer metrics.ObserveDBQuery(petitionCommentsTable, "update", time.Now())
+
+	updates := map[string]interface{}{}
+
+	if in.Body != nil {
+		updates["body"] = *in.Body
+	}
+	if in.Status != nil {
+		updates["status"] = *in.Status
+	}
+	if in.EditedAt != nil {
+		updates["edited_at"] = *in.EditedAt
+	}
+	if in.DeletedAt != nil {
+		updates["deleted_at"] = *in.DeletedAt
+	}
+	if in.DeletedBy != nil {
+		updates["deleted_by"] = *in.DeletedBy
+	}
+
+	if len(updates) == 0 {
+		return nil
+	}
+
+	query, args, err := q.updater.SetMap(updates).ToSql()
+	if err != nil {
+		return fmt.Errorf("building updater query for table: %s: %w", petitionCommentsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, petitionCommentsTable, "update", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = q.db.ExecContext(ctx, query, args...)
+	}
+
+	return err
+}
+
+// AnonymizeUser removes the comments of userID and detaches them from the user. The rows stay,
+// so replies of other users keep their thread. Deletions userID made are forgotten.
+func (q PetitionCommentsQ) AnonymizeUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return execUpdates(ctx, q.db, petitionCommentsTable, "anonymize",
+		q.updater.
+			Set("author_id", anonymousID).
+			Set("body", "").
+			Set("deleted_at", sq.Expr("COALESCE(deleted_at, ?)", time.Now().UTC())).
+			Set("deleted_by", nil).
+			Where(sq.Eq{"author_id": userID}),
+		q.updater.Set("deleted_by", nil).Where(sq.Eq{"deleted_by": userID}),
+	)
+}
+
+func (q PetitionCommentsQ) Count(ctx context.Context) (uint64, error) {
+	defer metrics.ObserveDBQuery(petitionCommentsTable, "count", time.Now())
+
+	query, args, err := q.counter.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("building count query for table: %s: %w", petitionCommentsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, petitionCommentsTable, "count", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	var count uint64
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		err = tx.QueryRowContext(ctx, query, args...).Scan(&count)
+	} else {
+		err = q.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	}
+
+	return count, err
+}
+
+func (q PetitionCommentsQ) FilterID(id uuid.UUID) PetitionCommentsQ {
+	q.selector = q.selector.Where(sq.Eq{"id": id})
+	q.counter = q.counter.Where(sq.Eq{"id": id})
+	q.updater = q.updater.Where(sq.Eq{"id": id})
+
+	return q
+}
+
+func (q PetitionCommentsQ) FilterPetitionID(petitionID uuid.UUID) PetitionCommentsQ {
+	q.selector = q.selector.Where(sq.Eq{"petition_id": petitionID})
+	q.counter = q.counter.Where(sq.Eq{"petition_id": petitionID})
+	q.updater = q.updater.Where(sq.Eq{"petition_id": petitionID})
+
+	return q
+}
+
+// FilterParentID keeps replies to parentID, or top-level comments when parentID is nil.
+func (q PetitionCommentsQ) FilterParentID(parentID *uuid.UUID) PetitionCommentsQ {
+	var cond sq.Sqlizer = sq.Eq{"parent_id": nil}
+	if parentID != nil {
+		cond = sq.Eq{"parent_id": *parentID}
+	}
+
+	q.selector = q.selector.Where(cond)
+	q.counter = q.counter.Where(cond)
+	q.updater = q.updater.Where(cond)
+
+	return q
+}
+
+func (q PetitionCommentsQ) FilterAuthorID(authorID uuid.UUID) PetitionCommentsQ {
+	q.selector = q.selector.Where(sq.Eq{"author_id": authorID})
+	q.counter = q.counter.Where(sq.Eq{"author_id": authorID})
+	q.updater = q.updater.Where(sq.Eq{"author_id": authorID})
+
+	return q
+}
+
+func (q PetitionCommentsQ) FilterStatus(status ...string) PetitionCommentsQ {
+	q.selector = q.selector.Where(sq.Eq{"status": status})
+	q.counter = q.counter.Where(sq.Eq{"status": status})
+	q.updater = q.updater.Where(sq.Eq{"status": status})
+
+	return q
+}
+
+// FilterStatusOrAuthor keeps comments in one of statuses and any comment written by authorID.
+func (q PetitionCommentsQ) FilterStatusOrAuthor(authorID uuid.UUID, status ...string) PetitionCommentsQ {
+	cond := sq.Or{sq.Eq{"status": status}, sq.Eq{"author_id": authorID}}
+
+	q.selector = q.selector.Where(cond)
+	q.counter = q.counter.Where(cond)
+	q.updater = q.updater.Where(cond)
+
+	return q
+}
+
+func (q PetitionCommentsQ) FilterDeletedBy(userID uuid.UUID) PetitionCommentsQ {
+	q.selector = q.selector.Where(sq.Eq{"deleted_by": userID})
+	q.counter = q.counter.Where(sq.Eq{"deleted_by": userID})
+	q.updater = q.updater.Where(sq.Eq{"deleted_by": userID})
+
+	return q
+}
+
+// FilterNotDeleted keeps comments that were not soft deleted.
+func (q PetitionCommentsQ) FilterNotDeleted() PetitionCommentsQ {
+	cond := sq.Eq{"deleted_at": nil}
+
+	q.selector = q.selector.Where(cond)
+	q.counter = q.counter.Where(cond)
+	q.updater = q.updater.Where(cond)
+
+	return q
+}
+
+// After keeps comments following the one created at createdAt with id in OrderByCreated(true) order.
+func (q PetitionCommentsQ) After(createdAt time.Time, id uuid.UUID) PetitionCommentsQ {
+	q.selector = q.selector.Where("(created_at, id) > (?, ?)", createdAt, id)
+
+	return q
+}
+
+// OrderByCreated orders by creation time, ties are broken by id to keep cursors stable.
+func (q PetitionCommentsQ) OrderByCreated(ascending bool) PetitionCommentsQ {
+	if ascending {
+		q.selector = q.selector.OrderBy("created_at ASC", "id ASC")
+	} else {
+		q.selector = q.selector.OrderBy("created_at DESC", "id DESC")
+	}
+
+	return q
+}
+
+func (q PetitionCommentsQ) Limit(limit uint64) PetitionCommentsQ {
+	q.selector = q.selector.Limit(limit)
+
+	return q
+}
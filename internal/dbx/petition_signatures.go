@@ -7,6 +7,7 @@ import (
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/chains-lab/city-petitions-svc/internal/metrics"
 	"github.com/google/uuid"
 )
 
@@ -17,6 +18,21 @@ type PetitionSignature struct {
 	PetitionID uuid.UUID `db:"petition_id"`
 	UserID     uuid.UUID `db:"user_id"`
 	CreatedAt  time.Time `db:"created_at"`
+	Verified   bool      `db:"verified"`
+
+	SessionID         uuid.NullUUID  `db:"session_id"`
+	Invalidated       bool           `db:"invalidated"`
+	InvalidatedReason sql.NullString `db:"invalidated_reason"`
+	InvalidatedBy     uuid.NullUUID  `db:"invalidated_by"`
+	InvalidatedAt     sql.NullTime   `db:"invalidated_at"`
+
+	MergedFrom uuid.NullUUID `db:"merged_from"`
+
+	Reason       sql.NullString `db:"reason"`
+	ReasonHidden bool           `db:"reason_hidden"`
+	Visibility   string         `db:"visibility"`
+
+	PseudonymizedAt sql.NullTime `db:"pseudonymized_at"`
 }
 
 type PetitionSignaturesQ struct {
@@ -30,9 +46,28 @@ type PetitionSignaturesQ struct {
 
 func NewPetitionSignaturesQ(db *sql.DB) PetitionSignaturesQ {
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	selectCols := []string{
+		"id",
+		"petition_id",
+		"user_id",
+		"created_at",
+		"verified",
+		"session_id",
+		"invalidated",
+		"invalidated_reason",
+		"invalidated_by",
+		"invalidated_at",
+		"merged_from",
+		"reason",
+		"reason_hidden",
+		"visibility",
+		"pseudonymized_at",
+	}
+
 	return PetitionSignaturesQ{
 		db:       db,
-		selector: builder.Select("*").From(petitionSignaturesTable),
+		selector: builder.Select(selectCols...).From(petitionSignaturesTable),
 		inserter: builder.Insert(petitionSignaturesTable),
 		updater:  builder.Update(petitionSignaturesTable),
 		deleter:  builder.Delete(petitionSignaturesTable),
@@ -45,17 +80,28 @@ func (q PetitionSignaturesQ) New() PetitionSignaturesQ {
 }
 
 func (q PetitionSignaturesQ) Insert(ctx context.Context, input PetitionSignature) error {
+	defer metrics.ObserveDBQuery(petitionSignaturesTable, "insert", time.Now())
+
 	values := map[string]interface{}{
-		"id":          input.ID,
-		"petition_id": input.PetitionID,
-		"user_id":     input.UserID,
-		"created_at":  input.CreatedAt,
+		"id":            input.ID,
+		"petition_id":   input.PetitionID,
+		"user_id":       input.UserID,
+		"created_at":    input.CreatedAt,
+		"verified":      input.Verified,
+		"session_id":    input.SessionID,
+		"invalidated":   input.Invalidated,
+		"reason":        input.Reason,
+		"reason_hidden": input.ReasonHidden,
+		"visibility":    input.Visibility,
 	}
 	query, args, err := q.inserter.SetMap(values).ToSql()
 	if err != nil {
 		return fmt.Errorf("building inserter query for table: %s input: %w", petitionSignaturesTable, err)
 	}
 
+	ctx, span := startQuerySpan(ctx, petitionSignaturesTable, "insert", query)
+	defer func() { endQuerySpan(span, err) }()
+
 	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
 		_, err = tx.ExecContext(ctx, query, args...)
 	} else {
@@ -66,11 +112,16 @@ func (q PetitionSignaturesQ) Insert(ctx context.Context, input PetitionSignature
 }
 
 func (q PetitionSignaturesQ) Get(ctx context.Context) (PetitionSignature, error) {
+	defer metrics.ObserveDBQuery(petitionSignaturesTable, "get", time.Now())
+
 	query, args, err := q.selector.Limit(1).ToSql()
 	if err != nil {
 		return PetitionSignature{}, fmt.Errorf("building selector query for table: %s: %w", petitionSignaturesTable, err)
 	}
 
+	ctx, span := startQuerySpan(ctx, petitionSignaturesTable, "get", query)
+	defer func() { endQuerySpan(span, err) }()
+
 	var s PetitionSignature
 	var row *sql.Row
 	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
@@ -84,17 +135,33 @@ func (q PetitionSignaturesQ) Get(ctx context.Context) (PetitionSignature, error)
 		&s.PetitionID,
 		&s.UserID,
 		&s.CreatedAt,
+		&s.Verified,
+		&s.SessionID,
+		&s.Invalidated,
+		&s.InvalidatedReason,
+		&s.InvalidatedBy,
+		&s.InvalidatedAt,
+		&s.MergedFrom,
+		&s.Reason,
+		&s.ReasonHidden,
+		&s.Visibility,
+		&s.PseudonymizedAt,
 	)
 
 	return s, err
 }
 
 func (q PetitionSignaturesQ) Select(ctx context.Context) ([]PetitionSignature, error) {
+	defer metrics.ObserveDBQuery(petitionSignaturesTable, "select", time.Now())
+
 	query, args, err := q.selector.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("building selector query for table: %s: %w", petitionSignaturesTable, err)
 	}
 
+	ctx, span := startQuerySpan(ctx, petitionSignaturesTable, "select", query)
+	defer func() { endQuerySpan(span, err) }()
+
 	var rows *sql.Rows
 	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
 		rows, err = tx.QueryContext(ctx, query, args...)
@@ -110,11 +177,22 @@ func (q PetitionSignaturesQ) Select(ctx context.Context) ([]PetitionSignature, e
 
 	for rows.Next() {
 		var s PetitionSignature
-		if err := rows.Scan(
+		if err = rows.Scan(
 			&s.ID,
 			&s.PetitionID,
 			&s.UserID,
 			&s.CreatedAt,
+			&s.Verified,
+			&s.SessionID,
+			&s.Invalidated,
+			&s.InvalidatedReason,
+			&s.InvalidatedBy,
+			&s.InvalidatedAt,
+			&s.MergedFrom,
+			&s.Reason,
+			&s.ReasonHidden,
+			&s.Visibility,
+			&s.PseudonymizedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -124,12 +202,195 @@ func (q PetitionSignaturesQ) Select(ctx context.Context) ([]PetitionSignature, e
 	return out, nil
 }
 
+type UpdatePetitionSignatureInput struct {
+	Invalidated       *bool
+	InvalidatedReason *string
+	InvalidatedBy     *uuid.UUID
+	InvalidatedAt     *time.Time
+}
+
+func (q PetitionSignaturesQ) Update(ctx context.Context, in UpdatePetitionSignatureInput) (int64, error) {
+	defer metrics.ObserveDBQuery(petitionSignaturesTable, "update", time.Now())
+
+	updates := map[string]interface{}{}
+
+	if in.Invalidated != nil {
+		updates["invalidated"] = *in.Invalidated
+	}
+	if in.InvalidatedReason != nil {
+		updates["invalidated_reason"] = *in.InvalidatedReason
+	}
+	if in.InvalidatedBy != nil {
+		updates["invalidated_by"] = *in.InvalidatedBy
+	}
+	if in.InvalidatedAt != nil {
+		updates["invalidated_at"] = *in.InvalidatedAt
+	}
+
+	if len(updates) == 0 {
+		return 0, nil
+	}
+
+	query, args, err := q.updater.SetMap(updates).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("building updater query for table: %s: %w", petitionSignaturesTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, petitionSignaturesTable, "update", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	var res sql.Result
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		res, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		res, err = q.db.ExecContext(ctx, query, args...)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// MoveToPetition moves the signatures of sourceIDs to targetID and remembers where they came from.
+// Users who already signed the target keep only that signature; of users who signed several
+// sources the earliest signature is moved. It returns the number of moved signatures.
+func (q PetitionSignaturesQ) MoveToPetition(ctx context.Context, targetID uuid.UUID, sourceIDs ...uuid.UUID) (int64, error) {
+	defer metrics.ObserveDBQuery(petitionSignaturesTable, "move", time.Now())
+
+	moved, movedArgs, err := sq.Select("DISTINCT ON (user_id) id").
+		From(petitionSignaturesTable).
+		Where(sq.Eq{"petition_id": sourceIDs}).
+		Where("user_id NOT IN (SELECT user_id FROM "+petitionSignaturesTable+" WHERE petition_id = ?)", targetID).
+		OrderBy("user_id", "created_at").
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("building move subquery for table: %s: %w", petitionSignaturesTable, err)
+	}
+
+	query, args, err := q.updater.
+		Set("merged_from", sq.Expr("petition_id")).
+		Set("petition_id", targetID).
+		Where("id IN ("+moved+")", movedArgs...).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("building move query for table: %s: %w", petitionSignaturesTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, petitionSignaturesTable, "move", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	var res sql.Result
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		res, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		res, err = q.db.ExecContext(ctx, query, args...)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// SignatureStats aggregates the signatures of one petition.
+type SignatureStats struct {
+	Total       uint64
+	Verified    uint64
+	Invalidated uint64
+	Public      uint64
+	Anonymous   uint64
+	Private     uint64
+	WithReason  uint64
+}
+
+// Stats counts the signatures of petitionID in a single pass.
+func (q PetitionSignaturesQ) Stats(ctx context.Context, petitionID uuid.UUID) (SignatureStats, error) {
+	defer metrics.ObserveDBQuery(petitionSignaturesTable, "stats", time.Now())
+
+	query, args, err := sq.Select(
+		"COUNT(*)",
+		"COUNT(*) FILTER (WHERE verified)",
+		"COUNT(*) FILTER (WHERE invalidated)",
+		"COUNT(*) FILTER (WHERE visibility = 'public')",
+		"COUNT(*) FILTER (WHERE visibility = 'anonymous')",
+		"COUNT(*) FILTER (WHERE visibility = 'private')",
+		"COUNT(reason)",
+	).
+		From(petitionSignaturesTable).
+		Where(sq.Eq{"petition_id": petitionID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return SignatureStats{}, fmt.Errorf("building stats query for table: %s: %w", petitionSignaturesTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, petitionSignaturesTable, "stats", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	var row *sql.Row
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		row = tx.QueryRowContext(ctx, query, args...)
+	} else {
+		row = q.db.QueryRowContext(ctx, query, args...)
+	}
+
+	var st SignatureStats
+	err = row.Scan(&st.Total, &st.Verified, &st.Invalidated, &st.Public, &st.Anonymous, &st.Private, &st.WithReason)
+
+	return st, err
+}
+
+// AnonymizeUser detaches the signatures of userID from them and forgets which signatures they
+// invalidated. The signatures keep counting; the reason and session are dropped with the signer.
+func (q PetitionSignaturesQ) AnonymizeUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return execUpdates(ctx, q.db, petitionSignaturesTable, "anonymize",
+		q.updater.
+			Set("user_id", anonymousID).
+			Set("session_id", nil).
+			Set("reason", nil).
+			Set("reason_hidden", false).
+			Set("visibility", "private").
+			Where(sq.Eq{"user_id": userID}),
+		q.updater.Set("invalidated_by", nil).Where(sq.Eq{"invalidated_by": userID}),
+	)
+}
+
+// Pseudonymize replaces the signer of each signature in pseudonyms, keyed by signature ID, with
+// its pseudonym and drops the session. Signatures pseudonymized meanwhile are skipped, so
+// concurrent runs never hash a pseudonym again.
+func (q PetitionSignaturesQ) Pseudonymize(ctx context.Context, pseudonyms map[uuid.UUID]uuid.UUID, at time.Time) (int64, error) {
+	if len(pseudonyms) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(pseudonyms))
+	userID := sq.Case("id")
+	for id, pseudonym := range pseudonyms {
+		ids = append(ids, id)
+		userID = userID.When(sq.Expr("?::uuid", id), sq.Expr("?::uuid", pseudonym))
+	}
+
+	return execUpdates(ctx, q.db, petitionSignaturesTable, "pseudonymize",
+		q.updater.
+			Set("user_id", userID).
+			Set("session_id", nil).
+			Set("pseudonymized_at", at).
+			Where(sq.Eq{"id": ids, "pseudonymized_at": nil}),
+	)
+}
+
 func (q PetitionSignaturesQ) Delete(ctx context.Context) error {
+	defer metrics.ObserveDBQuery(petitionSignaturesTable, "delete", time.Now())
+
 	query, args, err := q.deleter.ToSql()
 	if err != nil {
 		return fmt.Errorf("building deleter query for table: %s: %w", petitionSignaturesTable, err)
 	}
 
+	ctx, span := startQuerySpan(ctx, petitionSignaturesTable, "delete", query)
+	defer func() { endQuerySpan(span, err) }()
+
 	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
 		_, err = tx.ExecContext(ctx, query, args...)
 	} else {
@@ -150,6 +411,15 @@ func (q PetitionSignaturesQ) FilterID(id uuid.UUID) PetitionSignaturesQ {
 	return q
 }
 
+func (q PetitionSignaturesQ) FilterIDs(ids ...uuid.UUID) PetitionSignaturesQ {
+	q.selector = q.selector.Where(sq.Eq{"id": ids})
+	q.counter = q.counter.Where(sq.Eq{"id": ids})
+	q.updater = q.updater.Where(sq.Eq{"id": ids})
+	q.deleter = q.deleter.Where(sq.Eq{"id": ids})
+
+	return q
+}
+
 func (q PetitionSignaturesQ) FilterPetitionID(petitionID uuid.UUID) PetitionSignaturesQ {
 	q.selector = q.selector.Where(sq.Eq{"petition_id": petitionID})
 	q.counter = q.counter.Where(sq.Eq{"petition_id": petitionID})
@@ -168,6 +438,126 @@ func (q PetitionSignaturesQ) FilterUserID(userID uuid.UUID) PetitionSignaturesQ
 	return q
 }
 
+func (q PetitionSignaturesQ) FilterUserIDNot(userID uuid.UUID) PetitionSignaturesQ {
+	q.selector = q.selector.Where(sq.NotEq{"user_id": userID})
+	q.counter = q.counter.Where(sq.NotEq{"user_id": userID})
+	q.updater = q.updater.Where(sq.NotEq{"user_id": userID})
+	q.deleter = q.deleter.Where(sq.NotEq{"user_id": userID})
+
+	return q
+}
+
+func (q PetitionSignaturesQ) FilterInvalidatedBy(userID uuid.UUID) PetitionSignaturesQ {
+	q.selector = q.selector.Where(sq.Eq{"invalidated_by": userID})
+	q.counter = q.counter.Where(sq.Eq{"invalidated_by": userID})
+	q.updater = q.updater.Where(sq.Eq{"invalidated_by": userID})
+	q.deleter = q.deleter.Where(sq.Eq{"invalidated_by": userID})
+
+	return q
+}
+
+func (q PetitionSignaturesQ) FilterMergedFrom(petitionIDs ...uuid.UUID) PetitionSignaturesQ {
+	q.selector = q.selector.Where(sq.Eq{"merged_from": petitionIDs})
+	q.counter = q.counter.Where(sq.Eq{"merged_from": petitionIDs})
+	q.updater = q.updater.Where(sq.Eq{"merged_from": petitionIDs})
+	q.deleter = q.deleter.Where(sq.Eq{"merged_from": petitionIDs})
+
+	return q
+}
+
+// FilterUnchained keeps signatures missing from the hash chain of the petition they belong to.
+func (q PetitionSignaturesQ) FilterUnchained() PetitionSignaturesQ {
+	cond := sq.Expr("NOT EXISTS (SELECT 1 FROM " + signatureChainTable + " c" +
+		" WHERE c.petition_id = " + petitionSignaturesTable + ".petition_id" +
+		" AND c.signature_id = " + petitionSignaturesTable + ".id)")
+
+	q.selector = q.selector.Where(cond)
+	q.counter = q.counter.Where(cond)
+	q.updater = q.updater.Where(cond)
+	q.deleter = q.deleter.Where(cond)
+
+	return q
+}
+
+func (q PetitionSignaturesQ) FilterSessionID(sessionID uuid.UUID) PetitionSignaturesQ {
+	q.selector = q.selector.Where(sq.Eq{"session_id": sessionID})
+	q.counter = q.counter.Where(sq.Eq{"session_id": sessionID})
+	q.updater = q.updater.Where(sq.Eq{"session_id": sessionID})
+	q.deleter = q.deleter.Where(sq.Eq{"session_id": sessionID})
+
+	return q
+}
+
+func (q PetitionSignaturesQ) FilterInvalidated(invalidated bool) PetitionSignaturesQ {
+	q.selector = q.selector.Where(sq.Eq{"invalidated": invalidated})
+	q.counter = q.counter.Where(sq.Eq{"invalidated": invalidated})
+	q.updater = q.updater.Where(sq.Eq{"invalidated": invalidated})
+	q.deleter = q.deleter.Where(sq.Eq{"invalidated": invalidated})
+
+	return q
+}
+
+func (q PetitionSignaturesQ) FilterVisibility(visibility ...string) PetitionSignaturesQ {
+	q.selector = q.selector.Where(sq.Eq{"visibility": visibility})
+	q.counter = q.counter.Where(sq.Eq{"visibility": visibility})
+	q.updater = q.updater.Where(sq.Eq{"visibility": visibility})
+	q.deleter = q.deleter.Where(sq.Eq{"visibility": visibility})
+
+	return q
+}
+
+// FilterVisibilityOrUserID keeps signatures with one of visibility and all signatures of userID.
+func (q PetitionSignaturesQ) FilterVisibilityOrUserID(userID uuid.UUID, visibility ...string) PetitionSignaturesQ {
+	cond := sq.Or{sq.Eq{"visibility": visibility}, sq.Eq{"user_id": userID}}
+
+	q.selector = q.selector.Where(cond)
+	q.counter = q.counter.Where(cond)
+	q.updater = q.updater.Where(cond)
+	q.deleter = q.deleter.Where(cond)
+
+	return q
+}
+
+// FilterShownReason keeps signatures with a reason that was not held by the content filter.
+func (q PetitionSignaturesQ) FilterShownReason() PetitionSignaturesQ {
+	cond := sq.And{sq.NotEq{"reason": nil}, sq.Eq{"reason_hidden": false}}
+
+	q.selector = q.selector.Where(cond)
+	q.counter = q.counter.Where(cond)
+	q.updater = q.updater.Where(cond)
+	q.deleter = q.deleter.Where(cond)
+
+	return q
+}
+
+func (q PetitionSignaturesQ) FilterPseudonymized(pseudonymized bool) PetitionSignaturesQ {
+	cond := sq.Sqlizer(sq.Eq{"pseudonymized_at": nil})
+	if pseudonymized {
+		cond = sq.NotEq{"pseudonymized_at": nil}
+	}
+
+	q.selector = q.selector.Where(cond)
+	q.counter = q.counter.Where(cond)
+	q.updater = q.updater.Where(cond)
+	q.deleter = q.deleter.Where(cond)
+
+	return q
+}
+
+func (q PetitionSignaturesQ) FilterCreatedAt(t time.Time, after bool) PetitionSignaturesQ {
+	query := "created_at > ?"
+	if !after {
+		query = "created_at < ?"
+	}
+
+	q.selector = q.selector.Where(query, t)
+	q.counter = q.counter.Where(query, t)
+	q.updater = q.updater.Where(query, t)
+	q.deleter = q.deleter.Where(query, t)
+
+	return q
+}
+
 func (q PetitionSignaturesQ) OrderByCreated(ascending bool) PetitionSignaturesQ {
 	if ascending {
 		q.selector = q.selector.OrderBy("created_at ASC")
@@ -178,12 +568,24 @@ func (q PetitionSignaturesQ) OrderByCreated(ascending bool) PetitionSignaturesQ
 	return q
 }
 
+// OrderByVerifiedFirst lists signatures of verified users first, newest first within each group.
+func (q PetitionSignaturesQ) OrderByVerifiedFirst() PetitionSignaturesQ {
+	q.selector = q.selector.OrderBy("verified DESC", "created_at DESC")
+
+	return q
+}
+
 func (q PetitionSignaturesQ) Count(ctx context.Context) (uint64, error) {
+	defer metrics.ObserveDBQuery(petitionSignaturesTable, "count", time.Now())
+
 	query, args, err := q.counter.ToSql()
 	if err != nil {
 		return 0, fmt.Errorf("building count query for table: %s: %w", petitionSignaturesTable, err)
 	}
 
+	ctx, span := startQuerySpan(ctx, petitionSignaturesTable, "count", query)
+	defer func() { endQuerySpan(span, err) }()
+
 	var count uint64
 	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
 		err = tx.QueryRowContext(ctx, query, args...).Scan(&count)
@@ -4,9 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/chains-lab/city-petitions-svc/internal/metrics"
 	"github.com/google/uuid"
 )
 
@@ -18,18 +20,20 @@ type GeoPoint struct {
 }
 
 type Petition struct {
-	ID          uuid.UUID `db:"id"`
-	CityID      uuid.UUID `db:"city_id"`
-	CreatorID   uuid.UUID `db:"creator_id"`
-	Title       string    `db:"title"`
-	Description string    `db:"description"`
-	Status      string    `db:"status"`
-	Signatures  int       `db:"signatures"`
-	Goal        int       `db:"goal"`
-	Reply       string    `db:"reply"`
-	EndDate     time.Time `db:"end_date"`
-	CreatedAt   time.Time `db:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at"`
+	ID          uuid.UUID     `db:"id"`
+	CityID      uuid.UUID     `db:"city_id"`
+	CreatorID   uuid.UUID     `db:"creator_id"`
+	Title       string        `db:"title"`
+	Description string        `db:"description"`
+	Status      string        `db:"status"`
+	Signatures  int           `db:"signatures"`
+	Goal        int           `db:"goal"`
+	Reply       string        `db:"reply"`
+	EndDate     time.Time     `db:"end_date"`
+	CreatedAt   time.Time     `db:"created_at"`
+	UpdatedAt   time.Time     `db:"updated_at"`
+	MergedInto  uuid.NullUUID `db:"merged_into"`
+	ClosedAt    sql.NullTime  `db:"closed_at"`
 }
 
 type PetitionsQ struct {
@@ -58,6 +62,8 @@ func NewPetitionsQ(db *sql.DB) PetitionsQ {
 		"end_date",
 		"created_at",
 		"updated_at",
+		"merged_into",
+		"closed_at",
 	}
 
 	return PetitionsQ{
@@ -75,6 +81,8 @@ func (q PetitionsQ) New() PetitionsQ {
 }
 
 func (q PetitionsQ) Insert(ctx context.Context, input Petition) error {
+	defer metrics.ObserveDBQuery(petitionsTable, "insert", time.Now())
+
 	values := map[string]interface{}{
 		"id":          input.ID,
 		"city_id":     input.CityID,
@@ -95,6 +103,9 @@ func (q PetitionsQ) Insert(ctx context.Context, input Petition) error {
 		return fmt.Errorf("building inserter query for table %s: %w", petitionsTable, err)
 	}
 
+	ctx, span := startQuerySpan(ctx, petitionsTable, "insert", query)
+	defer func() { endQuerySpan(span, err) }()
+
 	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
 		_, err = tx.ExecContext(ctx, query, args...)
 	} else {
@@ -105,11 +116,16 @@ func (q PetitionsQ) Insert(ctx context.Context, input Petition) error {
 }
 
 func (q PetitionsQ) Get(ctx context.Context) (Petition, error) {
+	defer metrics.ObserveDBQuery(petitionsTable, "get", time.Now())
+
 	query, args, err := q.selector.Limit(1).ToSql()
 	if err != nil {
 		return Petition{}, fmt.Errorf("building selector query for table %s: %w", petitionsTable, err)
 	}
 
+	ctx, span := startQuerySpan(ctx, petitionsTable, "get", query)
+	defer func() { endQuerySpan(span, err) }()
+
 	var p Petition
 	var row *sql.Row
 	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
@@ -131,17 +147,24 @@ func (q PetitionsQ) Get(ctx context.Context) (Petition, error) {
 		&p.EndDate,
 		&p.CreatedAt,
 		&p.UpdatedAt,
+		&p.MergedInto,
+		&p.ClosedAt,
 	)
 
 	return p, err
 }
 
 func (q PetitionsQ) Select(ctx context.Context) ([]Petition, error) {
+	defer metrics.ObserveDBQuery(petitionsTable, "select", time.Now())
+
 	query, args, err := q.selector.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("building selector query for table %s: %w", petitionsTable, err)
 	}
 
+	ctx, span := startQuerySpan(ctx, petitionsTable, "select", query)
+	defer func() { endQuerySpan(span, err) }()
+
 	var rows *sql.Rows
 	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
 		rows, err = tx.QueryContext(ctx, query, args...)
@@ -156,7 +179,7 @@ func (q PetitionsQ) Select(ctx context.Context) ([]Petition, error) {
 	var out []Petition
 	for rows.Next() {
 		var p Petition
-		if err := rows.Scan(
+		if err = rows.Scan(
 			&p.ID,
 			&p.CityID,
 			&p.CreatorID,
@@ -169,6 +192,8 @@ func (q PetitionsQ) Select(ctx context.Context) ([]Petition, error) {
 			&p.EndDate,
 			&p.CreatedAt,
 			&p.UpdatedAt,
+			&p.MergedInto,
+			&p.ClosedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -178,15 +203,103 @@ func (q PetitionsQ) Select(ctx context.Context) ([]Petition, error) {
 	return out, nil
 }
 
+type SimilarPetition struct {
+	Petition
+	Similarity float64 `db:"similarity"`
+}
+
+// SelectSimilarTitles selects petitions whose title has a trigram similarity to title
+// of at least minSimilarity, most similar first. It needs the pg_trgm extension.
+// Titles are filtered with the % operator, so the trigram index on title is used; its threshold
+// is set to minSimilarity for the transaction the query runs in.
+func (q PetitionsQ) SelectSimilarTitles(ctx context.Context, title string, minSimilarity float64) ([]SimilarPetition, error) {
+	defer metrics.ObserveDBQuery(petitionsTable, "select_similar", time.Now())
+
+	query, args, err := q.selector.
+		Column(sq.Expr("similarity(title, ?) AS similarity", title)).
+		Where(sq.Expr("title % ?", title)).
+		OrderBy("similarity DESC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building similarity query for table %s: %w", petitionsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, petitionsTable, "select_similar", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	var out []SimilarPetition
+	err = Transaction(ctx, q.db, func(ctx context.Context) error {
+		tx := ctx.Value(TxKey).(*sql.Tx)
+
+		threshold := strconv.FormatFloat(minSimilarity, 'f', -1, 64)
+		if _, err := tx.ExecContext(ctx, "SELECT set_config('pg_trgm.similarity_threshold', $1, true)", threshold); err != nil {
+			return fmt.Errorf("setting similarity threshold: %w", err)
+		}
+
+		rows, err := tx.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var p SimilarPetition
+			if err := rows.Scan(
+				&p.ID,
+				&p.CityID,
+				&p.CreatorID,
+				&p.Title,
+				&p.Description,
+				&p.Status,
+				&p.Signatures,
+				&p.Goal,
+				&p.Reply,
+				&p.EndDate,
+				&p.CreatedAt,
+				&p.UpdatedAt,
+				&p.MergedInto,
+				&p.ClosedAt,
+				&p.Similarity,
+			); err != nil {
+				return err
+			}
+			out = append(out, p)
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
 type UpdatePetitionInput struct {
-	Status  *string
-	Reply   *string
-	EndDate *time.Time
+	Title       *string
+	Description *string
+	UpdatedAt   *time.Time
+	Status      *string
+	Reply       *string
+	EndDate     *time.Time
+	MergedInto  *uuid.UUID
+	ClosedAt    *time.Time
 }
 
 func (q PetitionsQ) Update(ctx context.Context, in UpdatePetitionInput) error {
+	defer metrics.ObserveDBQuery(petitionsTable, "update", time.Now())
+
 	updates := map[string]interface{}{}
 
+	if in.Title != nil {
+		updates["title"] = *in.Title
+	}
+	if in.Description != nil {
+		updates["description"] = *in.Description
+	}
+	if in.UpdatedAt != nil {
+		updates["updated_at"] = *in.UpdatedAt
+	}
 	if in.Reply != nil {
 		updates["reply"] = *in.Reply
 	}
@@ -196,6 +309,12 @@ func (q PetitionsQ) Update(ctx context.Context, in UpdatePetitionInput) error {
 	if in.EndDate != nil {
 		updates["end_date"] = *in.EndDate
 	}
+	if in.MergedInto != nil {
+		updates["merged_into"] = *in.MergedInto
+	}
+	if in.ClosedAt != nil {
+		updates["closed_at"] = *in.ClosedAt
+	}
 
 	if len(updates) == 0 {
 		return nil
@@ -206,6 +325,9 @@ func (q PetitionsQ) Update(ctx context.Context, in UpdatePetitionInput) error {
 		return fmt.Errorf("building updater query for table %s: %w", petitionsTable, err)
 	}
 
+	ctx, span := startQuerySpan(ctx, petitionsTable, "update", query)
+	defer func() { endQuerySpan(span, err) }()
+
 	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
 		_, err = tx.ExecContext(ctx, query, args...)
 	} else {
@@ -215,12 +337,48 @@ func (q PetitionsQ) Update(ctx context.Context, in UpdatePetitionInput) error {
 	return err
 }
 
+// RecountSignatures sets the counter of the selected petitions to their number of valid signatures.
+func (q PetitionsQ) RecountSignatures(ctx context.Context) error {
+	defer metrics.ObserveDBQuery(petitionsTable, "recount", time.Now())
+
+	query, args, err := q.updater.
+		Set("signatures", sq.Expr("(SELECT COUNT(*) FROM "+petitionSignaturesTable+" s WHERE s.petition_id = "+petitionsTable+".id AND NOT s.invalidated)")).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building recount query for table %s: %w", petitionsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, petitionsTable, "recount", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = q.db.ExecContext(ctx, query, args...)
+	}
+
+	return err
+}
+
+// AnonymizeUser detaches the petitions created by userID from them. Petitions stay published
+// with their signatures, only the creator becomes unknown.
+func (q PetitionsQ) AnonymizeUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return execUpdates(ctx, q.db, petitionsTable, "anonymize",
+		q.updater.Set("creator_id", anonymousID).Where(sq.Eq{"creator_id": userID}),
+	)
+}
+
 func (q PetitionsQ) Delete(ctx context.Context) error {
+	defer metrics.ObserveDBQuery(petitionsTable, "delete", time.Now())
+
 	query, args, err := q.deleter.ToSql()
 	if err != nil {
 		return fmt.Errorf("building deleter query for table %s: %w", petitionsTable, err)
 	}
 
+	ctx, span := startQuerySpan(ctx, petitionsTable, "delete", query)
+	defer func() { endQuerySpan(span, err) }()
+
 	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
 		_, err = tx.ExecContext(ctx, query, args...)
 	} else {
@@ -239,6 +397,15 @@ func (q PetitionsQ) FilterID(id uuid.UUID) PetitionsQ {
 	return q
 }
 
+func (q PetitionsQ) FilterIDs(ids ...uuid.UUID) PetitionsQ {
+	q.selector = q.selector.Where(sq.Eq{"id": ids})
+	q.counter = q.counter.Where(sq.Eq{"id": ids})
+	q.updater = q.updater.Where(sq.Eq{"id": ids})
+	q.deleter = q.deleter.Where(sq.Eq{"id": ids})
+
+	return q
+}
+
 func (q PetitionsQ) FilterCityID(cityID uuid.UUID) PetitionsQ {
 	q.selector = q.selector.Where(sq.Eq{"city_id": cityID})
 	q.counter = q.counter.Where(sq.Eq{"city_id": cityID})
@@ -335,11 +502,16 @@ func (q PetitionsQ) OrderBySignatures(ascending bool) PetitionsQ {
 }
 
 func (q PetitionsQ) Count(ctx context.Context) (uint64, error) {
+	defer metrics.ObserveDBQuery(petitionsTable, "count", time.Now())
+
 	query, args, err := q.counter.ToSql()
 	if err != nil {
 		return 0, fmt.Errorf("building count query for table %s: %w", petitionsTable, err)
 	}
 
+	ctx, span := startQuerySpan(ctx, petitionsTable, "count", query)
+	defer func() { endQuerySpan(span, err) }()
+
 	var count uint64
 	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
 		err = tx.QueryRowContext(ctx, query, args...).Scan(&count)
@@ -350,6 +522,22 @@ func (q PetitionsQ) Count(ctx context.Context) (uint64, error) {
 	return count, err
 }
 
+// FilterClosedBefore keeps petitions closed before t: answered or merged ones by closed_at,
+// still published ones by their end date.
+func (q PetitionsQ) FilterClosedBefore(t time.Time) PetitionsQ {
+	return q.applyCondition(sq.Or{
+		sq.And{sq.Eq{"status": []string{"approved", "rejected", "merged"}}, sq.Lt{"closed_at": t}},
+		sq.And{sq.Eq{"status": "published"}, sq.Lt{"end_date": t}},
+	})
+}
+
+// ForUpdate locks the selected rows until the end of the transaction.
+func (q PetitionsQ) ForUpdate() PetitionsQ {
+	q.selector = q.selector.Suffix("FOR UPDATE")
+
+	return q
+}
+
 func (q PetitionsQ) Page(limit, offset uint64) PetitionsQ {
 	q.selector = q.selector.Limit(limit).Offset(offset)
 	q.counter = q.counter.Limit(limit).Offset(offset)
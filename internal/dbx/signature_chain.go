@@ -0,0 +1,225 @@
+package dbx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/chains-lab/city-petitions-svc/internal/metrics"
+	"github.com/google/uuid"
+)
+
+const signatureChainTable = "petition_signature_chain"
+
+// SignatureChainEntry is one link of a petition's signature hash chain. Entries are append-only.
+type SignatureChainEntry struct {
+	PetitionID  uuid.UUID `db:"petition_id"`
+	Seq         int64     `db:"seq"`
+	SignatureID uuid.UUID `db:"signature_id"`
+	UserHash    []byte    `db:"user_hash"`
+	PrevHash    []byte    `db:"prev_hash"`
+	Hash        []byte    `db:"hash"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+type SignatureChainQ struct {
+	db       *sql.DB
+	selector sq.SelectBuilder
+	inserter sq.InsertBuilder
+	counter  sq.SelectBuilder
+}
+
+func NewSignatureChainQ(db *sql.DB) SignatureChainQ {
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	selectCols := []string{
+		"petition_id",
+		"seq",
+		"signature_id",
+		"user_hash",
+		"prev_hash",
+		"hash",
+		"created_at",
+	}
+
+	return SignatureChainQ{
+		db:       db,
+		selector: builder.Select(selectCols...).From(signatureChainTable),
+		inserter: builder.Insert(signatureChainTable),
+		counter:  builder.Select("COUNT(*) AS count").From(signatureChainTable),
+	}
+}
+
+func (q SignatureChainQ) New() SignatureChainQ {
+	return NewSignatureChainQ(q.db)
+}
+
+func (q SignatureChainQ) Insert(ctx context.Context, entries ...SignatureChainEntry) error {
+	defer metrics.ObserveDBQuery(signatureChainTable, "insert", time.Now())
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	inserter := q.inserter.Columns("petition_id", "seq", "signature_id", "user_hash", "prev_hash", "hash", "created_at")
+	for _, e := range entries {
+		inserter = inserter.Values(e.PetitionID, e.Seq, e.SignatureID, e.UserHash, e.PrevHash, e.Hash, e.CreatedAt)
+	}
+
+	query, args, err := inserter.ToSql()
+	if err != nil {
+		return fmt.Errorf("building inserter query for table: %s: %w", signatureChainTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, signatureChainTable, "insert", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = q.db.ExecContext(ctx, query, args...)
+	}
+
+	return err
+}
+
+func (q SignatureChainQ) Get(ctx context.Context) (SignatureChainEntry, error) {
+	defer metrics.ObserveDBQuery(signatureChainTable, "get", time.Now())
+
+	query, args, err := q.selector.Limit(1).ToSql()
+	if err != nil {
+		return SignatureChainEntry{}, fmt.Errorf("building selector query for table: %s: %w", signatureChainTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, signatureChainTable, "get", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	var row *sql.Row
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		row = tx.QueryRowContext(ctx, query, args...)
+	} else {
+		row = q.db.QueryRowContext(ctx, query, args...)
+	}
+
+	var e SignatureChainEntry
+	err = row.Scan(
+		&e.PetitionID,
+		&e.Seq,
+		&e.SignatureID,
+		&e.UserHash,
+		&e.PrevHash,
+		&e.Hash,
+		&e.CreatedAt,
+	)
+
+	return e, err
+}
+
+func (q SignatureChainQ) Select(ctx context.Context) ([]SignatureChainEntry, error) {
+	defer metrics.ObserveDBQuery(signatureChainTable, "select", time.Now())
+
+	query, args, err := q.selector.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building selector query for table: %s: %w", signatureChainTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, signatureChainTable, "select", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	var rows *sql.Rows
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		rows, err = tx.QueryContext(ctx, query, args...)
+	} else {
+		rows, err = q.db.QueryContext(ctx, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []SignatureChainEntry
+	for rows.Next() {
+		var e SignatureChainEntry
+		if err = rows.Scan(
+			&e.PetitionID,
+			&e.Seq,
+			&e.SignatureID,
+			&e.UserHash,
+			&e.PrevHash,
+			&e.Hash,
+			&e.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+
+	return out, rows.Err()
+}
+
+func (q SignatureChainQ) Count(ctx context.Context) (uint64, error) {
+	defer metrics.ObserveDBQuery(signatureChainTable, "count", time.Now())
+
+	query, args, err := q.counter.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("building count query for table: %s: %w", signatureChainTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, signatureChainTable, "count", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	var count uint64
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		err = tx.QueryRowContext(ctx, query, args...).Scan(&count)
+	} else {
+		err = q.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	}
+
+	return count, err
+}
+
+func (q SignatureChainQ) FilterPetitionID(petitionID uuid.UUID) SignatureChainQ {
+	q.selector = q.selector.Where(sq.Eq{"petition_id": petitionID})
+	q.counter = q.counter.Where(sq.Eq{"petition_id": petitionID})
+
+	return q
+}
+
+func (q SignatureChainQ) FilterSignatureID(signatureID uuid.UUID) SignatureChainQ {
+	q.selector = q.selector.Where(sq.Eq{"signature_id": signatureID})
+	q.counter = q.counter.Where(sq.Eq{"signature_id": signatureID})
+
+	return q
+}
+
+func (q SignatureChainQ) FilterSeq(seq int64) SignatureChainQ {
+	q.selector = q.selector.Where(sq.Eq{"seq": seq})
+	q.counter = q.counter.Where(sq.Eq{"seq": seq})
+
+	return q
+}
+
+func (q SignatureChainQ) FilterSeqAfter(seq int64) SignatureChainQ {
+	q.selector = q.selector.Where(sq.Gt{"seq": seq})
+	q.counter = q.counter.Where(sq.Gt{"seq": seq})
+
+	return q
+}
+
+func (q SignatureChainQ) OrderBySeq(ascending bool) SignatureChainQ {
+	if ascending {
+		q.selector = q.selector.OrderBy("seq ASC")
+	} else {
+		q.selector = q.selector.OrderBy("seq DESC")
+	}
+
+	return q
+}
+
+func (q SignatureChainQ) Page(limit, offset uint64) SignatureChainQ {
+	q.selector = q.selector.Limit(limit).Offset(offset)
+
+	return q
+}
@@ -0,0 +1,318 @@
+package dbx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/chains-lab/city-petitions-svc/internal/metrics"
+	"github.com/google/uuid"
+)
+
+const signatureFlagsTable = "signature_flags"
+
+type SignatureFlag struct {
+	ID          uuid.UUID     `db:"id"`
+	SignatureID uuid.UUID     `db:"signature_id"`
+	PetitionID  uuid.UUID     `db:"petition_id"`
+	Reason      string        `db:"reason"`
+	Details     string        `db:"details"`
+	Status      string        `db:"status"`
+	ReviewedBy  uuid.NullUUID `db:"reviewed_by"`
+	ReviewedAt  sql.NullTime  `db:"reviewed_at"`
+	CreatedAt   time.Time     `db:"created_at"`
+}
+
+type SignatureFlagsQ struct {
+	db       *sql.DB
+	selector sq.SelectBuilder
+	inserter sq.InsertBuilder
+	updater  sq.UpdateBuilder
+	deleter  sq.DeleteBuilder
+	counter  sq.SelectBuilder
+}
+
+func NewSignatureFlagsQ(db *sql.DB) SignatureFlagsQ {
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	selectCols := []string{
+		"id",
+		"signature_id",
+		"petition_id",
+		"reason",
+		"details",
+		"status",
+		"reviewed_by",
+		"reviewed_at",
+		"created_at",
+	}
+
+	return SignatureFlagsQ{
+		db:       db,
+		selector: builder.Select(selectCols...).From(signatureFlagsTable),
+		inserter: builder.Insert(signatureFlagsTable),
+		updater:  builder.Update(signatureFlagsTable),
+		deleter:  builder.Delete(signatureFlagsTable),
+		counter:  builder.Select("COUNT(*) AS count").From(signatureFlagsTable),
+	}
+}
+
+func (q SignatureFlagsQ) New() SignatureFlagsQ {
+	return NewSignatureFlagsQ(q.db)
+}
+
+// Insert stores flags; a flag with the same signature and reason is kept as is.
+func (q SignatureFlagsQ) Insert(ctx context.Context, flags ...SignatureFlag) error {
+	defer metrics.ObserveDBQuery(signatureFlagsTable, "insert", time.Now())
+
+	if len(flags) == 0 {
+		return nil
+	}
+
+	inserter := q.inserter.Columns("id", "signature_id", "petition_id", "reason", "details", "status", "created_at")
+	for _, f := range flags {
+		inserter = inserter.Values(f.ID, f.SignatureID, f.PetitionID, f.Reason, f.Details, f.Status, f.CreatedAt)
+	}
+
+	query, args, err := inserter.Suffix("ON CONFLICT (signature_id, reason) DO NOTHING").ToSql()
+	if err != nil {
+		return fmt.Errorf("building inserter query for table: %s: %w", signatureFlagsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, signatureFlagsTable, "insert", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = q.db.ExecContext(ctx, query, args...)
+	}
+
+	return err
+}
+
+func (q SignatureFlagsQ) Select(ctx context.Context) ([]SignatureFlag, error) {
+	defer metrics.ObserveDBQuery(signatureFlagsTable, "select", time.Now())
+
+	query, args, err := q.selector.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building selector query for table: %s: %w", signatureFlagsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, signatureFlagsTable, "select", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	var rows *sql.Rows
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		rows, err = tx.QueryContext(ctx, query, args...)
+	} else {
+		rows, err = q.db.QueryContext(ctx, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []SignatureFlag
+	for rows.Next() {
+		var f SignatureFlag
+		if err = rows.Scan(
+			&f.ID,
+			&f.SignatureID,
+			&f.PetitionID,
+			&f.Reason,
+			&f.Details,
+			&f.Status,
+			&f.ReviewedBy,
+			&f.ReviewedAt,
+			&f.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+
+	return out, nil
+}
+
+type UpdateSignatureFlagInput struct {
+	Status     *string
+	ReviewedBy *uuid.UUID
+	ReviewedAt *time.Time
+}
+
+func (q SignatureFlagsQ) Update(ctx context.Context, in UpdateSignatureFlagInput) (int64, error) {
+	defer metrics.ObserveDBQuery(signatureFlagsTable, "update", time.Now())
+
+	updates := map[string]interface{}{}
+
+	if in.Status != nil {
+		updates["status"] = *in.Status
+	}
+	if in.ReviewedBy != nil {
+		updates["reviewed_by"] = *in.ReviewedBy
+	}
+	if in.ReviewedAt != nil {
+		updates["reviewed_at"] = *in.ReviewedAt
+	}
+
+	if len(updates) == 0 {
+		return 0, nil
+	}
+
+	query, args, err := q.updater.SetMap(updates).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("building updater query for table: %s: %w", signatureFlagsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, signatureFlagsTable, "update", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	var res sql.Result
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		res, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		res, err = q.db.ExecContext(ctx, query, args...)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// FollowSignatures points the selected flags at the petition their signature belongs to now,
+// e.g. after signatures were moved by a merge.
+func (q SignatureFlagsQ) FollowSignatures(ctx context.Context) error {
+	defer metrics.ObserveDBQuery(signatureFlagsTable, "follow", time.Now())
+
+	query, args, err := q.updater.
+		Set("petition_id", sq.Expr("(SELECT s.petition_id FROM "+petitionSignaturesTable+" s WHERE s.id = "+signatureFlagsTable+".signature_id)")).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building follow query for table: %s: %w", signatureFlagsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, signatureFlagsTable, "follow", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = q.db.ExecContext(ctx, query, args...)
+	}
+
+	return err
+}
+
+// AnonymizeUser forgets that userID reviewed flags; the review outcome stays.
+func (q SignatureFlagsQ) AnonymizeUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return execUpdates(ctx, q.db, signatureFlagsTable, "anonymize",
+		q.updater.Set("reviewed_by", nil).Where(sq.Eq{"reviewed_by": userID}),
+	)
+}
+
+func (q SignatureFlagsQ) Count(ctx context.Context) (uint64, error) {
+	defer metrics.ObserveDBQuery(signatureFlagsTable, "count", time.Now())
+
+	query, args, err := q.counter.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("building count query for table: %s: %w", signatureFlagsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, signatureFlagsTable, "count", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	var count uint64
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		err = tx.QueryRowContext(ctx, query, args...).Scan(&count)
+	} else {
+		err = q.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	}
+
+	return count, err
+}
+
+func (q SignatureFlagsQ) FilterIDs(ids ...uuid.UUID) SignatureFlagsQ {
+	q.selector = q.selector.Where(sq.Eq{"id": ids})
+	q.counter = q.counter.Where(sq.Eq{"id": ids})
+	q.updater = q.updater.Where(sq.Eq{"id": ids})
+	q.deleter = q.deleter.Where(sq.Eq{"id": ids})
+
+	return q
+}
+
+func (q SignatureFlagsQ) FilterSignatureIDs(signatureIDs ...uuid.UUID) SignatureFlagsQ {
+	q.selector = q.selector.Where(sq.Eq{"signature_id": signatureIDs})
+	q.counter = q.counter.Where(sq.Eq{"signature_id": signatureIDs})
+	q.updater = q.updater.Where(sq.Eq{"signature_id": signatureIDs})
+	q.deleter = q.deleter.Where(sq.Eq{"signature_id": signatureIDs})
+
+	return q
+}
+
+func (q SignatureFlagsQ) FilterPetitionIDs(petitionIDs ...uuid.UUID) SignatureFlagsQ {
+	q.selector = q.selector.Where(sq.Eq{"petition_id": petitionIDs})
+	q.counter = q.counter.Where(sq.Eq{"petition_id": petitionIDs})
+	q.updater = q.updater.Where(sq.Eq{"petition_id": petitionIDs})
+	q.deleter = q.deleter.Where(sq.Eq{"petition_id": petitionIDs})
+
+	return q
+}
+
+func (q SignatureFlagsQ) FilterPetitionID(petitionID uuid.UUID) SignatureFlagsQ {
+	q.selector = q.selector.Where(sq.Eq{"petition_id": petitionID})
+	q.counter = q.counter.Where(sq.Eq{"petition_id": petitionID})
+	q.updater = q.updater.Where(sq.Eq{"petition_id": petitionID})
+	q.deleter = q.deleter.Where(sq.Eq{"petition_id": petitionID})
+
+	return q
+}
+
+// FilterCityID keeps flags of petitions addressed to cityID.
+func (q SignatureFlagsQ) FilterCityID(cityID uuid.UUID) SignatureFlagsQ {
+	cond := sq.Expr("petition_id IN (SELECT id FROM "+petitionsTable+" WHERE city_id = ?)", cityID)
+
+	q.selector = q.selector.Where(cond)
+	q.counter = q.counter.Where(cond)
+	q.updater = q.updater.Where(cond)
+	q.deleter = q.deleter.Where(cond)
+
+	return q
+}
+
+func (q SignatureFlagsQ) FilterStatus(status string) SignatureFlagsQ {
+	q.selector = q.selector.Where(sq.Eq{"status": status})
+	q.counter = q.counter.Where(sq.Eq{"status": status})
+	q.updater = q.updater.Where(sq.Eq{"status": status})
+	q.deleter = q.deleter.Where(sq.Eq{"status": status})
+
+	return q
+}
+
+func (q SignatureFlagsQ) FilterReviewedBy(userID uuid.UUID) SignatureFlagsQ {
+	q.selector = q.selector.Where(sq.Eq{"reviewed_by": userID})
+	q.counter = q.counter.Where(sq.Eq{"reviewed_by": userID})
+	q.updater = q.updater.Where(sq.Eq{"reviewed_by": userID})
+	q.deleter = q.deleter.Where(sq.Eq{"reviewed_by": userID})
+
+	return q
+}
+
+func (q SignatureFlagsQ) OrderByCreated(ascending bool) SignatureFlagsQ {
+	if ascending {
+		q.selector = q.selector.OrderBy("created_at ASC")
+	} else {
+		q.selector = q.selector.OrderBy("created_at DESC")
+	}
+
+	return q
+}
+
+func (q SignatureFlagsQ) Page(limit, offset uint64) SignatureFlagsQ {
+	q.selector = q.selector.Limit(limit).Offset(offset)
+
+	return q
+}
@@ -0,0 +1,134 @@
+package dbx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/chains-lab/city-petitions-svc/internal/metrics"
+	"github.com/google/uuid"
+)
+
+const signerSaltsTable = "signer_salts"
+
+// SignerSalt is the random salt of a user's signer hashes. It is deleted on erasure.
+type SignerSalt struct {
+	UserID    uuid.UUID `db:"user_id"`
+	Salt      []byte    `db:"salt"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type SignerSaltsQ struct {
+	db       *sql.DB
+	selector sq.SelectBuilder
+	inserter sq.InsertBuilder
+	deleter  sq.DeleteBuilder
+}
+
+func NewSignerSaltsQ(db *sql.DB) SignerSaltsQ {
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	return SignerSaltsQ{
+		db:       db,
+		selector: builder.Select("user_id", "salt", "created_at").From(signerSaltsTable),
+		inserter: builder.Insert(signerSaltsTable),
+		deleter:  builder.Delete(signerSaltsTable),
+	}
+}
+
+func (q SignerSaltsQ) New() SignerSaltsQ {
+	return NewSignerSaltsQ(q.db)
+}
+
+// Insert stores salts, skipping users who already have one.
+func (q SignerSaltsQ) Insert(ctx context.Context, salts ...SignerSalt) error {
+	defer metrics.ObserveDBQuery(signerSaltsTable, "insert", time.Now())
+
+	if len(salts) == 0 {
+		return nil
+	}
+
+	inserter := q.inserter.Columns("user_id", "salt", "created_at")
+	for _, s := range salts {
+		inserter = inserter.Values(s.UserID, s.Salt, s.CreatedAt)
+	}
+
+	query, args, err := inserter.Suffix("ON CONFLICT (user_id) DO NOTHING").ToSql()
+	if err != nil {
+		return fmt.Errorf("building inserter query for table: %s: %w", signerSaltsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, signerSaltsTable, "insert", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = q.db.ExecContext(ctx, query, args...)
+	}
+
+	return err
+}
+
+func (q SignerSaltsQ) Select(ctx context.Context) ([]SignerSalt, error) {
+	defer metrics.ObserveDBQuery(signerSaltsTable, "select", time.Now())
+
+	query, args, err := q.selector.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building selector query for table: %s: %w", signerSaltsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, signerSaltsTable, "select", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	var rows *sql.Rows
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		rows, err = tx.QueryContext(ctx, query, args...)
+	} else {
+		rows, err = q.db.QueryContext(ctx, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []SignerSalt
+	for rows.Next() {
+		var s SignerSalt
+		if err = rows.Scan(&s.UserID, &s.Salt, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+
+	return out, rows.Err()
+}
+
+func (q SignerSaltsQ) Delete(ctx context.Context) error {
+	defer metrics.ObserveDBQuery(signerSaltsTable, "delete", time.Now())
+
+	query, args, err := q.deleter.ToSql()
+	if err != nil {
+		return fmt.Errorf("building deleter query for table: %s: %w", signerSaltsTable, err)
+	}
+
+	ctx, span := startQuerySpan(ctx, signerSaltsTable, "delete", query)
+	defer func() { endQuerySpan(span, err) }()
+
+	if tx, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = q.db.ExecContext(ctx, query, args...)
+	}
+
+	return err
+}
+
+func (q SignerSaltsQ) FilterUserID(userIDs ...uuid.UUID) SignerSaltsQ {
+	q.selector = q.selector.Where(sq.Eq{"user_id": userIDs})
+	q.deleter = q.deleter.Where(sq.Eq{"user_id": userIDs})
+
+	return q
+}
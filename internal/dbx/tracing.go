@@ -0,0 +1,30 @@
+package dbx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/chains-lab/city-petitions-svc/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startQuerySpan opens a span for a single statement against table.
+// The span must be finished with endQuerySpan.
+func startQuerySpan(ctx context.Context, table, operation, query string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "dbx."+table+"."+operation,
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.collection.name", table),
+		attribute.String("db.operation.name", operation),
+		attribute.String("db.query.text", query),
+	)
+}
+
+// endQuerySpan finishes span; sql.ErrNoRows is an expected outcome and is not recorded as a failure.
+func endQuerySpan(span trace.Span, err error) {
+	if !errors.Is(err, sql.ErrNoRows) {
+		tracing.Fail(span, err)
+	}
+	span.End()
+}
@@ -1,6 +1,7 @@
 package dbx
 
 import (
+	"context"
 	"database/sql"
 	"embed"
 
@@ -14,6 +15,34 @@ type txKeyType struct{}
 
 var TxKey = txKeyType{}
 
+// Transaction runs fn with a transaction stored under TxKey in its context.
+// The transaction is committed when fn returns nil and rolled back otherwise.
+// When ctx already carries a transaction, fn joins it.
+func Transaction(ctx context.Context, db *sql.DB, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(TxKey).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+
+	if err := fn(context.WithValue(ctx, TxKey, tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return errors.Wrapf(err, "rollback failed: %v", rbErr)
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return nil
+}
+
 //go:embed migrations/*.sql
 var Migrations embed.FS
 
@@ -43,3 +72,17 @@ func MigrateDown(cfg config.Config) error {
 	logrus.WithField("applied", applied).Info("migrations applied")
 	return nil
 }
+
+// CheckMigrations returns an error when embedded migrations are not all applied to db.
+func CheckMigrations(db *sql.DB) error {
+	pending, _, err := migrate.PlanMigration(db, "postgres", migrations, migrate.Up, 0)
+	if err != nil {
+		return errors.Wrap(err, "failed to plan migrations")
+	}
+
+	if len(pending) > 0 {
+		return errors.Errorf("%d migrations are not applied, next is %s", len(pending), pending[0].Id)
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package errx
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/constant"
+	"github.com/chains-lab/svc-errors/ape"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrorCommentNotFound = ape.Declare("COMMENT_NOT_FOUND")
+
+func RaiseCommentNotFoundByID(ctx context.Context, cause error, commentID uuid.UUID) error {
+	st := status.New(codes.NotFound, fmt.Sprintf("Comment with id '%s' not found", commentID))
+	st, _ = st.WithDetails(
+		&errdetails.ErrorInfo{
+			Reason: ErrorCommentNotFound.Error(),
+			Domain: constant.ServiceName,
+			Metadata: map[string]string{
+				"timestamp": nowRFC3339Nano(),
+			},
+		},
+		&errdetails.RequestInfo{
+			RequestId: meta.RequestID(ctx),
+		},
+	)
+
+	return ErrorCommentNotFound.Raise(cause, st)
+}
+
+var ErrorNotCommentAuthor = ape.Declare("NOT_COMMENT_AUTHOR")
+
+func RaiseNotCommentAuthor(ctx context.Context, cause error, commentID, userID uuid.UUID) error {
+	st := status.New(codes.PermissionDenied, fmt.Sprintf("User '%s' is not the author of comment '%s'", userID, commentID))
+	st, _ = st.WithDetails(
+		&errdetails.ErrorInfo{
+			Reason: ErrorNotCommentAuthor.Error(),
+			Domain: constant.ServiceName,
+			Metadata: map[string]string{
+				"comment_id": commentID.String(),
+				"user_id":    userID.String(),
+				"timestamp":  nowRFC3339Nano(),
+			},
+		},
+		&errdetails.RequestInfo{
+			RequestId: meta.RequestID(ctx),
+		},
+	)
+
+	return ErrorNotCommentAuthor.Raise(cause, st)
+}
+
+var ErrorCommentEditWindowExpired = ape.Declare("COMMENT_EDIT_WINDOW_EXPIRED")
+
+func RaiseCommentEditWindowExpired(ctx context.Context, cause error, commentID uuid.UUID, window time.Duration) error {
+	st := status.New(codes.FailedPrecondition, fmt.Sprintf("Comment with id '%s' can only be edited within %s of posting", commentID, window))
+	st, _ = st.WithDetails(
+		&errdetails.ErrorInfo{
+			Reason: ErrorCommentEditWindowExpired.Error(),
+			Domain: constant.ServiceName,
+			Metadata: map[string]string{
+				"comment_id":  commentID.String(),
+				"edit_window": window.String(),
+				"timestamp":   nowRFC3339Nano(),
+			},
+		},
+		&errdetails.RequestInfo{
+			RequestId: meta.RequestID(ctx),
+		},
+	)
+
+	return ErrorCommentEditWindowExpired.Raise(cause, st)
+}
+
+var ErrorCommentThreadTooDeep = ape.Declare("COMMENT_THREAD_TOO_DEEP")
+
+func RaiseCommentThreadTooDeep(ctx context.Context, cause error, parentID uuid.UUID, maxDepth int) error {
+	st := status.New(codes.FailedPrecondition, fmt.Sprintf("Comment with id '%s' can not be replied to, threads are at most %d replies deep", parentID, maxDepth))
+	st, _ = st.WithDetails(
+		&errdetails.ErrorInfo{
+			Reason: ErrorCommentThreadTooDeep.Error(),
+			Domain: constant.ServiceName,
+			Metadata: map[string]string{
+				"parent_id": parentID.String(),
+				"max_depth": strconv.Itoa(maxDepth),
+				"timestamp": nowRFC3339Nano(),
+			},
+		},
+		&errdetails.RequestInfo{
+			RequestId: meta.RequestID(ctx),
+		},
+	)
+
+	return ErrorCommentThreadTooDeep.Raise(cause, st)
+}
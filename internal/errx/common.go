@@ -69,3 +69,23 @@ func RaiseUnauthenticated(ctx context.Context, cause error) error {
 		res,
 	)
 }
+
+var ErrorNoPermission = ape.Declare("NO_PERMISSION")
+
+func RaiseNoPermission(ctx context.Context, cause error, userID uuid.UUID, permission string, cityID uuid.UUID) error {
+	msg := fmt.Sprintf("user %s has no permission %s in city %s", userID, permission, cityID)
+	st := status.New(codes.PermissionDenied, msg)
+	st, _ = st.WithDetails(
+		&errdetails.ErrorInfo{
+			Reason: ErrorNoPermission.Error(),
+			Domain: constant.ServiceName,
+			Metadata: map[string]string{
+				"permission": permission,
+				"city_id":    cityID.String(),
+				"timestamp":  nowRFC3339Nano(),
+			},
+		},
+		&errdetails.RequestInfo{RequestId: meta.RequestID(ctx)},
+	)
+	return ErrorNoPermission.Raise(cause, st)
+}
@@ -0,0 +1,67 @@
+package errx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/constant"
+	"github.com/chains-lab/city-petitions-svc/internal/contentfilter"
+	"github.com/chains-lab/svc-errors/ape"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrorContentFlagNotFound = ape.Declare("CONTENT_FLAG_NOT_FOUND")
+
+func RaiseContentFlagNotFoundByID(ctx context.Context, cause error, flagID uuid.UUID) error {
+	st := status.New(codes.NotFound, fmt.Sprintf("Content flag with ID '%s' not found", flagID))
+	st, _ = st.WithDetails(
+		&errdetails.ErrorInfo{
+			Reason: ErrorContentFlagNotFound.Error(),
+			Domain: constant.ServiceName,
+			Metadata: map[string]string{
+				"timestamp": nowRFC3339Nano(),
+			},
+		},
+		&errdetails.RequestInfo{
+			RequestId: meta.RequestID(ctx),
+		},
+	)
+
+	return ErrorContentFlagNotFound.Raise(cause, st)
+}
+
+var ErrorContentRejected = ape.Declare("CONTENT_REJECTED")
+
+// RaiseContentRejected lists every match of the content filter as a field violation.
+func RaiseContentRejected(ctx context.Context, cause error, matches []contentfilter.Match) error {
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(matches))
+	for _, m := range matches {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       m.Field,
+			Description: fmt.Sprintf("%s: %s", m.Filter, m.Details),
+		})
+	}
+
+	st := status.New(codes.InvalidArgument, "Content was rejected by the content filter")
+	st, _ = st.WithDetails(
+		&errdetails.ErrorInfo{
+			Reason: ErrorContentRejected.Error(),
+			Domain: constant.ServiceName,
+			Metadata: map[string]string{
+				"timestamp": nowRFC3339Nano(),
+			},
+		},
+		&errdetails.BadRequest{
+			FieldViolations: violations,
+		},
+		&errdetails.RequestInfo{
+			RequestId: meta.RequestID(ctx),
+		},
+	)
+
+	return ErrorContentRejected.Raise(cause, st)
+}
@@ -3,15 +3,18 @@ package errx
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/app/models"
 	"github.com/chains-lab/city-petitions-svc/internal/constant"
 	"github.com/chains-lab/svc-errors/ape"
 	"github.com/google/uuid"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 func nowRFC3339Nano() string {
@@ -57,3 +60,85 @@ func RaisePetitionIsNotAvailable(ctx context.Context, cause error, petitionID st
 
 	return ErrorPetitionIsNotAvailable.Raise(cause, st)
 }
+
+var ErrorPetitionDailyLimitExceeded = ape.Declare("PETITION_DAILY_LIMIT_EXCEEDED")
+
+func RaisePetitionDailyLimitExceeded(ctx context.Context, cause error, userID uuid.UUID, limit int, retryAfter time.Duration) error {
+	st := status.New(codes.ResourceExhausted, fmt.Sprintf("user '%s' has reached the limit of %d petitions per day", userID, limit))
+	st, _ = st.WithDetails(
+		&errdetails.ErrorInfo{
+			Reason: ErrorPetitionDailyLimitExceeded.Error(),
+			Domain: constant.ServiceName,
+			Metadata: map[string]string{
+				"user_id":   userID.String(),
+				"limit":     strconv.Itoa(limit),
+				"timestamp": nowRFC3339Nano(),
+			},
+		},
+		&errdetails.RetryInfo{
+			RetryDelay: durationpb.New(retryAfter),
+		},
+		&errdetails.RequestInfo{
+			RequestId: meta.RequestID(ctx),
+		},
+	)
+
+	return ErrorPetitionDailyLimitExceeded.Raise(cause, st)
+}
+
+var ErrorPetitionDuplicatesFound = ape.Declare("PETITION_DUPLICATES_FOUND")
+
+// RaisePetitionDuplicatesFound lists the similar open petitions as precondition violations.
+// The author may create the petition anyway by repeating the request with force set.
+func RaisePetitionDuplicatesFound(ctx context.Context, cause error, candidates []models.DuplicateCandidate) error {
+	violations := make([]*errdetails.PreconditionFailure_Violation, 0, len(candidates))
+	for _, c := range candidates {
+		violations = append(violations, &errdetails.PreconditionFailure_Violation{
+			Type:        "DUPLICATE_PETITION",
+			Subject:     c.Petition.ID.String(),
+			Description: fmt.Sprintf("'%s' is %.0f%% similar", c.Petition.Title, c.Similarity*100),
+		})
+	}
+
+	st := status.New(codes.FailedPrecondition, "Similar open petitions already exist, set force to create the petition anyway")
+	st, _ = st.WithDetails(
+		&errdetails.ErrorInfo{
+			Reason: ErrorPetitionDuplicatesFound.Error(),
+			Domain: constant.ServiceName,
+			Metadata: map[string]string{
+				"candidates": strconv.Itoa(len(candidates)),
+				"timestamp":  nowRFC3339Nano(),
+			},
+		},
+		&errdetails.PreconditionFailure{
+			Violations: violations,
+		},
+		&errdetails.RequestInfo{
+			RequestId: meta.RequestID(ctx),
+		},
+	)
+
+	return ErrorPetitionDuplicatesFound.Raise(cause, st)
+}
+
+var ErrorPetitionsNotMergeable = ape.Declare("PETITIONS_NOT_MERGEABLE")
+
+// RaisePetitionsNotMergeable reports the petition that prevents a merge and why.
+func RaisePetitionsNotMergeable(ctx context.Context, cause error, petitionID uuid.UUID, reason string) error {
+	st := status.New(codes.FailedPrecondition, fmt.Sprintf("Petition with id '%s' can not be merged: %s", petitionID, reason))
+	st, _ = st.WithDetails(
+		&errdetails.ErrorInfo{
+			Reason: ErrorPetitionsNotMergeable.Error(),
+			Domain: constant.ServiceName,
+			Metadata: map[string]string{
+				"petition_id": petitionID.String(),
+				"timestamp":   nowRFC3339Nano(),
+			},
+		},
+		&errdetails.RequestInfo{
+			RequestId: meta.RequestID(ctx),
+		},
+	)
+
+	return ErrorPetitionsNotMergeable.Raise(cause, st)
+}
@@ -0,0 +1,149 @@
+package errx
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/constant"
+	"github.com/chains-lab/svc-errors/ape"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrorPetitionAuthorNotFound = ape.Declare("PETITION_AUTHOR_NOT_FOUND")
+
+func RaisePetitionAuthorNotFound(ctx context.Context, cause error, petitionID, userID uuid.UUID) error {
+	st := status.New(codes.NotFound, fmt.Sprintf("User '%s' is not invited to author petition '%s'", userID, petitionID))
+	st, _ = st.WithDetails(
+		&errdetails.ErrorInfo{
+			Reason: ErrorPetitionAuthorNotFound.Error(),
+			Domain: constant.ServiceName,
+			Metadata: map[string]string{
+				"petition_id": petitionID.String(),
+				"user_id":     userID.String(),
+				"timestamp":   nowRFC3339Nano(),
+			},
+		},
+		&errdetails.RequestInfo{
+			RequestId: meta.RequestID(ctx),
+		},
+	)
+
+	return ErrorPetitionAuthorNotFound.Raise(cause, st)
+}
+
+var ErrorPetitionAuthorAlreadyExists = ape.Declare("PETITION_AUTHOR_ALREADY_EXISTS")
+
+func RaisePetitionAuthorAlreadyExists(ctx context.Context, cause error, petitionID, userID uuid.UUID) error {
+	st := status.New(codes.AlreadyExists, fmt.Sprintf("User '%s' is already an author of petition '%s' or invited to it", userID, petitionID))
+	st, _ = st.WithDetails(
+		&errdetails.ErrorInfo{
+			Reason: ErrorPetitionAuthorAlreadyExists.Error(),
+			Domain: constant.ServiceName,
+			Metadata: map[string]string{
+				"petition_id": petitionID.String(),
+				"user_id":     userID.String(),
+				"timestamp":   nowRFC3339Nano(),
+			},
+		},
+		&errdetails.RequestInfo{
+			RequestId: meta.RequestID(ctx),
+		},
+	)
+
+	return ErrorPetitionAuthorAlreadyExists.Raise(cause, st)
+}
+
+var ErrorNotPetitionAuthor = ape.Declare("NOT_PETITION_AUTHOR")
+
+// RaiseNotPetitionAuthor is returned when a user acts on a petition they do not author,
+// or on behalf of its creator without being the creator.
+func RaiseNotPetitionAuthor(ctx context.Context, cause error, petitionID, userID uuid.UUID) error {
+	st := status.New(codes.PermissionDenied, fmt.Sprintf("User '%s' may not do this on petition '%s'", userID, petitionID))
+	st, _ = st.WithDetails(
+		&errdetails.ErrorInfo{
+			Reason: ErrorNotPetitionAuthor.Error(),
+			Domain: constant.ServiceName,
+			Metadata: map[string]string{
+				"petition_id": petitionID.String(),
+				"user_id":     userID.String(),
+				"timestamp":   nowRFC3339Nano(),
+			},
+		},
+		&errdetails.RequestInfo{
+			RequestId: meta.RequestID(ctx),
+		},
+	)
+
+	return ErrorNotPetitionAuthor.Raise(cause, st)
+}
+
+var ErrorPetitionIsNotDraft = ape.Declare("PETITION_IS_NOT_DRAFT")
+
+func RaisePetitionIsNotDraft(ctx context.Context, cause error, petitionID uuid.UUID) error {
+	st := status.New(codes.FailedPrecondition, fmt.Sprintf("Petition with id '%s' is not a draft any more", petitionID))
+	st, _ = st.WithDetails(
+		&errdetails.ErrorInfo{
+			Reason: ErrorPetitionIsNotDraft.Error(),
+			Domain: constant.ServiceName,
+			Metadata: map[string]string{
+				"petition_id": petitionID.String(),
+				"timestamp":   nowRFC3339Nano(),
+			},
+		},
+		&errdetails.RequestInfo{
+			RequestId: meta.RequestID(ctx),
+		},
+	)
+
+	return ErrorPetitionIsNotDraft.Raise(cause, st)
+}
+
+var ErrorNotEnoughCoAuthors = ape.Declare("NOT_ENOUGH_CO_AUTHORS")
+
+func RaiseNotEnoughCoAuthors(ctx context.Context, cause error, petitionID uuid.UUID, required, accepted int) error {
+	st := status.New(codes.FailedPrecondition, fmt.Sprintf("Petition with id '%s' needs %d accepted co-authors to be published, it has %d", petitionID, required, accepted))
+	st, _ = st.WithDetails(
+		&errdetails.ErrorInfo{
+			Reason: ErrorNotEnoughCoAuthors.Error(),
+			Domain: constant.ServiceName,
+			Metadata: map[string]string{
+				"petition_id": petitionID.String(),
+				"required":    strconv.Itoa(required),
+				"accepted":    strconv.Itoa(accepted),
+				"timestamp":   nowRFC3339Nano(),
+			},
+		},
+		&errdetails.RequestInfo{
+			RequestId: meta.RequestID(ctx),
+		},
+	)
+
+	return ErrorNotEnoughCoAuthors.Raise(cause, st)
+}
+
+var ErrorTooManyCoAuthors = ape.Declare("TOO_MANY_CO_AUTHORS")
+
+func RaiseTooManyCoAuthors(ctx context.Context, cause error, petitionID uuid.UUID, limit int) error {
+	st := status.New(codes.FailedPrecondition, fmt.Sprintf("Petition with id '%s' can not have more than %d co-authors", petitionID, limit))
+	st, _ = st.WithDetails(
+		&errdetails.ErrorInfo{
+			Reason: ErrorTooManyCoAuthors.Error(),
+			Domain: constant.ServiceName,
+			Metadata: map[string]string{
+				"petition_id": petitionID.String(),
+				"limit":       strconv.Itoa(limit),
+				"timestamp":   nowRFC3339Nano(),
+			},
+		},
+		&errdetails.RequestInfo{
+			RequestId: meta.RequestID(ctx),
+		},
+	)
+
+	return ErrorTooManyCoAuthors.Raise(cause, st)
+}
@@ -0,0 +1,34 @@
+package errx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/constant"
+	"github.com/chains-lab/svc-errors/ape"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrorSignatureFlagNotFound = ape.Declare("SIGNATURE_FLAG_NOT_FOUND")
+
+func RaiseSignatureFlagNotFoundByID(ctx context.Context, cause error, flagID uuid.UUID) error {
+	st := status.New(codes.NotFound, fmt.Sprintf("Signature flag with ID '%s' not found", flagID))
+	st, _ = st.WithDetails(
+		&errdetails.ErrorInfo{
+			Reason: ErrorSignatureFlagNotFound.Error(),
+			Domain: constant.ServiceName,
+			Metadata: map[string]string{
+				"timestamp": nowRFC3339Nano(),
+			},
+		},
+		&errdetails.RequestInfo{
+			RequestId: meta.RequestID(ctx),
+		},
+	)
+
+	return ErrorSignatureFlagNotFound.Raise(cause, st)
+}
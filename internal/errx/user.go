@@ -0,0 +1,65 @@
+package errx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chains-lab/city-petitions-svc/internal/api/grpc/meta"
+	"github.com/chains-lab/city-petitions-svc/internal/constant"
+	"github.com/chains-lab/svc-errors/ape"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrorUserNotVerified = ape.Declare("USER_NOT_VERIFIED")
+
+func RaiseUserNotVerified(ctx context.Context, cause error, userID uuid.UUID, action string) error {
+	st := status.New(codes.PermissionDenied, fmt.Sprintf("User '%s' must be verified to %s petition", userID, action))
+	st, _ = st.WithDetails(
+		&errdetails.ErrorInfo{
+			Reason: ErrorUserNotVerified.Error(),
+			Domain: constant.ServiceName,
+			Metadata: map[string]string{
+				"user_id":   userID.String(),
+				"action":    action,
+				"timestamp": nowRFC3339Nano(),
+			},
+		},
+		&errdetails.PreconditionFailure{
+			Violations: []*errdetails.PreconditionFailure_Violation{{
+				Type:        "USER_VERIFICATION",
+				Subject:     userID.String(),
+				Description: fmt.Sprintf("account verification is required to %s petition", action),
+			}},
+		},
+		&errdetails.RequestInfo{
+			RequestId: meta.RequestID(ctx),
+		},
+	)
+
+	return ErrorUserNotVerified.Raise(cause, st)
+}
+
+var ErrorUserNotResident = ape.Declare("USER_NOT_RESIDENT")
+
+func RaiseUserNotResident(ctx context.Context, cause error, userID, cityID uuid.UUID) error {
+	st := status.New(codes.PermissionDenied, fmt.Sprintf("User '%s' is not a resident of city '%s'", userID, cityID))
+	st, _ = st.WithDetails(
+		&errdetails.ErrorInfo{
+			Reason: ErrorUserNotResident.Error(),
+			Domain: constant.ServiceName,
+			Metadata: map[string]string{
+				"user_id":   userID.String(),
+				"city_id":   cityID.String(),
+				"timestamp": nowRFC3339Nano(),
+			},
+		},
+		&errdetails.RequestInfo{
+			RequestId: meta.RequestID(ctx),
+		},
+	)
+
+	return ErrorUserNotResident.Raise(cause, st)
+}
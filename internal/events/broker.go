@@ -0,0 +1,124 @@
+package events
+
+import (
+	"sync"
+
+	"github.com/chains-lab/city-petitions-svc/internal/app/models"
+	"github.com/google/uuid"
+)
+
+// Broker fans petition updates out to in-process subscribers.
+type Broker struct {
+	mu   sync.RWMutex
+	subs map[uuid.UUID]map[*Subscription]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		subs: make(map[uuid.UUID]map[*Subscription]struct{}),
+	}
+}
+
+func (b *Broker) Subscribe(petitionIDs ...uuid.UUID) *Subscription {
+	sub := &Subscription{
+		broker:  b,
+		ids:     petitionIDs,
+		pending: make(map[uuid.UUID]models.PetitionUpdate),
+		ready:   make(chan struct{}, 1),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, id := range petitionIDs {
+		if b.subs[id] == nil {
+			b.subs[id] = make(map[*Subscription]struct{})
+		}
+		b.subs[id][sub] = struct{}{}
+	}
+
+	return sub
+}
+
+// Publish never blocks: every subscriber keeps only the latest update per petition,
+// so a slow stream skips intermediate counts instead of holding the broker back.
+func (b *Broker) Publish(update models.PetitionUpdate) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subs[update.PetitionID] {
+		sub.push(update)
+	}
+}
+
+func (b *Broker) unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, id := range sub.ids {
+		delete(b.subs[id], sub)
+		if len(b.subs[id]) == 0 {
+			delete(b.subs, id)
+		}
+	}
+}
+
+type Subscription struct {
+	broker *Broker
+	ids    []uuid.UUID
+
+	mu      sync.Mutex
+	pending map[uuid.UUID]models.PetitionUpdate
+	ready   chan struct{}
+	closed  bool
+}
+
+// Ready is signalled when Drain has updates to return.
+func (s *Subscription) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Drain returns and clears the pending updates.
+func (s *Subscription) Drain() []models.PetitionUpdate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]models.PetitionUpdate, 0, len(s.pending))
+	for id, update := range s.pending {
+		out = append(out, update)
+		delete(s.pending, id)
+	}
+
+	return out
+}
+
+func (s *Subscription) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	s.broker.unsubscribe(s)
+}
+
+func (s *Subscription) push(update models.PetitionUpdate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	if prev, ok := s.pending[update.PetitionID]; ok && prev.UpdatedAt.After(update.UpdatedAt) {
+		return
+	}
+	s.pending[update.PetitionID] = update
+
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,94 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/chains-lab/city-petitions-svc/internal/app/models"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// PetitionUpdatesChannel is the NOTIFY channel fired by the petitions update trigger.
+const PetitionUpdatesChannel = "petition_updates"
+
+const (
+	listenerMinReconnect = 1 * time.Second
+	listenerMaxReconnect = 30 * time.Second
+	listenerPingInterval = 90 * time.Second
+)
+
+type petitionUpdatePayload struct {
+	PetitionID uuid.UUID  `json:"petition_id"`
+	Signatures int        `json:"signatures"`
+	Status     string     `json:"status"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	MergedInto *uuid.UUID `json:"merged_into"`
+}
+
+// Listener forwards Postgres notifications on PetitionUpdatesChannel into a Broker.
+type Listener struct {
+	url    string
+	broker *Broker
+}
+
+func NewListener(url string, broker *Broker) *Listener {
+	return &Listener{
+		url:    url,
+		broker: broker,
+	}
+}
+
+func (l *Listener) Run(ctx context.Context, log logger.Logger) error {
+	listener := pq.NewListener(l.url, listenerMinReconnect, listenerMaxReconnect, func(ev pq.ListenerEventType, err error) {
+		switch ev {
+		case pq.ListenerEventConnectionAttemptFailed, pq.ListenerEventDisconnected:
+			log.WithError(err).Warn("petition updates listener lost connection")
+		case pq.ListenerEventReconnected:
+			log.Info("petition updates listener reconnected, updates sent meanwhile were missed")
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(PetitionUpdatesChannel); err != nil {
+		return fmt.Errorf("listening on %s: %w", PetitionUpdatesChannel, err)
+	}
+
+	log.Infof("listening for petition updates on %s", PetitionUpdatesChannel)
+
+	ticker := time.NewTicker(listenerPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n := <-listener.Notify:
+			if n == nil {
+				// connection was re-established, nothing to forward
+				continue
+			}
+
+			var payload petitionUpdatePayload
+			if err := json.Unmarshal([]byte(n.Extra), &payload); err != nil {
+				log.WithError(err).Errorf("invalid petition update payload: %s", n.Extra)
+				continue
+			}
+
+			l.broker.Publish(models.PetitionUpdate{
+				PetitionID: payload.PetitionID,
+				Signatures: payload.Signatures,
+				Status:     payload.Status,
+				UpdatedAt:  payload.UpdatedAt,
+				MergedInto: payload.MergedInto,
+			})
+		case <-ticker.C:
+			if err := listener.Ping(); err != nil {
+				log.WithError(err).Warn("petition updates listener ping failed")
+			}
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chains-lab/city-petitions-svc/internal/config"
+	"github.com/chains-lab/city-petitions-svc/internal/dbx"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+	"github.com/google/uuid"
+)
+
+const (
+	StatusPending   = "pending"
+	StatusCompleted = "completed"
+
+	defaultTTL            = 24 * time.Hour
+	defaultPendingTimeout = time.Minute
+	defaultPurgeInterval  = time.Hour
+)
+
+var (
+	// ErrMismatch means the key was already used with a different request.
+	ErrMismatch = errors.New("idempotency key reused with a different request")
+	// ErrInProgress means the first request with the key has not finished yet.
+	ErrInProgress = errors.New("request with the idempotency key is in progress")
+)
+
+// Record is a stored response of a completed request.
+type Record struct {
+	ResponseType string
+	Response     []byte
+}
+
+// Store keeps idempotency keys of users in the database.
+type Store struct {
+	keys           dbx.IdempotencyKeysQ
+	ttl            time.Duration
+	pendingTimeout time.Duration
+	purgeInterval  time.Duration
+}
+
+func New(cfg config.Config, db *sql.DB) *Store {
+	ttl := cfg.Idempotency.TTL
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+
+	pendingTimeout := cfg.Idempotency.PendingTimeout
+	if pendingTimeout <= 0 {
+		pendingTimeout = defaultPendingTimeout
+	}
+
+	purgeInterval := cfg.Idempotency.PurgeInterval
+	if purgeInterval <= 0 {
+		purgeInterval = defaultPurgeInterval
+	}
+
+	return &Store{
+		keys:           dbx.NewIdempotencyKeysQ(db),
+		ttl:            ttl,
+		pendingTimeout: pendingTimeout,
+		purgeInterval:  purgeInterval,
+	}
+}
+
+// Begin claims key of userID for method and a request with requestHash.
+// It returns nil when the caller should handle the request and Complete or Release the key afterwards,
+// the stored response when an identical request already completed,
+// ErrMismatch when the key was used for another request and ErrInProgress when it is still being handled.
+// Expired keys and pending keys older than the pending timeout are taken over.
+func (s *Store) Begin(ctx context.Context, userID uuid.UUID, method, key string, requestHash []byte) (*Record, error) {
+	now := time.Now().UTC()
+
+	err := s.keys.New().FilterKey(userID, method, key).FilterExpiresAt(now, false).Delete(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("deleting expired idempotency key: %w", err)
+	}
+
+	err = s.keys.New().
+		FilterKey(userID, method, key).
+		FilterStatus(StatusPending).
+		FilterCreatedAt(now.Add(-s.pendingTimeout), false).
+		Delete(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("deleting abandoned idempotency key: %w", err)
+	}
+
+	inserted, err := s.keys.New().Insert(ctx, dbx.IdempotencyKey{
+		UserID:      userID,
+		Method:      method,
+		Key:         key,
+		RequestHash: requestHash,
+		Status:      StatusPending,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.ttl),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("inserting idempotency key: %w", err)
+	}
+	if inserted {
+		return nil, nil
+	}
+
+	stored, err := s.keys.New().FilterKey(userID, method, key).Get(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		// The holder released the key between our insert and select.
+		return nil, ErrInProgress
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting idempotency key: %w", err)
+	}
+
+	if !bytes.Equal(stored.RequestHash, requestHash) {
+		return nil, ErrMismatch
+	}
+
+	if stored.Status != StatusCompleted {
+		return nil, ErrInProgress
+	}
+
+	return &Record{
+		ResponseType: stored.ResponseType,
+		Response:     stored.Response,
+	}, nil
+}
+
+// Complete stores the response of the request that claimed the key.
+func (s *Store) Complete(ctx context.Context, userID uuid.UUID, method, key string, record Record) error {
+	status := StatusCompleted
+
+	err := s.keys.New().FilterKey(userID, method, key).FilterStatus(StatusPending).Update(ctx, dbx.UpdateIdempotencyKeyInput{
+		Status:       &status,
+		ResponseType: &record.ResponseType,
+		Response:     record.Response,
+	})
+	if err != nil {
+		return fmt.Errorf("completing idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// Release frees a pending key after a failed request, so the client can retry it.
+func (s *Store) Release(ctx context.Context, userID uuid.UUID, method, key string) error {
+	err := s.keys.New().FilterKey(userID, method, key).FilterStatus(StatusPending).Delete(ctx)
+	if err != nil {
+		return fmt.Errorf("releasing idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// Purge deletes every expired key. Begin only takes over the key it is asked for, so keys that are
+// never reused would otherwise stay forever.
+func (s *Store) Purge(ctx context.Context) error {
+	err := s.keys.New().FilterExpiresAt(time.Now().UTC(), false).Delete(ctx)
+	if err != nil {
+		return fmt.Errorf("deleting expired idempotency keys: %w", err)
+	}
+
+	return nil
+}
+
+// Run purges expired keys right away and then every purge interval until ctx is done. A failed
+// purge is logged and retried on the next tick.
+func (s *Store) Run(ctx context.Context, log logger.Logger) error {
+	ticker := time.NewTicker(s.purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Purge(ctx); err != nil {
+			log.WithError(err).Error("idempotency key purge failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
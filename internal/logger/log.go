@@ -56,6 +56,33 @@ func UnaryLogInterceptor(log Logger) grpc.UnaryServerInterceptor {
 	}
 }
 
+func StreamLogInterceptor(log Logger) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctxWithLog := context.WithValue(
+			ss.Context(),
+			meta.LogCtxKey,
+			log,
+		)
+
+		return handler(srv, &logServerStream{ServerStream: ss, ctx: ctxWithLog})
+	}
+}
+
+// logServerStream подменяет контекст стрима, чтобы логгер дошёл до хэндлера.
+type logServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *logServerStream) Context() context.Context {
+	return s.ctx
+}
+
 func Log(ctx context.Context) Logger {
 	entry, ok := ctx.Value(meta.LogCtxKey).(Logger)
 	if !ok {
@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/chains-lab/city-petitions-svc/internal/constant"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "city_petitions"
+
+// Registry holds every collector of the service; it is exposed by Run.
+var Registry = prometheus.NewRegistry()
+
+var (
+	rpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "handling_seconds",
+		Help:      "Time spent handling gRPC calls.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	rpcHandled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "handled_total",
+		Help:      "gRPC calls completed, by status code.",
+	}, []string{"method", "code"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_seconds",
+		Help:      "Time spent executing database queries.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"table", "operation"})
+
+	petitionsCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "petitions_created_total",
+		Help:      "Petitions created, by city.",
+	}, []string{"city_id"})
+
+	petitionSignatures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "signatures_total",
+		Help:      "Petition signatures, by city.",
+	}, []string{"city_id"})
+
+	petitionsAnswered = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "petitions_answered_total",
+		Help:      "Petitions approved or rejected, by city and resulting status.",
+	}, []string{"city_id", "status"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		rpcDuration,
+		rpcHandled,
+		dbQueryDuration,
+		petitionsCreated,
+		petitionSignatures,
+		petitionsAnswered,
+	)
+}
+
+// RegisterDB exposes connection pool gauges of db.
+func RegisterDB(db *sql.DB) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, constant.ServiceName))
+}
+
+func ObserveRPC(method, code string, start time.Time) {
+	rpcDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	rpcHandled.WithLabelValues(method, code).Inc()
+}
+
+// ObserveDBQuery is meant to be deferred: defer metrics.ObserveDBQuery(table, "select", time.Now()).
+func ObserveDBQuery(table, operation string, start time.Time) {
+	dbQueryDuration.WithLabelValues(table, operation).Observe(time.Since(start).Seconds())
+}
+
+func PetitionCreated(cityID uuid.UUID) {
+	petitionsCreated.WithLabelValues(cityID.String()).Inc()
+}
+
+func PetitionSigned(cityID uuid.UUID) {
+	petitionSignatures.WithLabelValues(cityID.String()).Inc()
+}
+
+func PetitionAnswered(cityID uuid.UUID, status string) {
+	petitionsAnswered.WithLabelValues(cityID.String(), status).Inc()
+}
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/chains-lab/city-petitions-svc/internal/config"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+// Run serves Registry on cfg.Metrics.Port until ctx is done.
+func Run(ctx context.Context, cfg config.Config, log logger.Logger) error {
+	path := cfg.Metrics.Path
+	if path == "" {
+		path = "/metrics"
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET "+path, promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry}))
+
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	lis, err := net.Listen("tcp", cfg.Metrics.Port)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	log.Infof("metrics server listening on %s%s", lis.Addr(), path)
+
+	serveErrCh := make(chan error, 1)
+	go func() {
+		serveErrCh <- srv.Serve(lis)
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		return srv.Shutdown(shutdownCtx)
+	case err := <-serveErrCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("metrics Serve() exited: %w", err)
+	}
+}
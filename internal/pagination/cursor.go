@@ -0,0 +1,76 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// CursorRequest asks for up to Limit items following the position encoded in Cursor,
+// an empty Cursor starts from the beginning.
+type CursorRequest struct {
+	Cursor string `json:"cursor"`
+	Limit  uint64 `json:"limit"`
+}
+
+// CursorResponse carries the cursor of the next page, empty on the last page.
+type CursorResponse struct {
+	NextCursor string `json:"next_cursor"`
+}
+
+// Cursor is a position in a list ordered by creation time and ID.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// Encode returns the opaque form of c handed to clients.
+func (c Cursor) Encode() string {
+	raw := strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + ":" + c.ID.String()
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor produced by Cursor.Encode.
+func DecodeCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return Cursor{CreatedAt: time.Unix(0, n).UTC(), ID: parsed}, nil
+}
+
+// CursorLimit returns the page size of req, defaulting to 10 and capped at max.
+func CursorLimit(req CursorRequest, max uint64) uint64 {
+	limit := req.Limit
+	if limit == 0 {
+		limit = 10
+	}
+	if max > 0 && limit > max {
+		limit = max
+	}
+
+	return limit
+}
@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const sweepInterval = time.Minute
+
+type memoryWindow struct {
+	count   int64
+	resetAt time.Time
+}
+
+// Memory keeps windows in process; each replica enforces its own budget.
+type Memory struct {
+	mu        sync.Mutex
+	windows   map[string]memoryWindow
+	lastSweep time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		windows: make(map[string]memoryWindow),
+	}
+}
+
+func (m *Memory) Hit(_ context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if now.Sub(m.lastSweep) > sweepInterval {
+		m.sweep(now)
+	}
+
+	w, ok := m.windows[key]
+	if !ok || !now.Before(w.resetAt) {
+		w = memoryWindow{resetAt: now.Add(window)}
+	}
+	w.count++
+	m.windows[key] = w
+
+	return w.count, w.resetAt.Sub(now), nil
+}
+
+// sweep drops expired windows so idle users do not pile up. m.mu must be held.
+func (m *Memory) sweep(now time.Time) {
+	for key, w := range m.windows {
+		if !now.Before(w.resetAt) {
+			delete(m.windows, key)
+		}
+	}
+	m.lastSweep = now
+}
@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chains-lab/city-petitions-svc/internal/config"
+	"github.com/google/uuid"
+)
+
+const (
+	BackendRedis  = "redis"
+	BackendMemory = "memory"
+)
+
+// Store counts hits in fixed windows.
+type Store interface {
+	// Hit records one hit under key in the current window of the given length.
+	// It returns the number of hits in the window so far and the time left until it resets.
+	Hit(ctx context.Context, key string, window time.Duration) (count int64, reset time.Duration, err error)
+}
+
+// Rule allows Limit calls per Window.
+type Rule struct {
+	Limit  int64
+	Window time.Duration
+}
+
+// Limiter applies per-method budgets to each user.
+type Limiter struct {
+	store Store
+	rules map[string]Rule // keyed by method name, e.g. "SignPetition"
+}
+
+// New builds the limiter from cfg.RateLimit. A disabled limiter allows every call.
+func New(cfg config.Config) (*Limiter, error) {
+	if !cfg.RateLimit.Enabled {
+		return &Limiter{}, nil
+	}
+
+	var store Store
+	switch cfg.RateLimit.Backend {
+	case BackendRedis, "":
+		store = NewRedis(cfg.Database.Redis.Addr, cfg.Database.Redis.Password, cfg.Database.Redis.DB)
+	case BackendMemory:
+		store = NewMemory()
+	default:
+		return nil, fmt.Errorf("unknown rate limit backend '%s'", cfg.RateLimit.Backend)
+	}
+
+	rules := make(map[string]Rule, len(cfg.RateLimit.Methods))
+	for _, m := range cfg.RateLimit.Methods {
+		if m.Limit <= 0 || m.Window <= 0 {
+			return nil, fmt.Errorf("rate limit for method %s must have positive limit and window", m.Method)
+		}
+		rules[m.Method] = Rule{Limit: m.Limit, Window: m.Window}
+	}
+
+	return &Limiter{store: store, rules: rules}, nil
+}
+
+// Allow records a call of fullMethod by userID.
+// When the budget is spent it returns false and how long the caller should wait.
+// Methods without a rule are always allowed.
+func (l *Limiter) Allow(ctx context.Context, fullMethod string, userID uuid.UUID) (bool, time.Duration, error) {
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+
+	rule, ok := l.rules[method]
+	if !ok {
+		return true, 0, nil
+	}
+
+	count, reset, err := l.store.Hit(ctx, fmt.Sprintf("ratelimit:%s:%s", method, userID), rule.Window)
+	if err != nil {
+		return false, 0, err
+	}
+
+	if count > rule.Limit {
+		return false, reset, nil
+	}
+
+	return true, 0, nil
+}
@@ -0,0 +1,45 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// hitScript increments the window counter and starts its expiry on the first hit.
+var hitScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return {count, redis.call('PTTL', KEYS[1])}
+`)
+
+// Redis keeps windows in Redis, so the budget is shared by all replicas.
+type Redis struct {
+	client *redis.Client
+}
+
+func NewRedis(addr, password string, db int) *Redis {
+	return &Redis{
+		client: redis.NewClient(&redis.Options{
+			Addr:     addr,
+			Password: password,
+			DB:       db,
+		}),
+	}
+}
+
+func (r *Redis) Hit(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	res, err := hitScript.Run(ctx, r.client, []string{key}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(res) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rate limit script result %v", res)
+	}
+
+	return res[0], time.Duration(res[1]) * time.Millisecond, nil
+}
@@ -0,0 +1,260 @@
+package rbac
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/chains-lab/city-petitions-svc/internal/config"
+	"github.com/chains-lab/city-petitions-svc/internal/constant/enum"
+	"github.com/google/uuid"
+)
+
+type Permission string
+
+const (
+	PermissionPetitionModerate Permission = "petition.moderate"
+	PermissionPetitionAnswer   Permission = "petition.answer"
+	PermissionPetitionAdmin    Permission = "petition.admin"
+	// PermissionPolicyManage lets a user read the role and grant mapping. It is never city scoped.
+	PermissionPolicyManage Permission = "policy.manage"
+)
+
+var permissions = []Permission{
+	PermissionPetitionModerate,
+	PermissionPetitionAnswer,
+	PermissionPetitionAdmin,
+	PermissionPolicyManage,
+}
+
+func ParsePermission(p string) (Permission, error) {
+	for _, perm := range permissions {
+		if string(perm) == p {
+			return perm, nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid permission '%s', must be one of: %v", p, permissions)
+}
+
+// defaultRoles is used when config does not define any role mapping.
+var defaultRoles = map[string][]Permission{
+	enum.UserRoleModerator: {PermissionPetitionModerate, PermissionPetitionAnswer},
+	enum.UserRoleAdmin:     {PermissionPetitionModerate, PermissionPetitionAnswer, PermissionPetitionAdmin},
+	enum.UserRoleSuperUser: permissions,
+}
+
+// Subject is who is asking for a permission.
+type Subject struct {
+	UserID uuid.UUID
+	Role   string
+}
+
+// scope holds the cities a permission is granted in; nil means every city.
+type scope map[uuid.UUID]struct{}
+
+func (s scope) covers(cityID uuid.UUID) bool {
+	if s == nil {
+		return true
+	}
+
+	_, ok := s[cityID]
+	return ok
+}
+
+type RBAC struct {
+	roles  map[string]map[Permission]scope
+	grants map[uuid.UUID]map[Permission]scope
+}
+
+func New(cfg config.RBACConfig) (RBAC, error) {
+	r := RBAC{
+		roles:  make(map[string]map[Permission]scope),
+		grants: make(map[uuid.UUID]map[Permission]scope),
+	}
+
+	if len(cfg.Roles) == 0 {
+		for role, perms := range defaultRoles {
+			for _, perm := range perms {
+				r.allowRole(role, perm, nil)
+			}
+		}
+	}
+
+	for role, roleCfg := range cfg.Roles {
+		if _, err := enum.ParseUserRole(role); err != nil {
+			return RBAC{}, fmt.Errorf("rbac role mapping: %w", err)
+		}
+
+		cities, err := parseCities(roleCfg.Cities)
+		if err != nil {
+			return RBAC{}, fmt.Errorf("rbac role %s: %w", role, err)
+		}
+
+		for _, p := range roleCfg.Permissions {
+			perm, err := ParsePermission(p)
+			if err != nil {
+				return RBAC{}, fmt.Errorf("rbac role %s: %w", role, err)
+			}
+			if perm == PermissionPolicyManage && cities != nil {
+				return RBAC{}, fmt.Errorf("rbac role %s: permission %s can not be scoped to cities", role, perm)
+			}
+
+			r.allowRole(role, perm, cities)
+		}
+	}
+
+	for _, grant := range cfg.Grants {
+		userID, err := uuid.Parse(grant.UserID)
+		if err != nil {
+			return RBAC{}, fmt.Errorf("rbac grant user_id '%s': %w", grant.UserID, err)
+		}
+
+		cities, err := parseCities([]string{grant.CityID})
+		if err != nil {
+			return RBAC{}, fmt.Errorf("rbac grant for user %s: %w", userID, err)
+		}
+
+		for _, p := range grant.Permissions {
+			perm, err := ParsePermission(p)
+			if err != nil {
+				return RBAC{}, fmt.Errorf("rbac grant for user %s: %w", userID, err)
+			}
+			if perm == PermissionPolicyManage && cities != nil {
+				return RBAC{}, fmt.Errorf("rbac grant for user %s: permission %s can not be scoped to a city", userID, perm)
+			}
+
+			r.allowUser(userID, perm, cities)
+		}
+	}
+
+	return r, nil
+}
+
+// Can reports whether subject holds perm in the given city.
+func (r RBAC) Can(subject Subject, perm Permission, cityID uuid.UUID) bool {
+	if s, ok := r.roles[subject.Role][perm]; ok && s.covers(cityID) {
+		return true
+	}
+
+	if s, ok := r.grants[subject.UserID][perm]; ok && s.covers(cityID) {
+		return true
+	}
+
+	return false
+}
+
+// CanAnywhere reports whether subject holds perm in at least one city.
+// It is used before the target city of a request is known.
+func (r RBAC) CanAnywhere(subject Subject, perm Permission) bool {
+	if _, ok := r.roles[subject.Role][perm]; ok {
+		return true
+	}
+
+	_, ok := r.grants[subject.UserID][perm]
+	return ok
+}
+
+// Rule is a permission held by a role or, when UserID is set, granted to a single user.
+// Empty Cities means every city.
+type Rule struct {
+	Role       string
+	UserID     uuid.UUID
+	Permission Permission
+	Cities     []uuid.UUID
+}
+
+// Rules lists the effective mapping, roles first, in a stable order.
+func (r RBAC) Rules() []Rule {
+	var res []Rule
+	for role, perms := range r.roles {
+		for perm, cities := range perms {
+			res = append(res, Rule{Role: role, Permission: perm, Cities: cities.list()})
+		}
+	}
+	for userID, perms := range r.grants {
+		for perm, cities := range perms {
+			res = append(res, Rule{UserID: userID, Permission: perm, Cities: cities.list()})
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		a, b := res[i], res[j]
+		if (a.Role == "") != (b.Role == "") {
+			return b.Role == ""
+		}
+		if a.Role != b.Role {
+			return a.Role < b.Role
+		}
+		if a.UserID != b.UserID {
+			return a.UserID.String() < b.UserID.String()
+		}
+
+		return a.Permission < b.Permission
+	})
+
+	return res
+}
+
+func (r RBAC) allowRole(role string, perm Permission, cities scope) {
+	if r.roles[role] == nil {
+		r.roles[role] = make(map[Permission]scope)
+	}
+
+	r.roles[role][perm] = mergeScopes(r.roles[role], perm, cities)
+}
+
+func (r RBAC) allowUser(userID uuid.UUID, perm Permission, cities scope) {
+	if r.grants[userID] == nil {
+		r.grants[userID] = make(map[Permission]scope)
+	}
+
+	r.grants[userID][perm] = mergeScopes(r.grants[userID], perm, cities)
+}
+
+func mergeScopes(existing map[Permission]scope, perm Permission, cities scope) scope {
+	prev, ok := existing[perm]
+	if !ok {
+		return cities
+	}
+	if prev == nil || cities == nil {
+		return nil
+	}
+
+	for cityID := range cities {
+		prev[cityID] = struct{}{}
+	}
+
+	return prev
+}
+
+func (s scope) list() []uuid.UUID {
+	res := make([]uuid.UUID, 0, len(s))
+	for cityID := range s {
+		res = append(res, cityID)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].String() < res[j].String() })
+
+	return res
+}
+
+func parseCities(ids []string) (scope, error) {
+	var cities scope
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+
+		cityID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid city_id '%s': %w", id, err)
+		}
+
+		if cities == nil {
+			cities = make(scope)
+		}
+		cities[cityID] = struct{}{}
+	}
+
+	return cities, nil
+}
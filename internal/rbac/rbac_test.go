@@ -0,0 +1,199 @@
+package rbac
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/chains-lab/city-petitions-svc/internal/config"
+	"github.com/chains-lab/city-petitions-svc/internal/constant/enum"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+)
+
+var (
+	cityA = uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+	cityB = uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+	clerk = uuid.MustParse("00000000-0000-0000-0000-0000000000c1")
+)
+
+func loadRBAC(t *testing.T, yaml string) RBAC {
+	t.Helper()
+
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(strings.NewReader(yaml)); err != nil {
+		t.Fatalf("reading config: %v", err)
+	}
+
+	var cfg config.RBACConfig
+	if err := v.Unmarshal(&cfg); err != nil {
+		t.Fatalf("unmarshalling config: %v", err)
+	}
+
+	r, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	return r
+}
+
+func TestCan(t *testing.T) {
+	configured := loadRBAC(t, `
+roles:
+  moderator:
+    permissions: ["petition.moderate"]
+    cities: ["`+cityA.String()+`"]
+  admin:
+    permissions: ["petition.moderate", "petition.answer", "petition.admin"]
+grants:
+  - user_id: "`+clerk.String()+`"
+    city_id: "`+cityB.String()+`"
+    permissions: ["petition.answer"]
+`)
+	defaults := loadRBAC(t, `{}`)
+
+	user := Subject{UserID: uuid.New(), Role: enum.UserRoleUser}
+	moderator := Subject{UserID: uuid.New(), Role: enum.UserRoleModerator}
+	admin := Subject{UserID: uuid.New(), Role: enum.UserRoleAdmin}
+	superUser := Subject{UserID: uuid.New(), Role: enum.UserRoleSuperUser}
+	granted := Subject{UserID: clerk, Role: enum.UserRoleUser}
+
+	tests := []struct {
+		name    string
+		rbac    RBAC
+		subject Subject
+		perm    Permission
+		cityID  uuid.UUID
+		want    bool
+	}{
+		{"user can not moderate", configured, user, PermissionPetitionModerate, cityA, false},
+		{"moderator moderates in scoped city", configured, moderator, PermissionPetitionModerate, cityA, true},
+		{"moderator does not moderate outside scope", configured, moderator, PermissionPetitionModerate, cityB, false},
+		{"moderator can not answer", configured, moderator, PermissionPetitionAnswer, cityA, false},
+		{"admin answers in every city", configured, admin, PermissionPetitionAnswer, cityB, true},
+		{"admin administrates", configured, admin, PermissionPetitionAdmin, cityA, true},
+		{"unmapped role has nothing", configured, superUser, PermissionPetitionModerate, cityA, false},
+		{"grant applies in its city", configured, granted, PermissionPetitionAnswer, cityB, true},
+		{"grant does not apply elsewhere", configured, granted, PermissionPetitionAnswer, cityA, false},
+		{"grant adds only its permissions", configured, granted, PermissionPetitionModerate, cityB, false},
+		{"grant is bound to the user", configured, Subject{UserID: uuid.New(), Role: enum.UserRoleUser}, PermissionPetitionAnswer, cityB, false},
+
+		{"default moderator moderates", defaults, moderator, PermissionPetitionModerate, cityB, true},
+		{"default moderator answers", defaults, moderator, PermissionPetitionAnswer, cityA, true},
+		{"default moderator can not administrate", defaults, moderator, PermissionPetitionAdmin, cityA, false},
+		{"default admin can not manage policy", defaults, admin, PermissionPolicyManage, cityA, false},
+		{"default super user manages policy", defaults, superUser, PermissionPolicyManage, cityA, true},
+		{"default admin answers", defaults, admin, PermissionPetitionAnswer, cityA, true},
+		{"default super user administrates", defaults, superUser, PermissionPetitionAdmin, cityB, true},
+		{"default user has nothing", defaults, user, PermissionPetitionModerate, cityA, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rbac.Can(tt.subject, tt.perm, tt.cityID); got != tt.want {
+				t.Errorf("Can(%s, %s, %s) = %t, want %t", tt.subject.Role, tt.perm, tt.cityID, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanAnywhere(t *testing.T) {
+	r := loadRBAC(t, `
+roles:
+  moderator:
+    permissions: ["petition.moderate"]
+    cities: ["`+cityA.String()+`"]
+grants:
+  - user_id: "`+clerk.String()+`"
+    city_id: "`+cityB.String()+`"
+    permissions: ["petition.answer"]
+`)
+
+	tests := []struct {
+		name    string
+		subject Subject
+		perm    Permission
+		want    bool
+	}{
+		{"city-scoped role counts", Subject{Role: enum.UserRoleModerator}, PermissionPetitionModerate, true},
+		{"city-scoped grant counts", Subject{UserID: clerk, Role: enum.UserRoleUser}, PermissionPetitionAnswer, true},
+		{"missing permission", Subject{Role: enum.UserRoleModerator}, PermissionPetitionAnswer, false},
+		{"plain user", Subject{UserID: uuid.New(), Role: enum.UserRoleUser}, PermissionPetitionModerate, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.CanAnywhere(tt.subject, tt.perm); got != tt.want {
+				t.Errorf("CanAnywhere(%s, %s) = %t, want %t", tt.subject.Role, tt.perm, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRejectsInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+	}{
+		{"unknown role", `{roles: {janitor: {permissions: ["petition.moderate"]}}}`},
+		{"unknown permission", `{roles: {admin: {permissions: ["petition.delete"]}}}`},
+		{"city-scoped policy.manage role", `{roles: {admin: {permissions: ["policy.manage"], cities: ["` + cityA.String() + `"]}}}`},
+		{"city-scoped policy.manage grant", `{grants: [{user_id: "` + clerk.String() + `", city_id: "` + cityA.String() + `", permissions: ["policy.manage"]}]}`},
+		{"invalid role city", `{roles: {admin: {permissions: ["petition.admin"], cities: ["nope"]}}}`},
+		{"invalid grant user", `{grants: [{user_id: "nope", permissions: ["petition.answer"]}]}`},
+		{"invalid grant city", `{grants: [{user_id: "` + clerk.String() + `", city_id: "nope", permissions: ["petition.answer"]}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := viper.New()
+			v.SetConfigType("yaml")
+			if err := v.ReadConfig(strings.NewReader(tt.yaml)); err != nil {
+				t.Fatalf("reading config: %v", err)
+			}
+
+			var cfg config.RBACConfig
+			if err := v.Unmarshal(&cfg); err != nil {
+				t.Fatalf("unmarshalling config: %v", err)
+			}
+
+			if _, err := New(cfg); err == nil {
+				t.Error("New succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestRules(t *testing.T) {
+	r := loadRBAC(t, `
+roles:
+  moderator:
+    permissions: ["petition.moderate"]
+    cities: ["`+cityB.String()+`", "`+cityA.String()+`"]
+  admin:
+    permissions: ["policy.manage"]
+grants:
+  - user_id: "`+clerk.String()+`"
+    city_id: "`+cityB.String()+`"
+    permissions: ["petition.answer"]
+`)
+
+	want := []Rule{
+		{Role: enum.UserRoleAdmin, Permission: PermissionPolicyManage, Cities: []uuid.UUID{}},
+		{Role: enum.UserRoleModerator, Permission: PermissionPetitionModerate, Cities: []uuid.UUID{cityA, cityB}},
+		{UserID: clerk, Permission: PermissionPetitionAnswer, Cities: []uuid.UUID{cityB}},
+	}
+
+	got := r.Rules()
+	if len(got) != len(want) {
+		t.Fatalf("Rules() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].Role != want[i].Role || got[i].UserID != want[i].UserID || got[i].Permission != want[i].Permission ||
+			!slices.Equal(got[i].Cities, want[i].Cities) {
+			t.Errorf("rule %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
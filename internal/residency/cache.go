@@ -0,0 +1,82 @@
+package residency
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type cacheKey struct {
+	cityID uuid.UUID
+	userID uuid.UUID
+}
+
+type cacheEntry struct {
+	resident  bool
+	expiresAt time.Time
+}
+
+// Cached remembers answers of the wrapped verifier for ttl, both positive and negative.
+// Errors are never cached. Expired entries are swept at most once per ttl, on insert, so the
+// cache holds at most the pairs looked up within the last two ttl periods.
+type Cached struct {
+	next Verifier
+	ttl  time.Duration
+
+	mu        sync.Mutex
+	entries   map[cacheKey]cacheEntry
+	nextSweep time.Time
+}
+
+func NewCached(next Verifier, ttl time.Duration) *Cached {
+	return &Cached{
+		next:    next,
+		ttl:     ttl,
+		entries: make(map[cacheKey]cacheEntry),
+	}
+}
+
+func (c *Cached) IsResident(ctx context.Context, cityID, userID uuid.UUID) (bool, error) {
+	key := cacheKey{cityID: cityID, userID: userID}
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok && now.After(entry.expiresAt) {
+		delete(c.entries, key)
+		ok = false
+	}
+	c.mu.Unlock()
+
+	if ok {
+		return entry.resident, nil
+	}
+
+	resident, err := c.next.IsResident(ctx, cityID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	c.sweep(now)
+	c.entries[key] = cacheEntry{resident: resident, expiresAt: now.Add(c.ttl)}
+	c.mu.Unlock()
+
+	return resident, nil
+}
+
+// sweep drops expired entries once the sweep is due. c.mu must be held.
+func (c *Cached) sweep(now time.Time) {
+	if now.Before(c.nextSweep) {
+		return
+	}
+
+	for key, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+	c.nextSweep = now.Add(c.ttl)
+}
@@ -0,0 +1,64 @@
+package residency
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const defaultClientTimeout = 5 * time.Second
+
+// Client asks the city service whether a user is a resident of a city.
+type Client struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+}
+
+func NewClient(baseURL, apiKey string, timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = defaultClientTimeout
+	}
+
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		http:    &http.Client{Timeout: timeout},
+	}
+}
+
+// IsResident calls GET {base}/cities/{city_id}/residents/{user_id};
+// 200 means the user is a resident, 404 means they are not.
+func (c *Client) IsResident(ctx context.Context, cityID, userID uuid.UUID) (bool, error) {
+	endpoint := fmt.Sprintf("%s/cities/%s/residents/%s",
+		c.baseURL,
+		url.PathEscape(cityID.String()),
+		url.PathEscape(userID.String()),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return false, fmt.Errorf("building residency request: %w", err)
+	}
+	req.Header.Set("X-Api-Key", c.apiKey)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("requesting residency for user %s in city %s: %w", userID, cityID, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected residency response status %d for user %s in city %s", resp.StatusCode, userID, cityID)
+	}
+}
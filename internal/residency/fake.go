@@ -0,0 +1,45 @@
+package residency
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Fake is an in-process verifier for local runs and tests.
+// With allowAll every user is treated as a resident of every city,
+// otherwise only pairs registered through AddResident are.
+type Fake struct {
+	allowAll bool
+
+	mu        sync.RWMutex
+	residents map[cacheKey]struct{}
+}
+
+func NewFake(allowAll bool) *Fake {
+	return &Fake{
+		allowAll:  allowAll,
+		residents: make(map[cacheKey]struct{}),
+	}
+}
+
+func (f *Fake) AddResident(cityID, userID uuid.UUID) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.residents[cacheKey{cityID: cityID, userID: userID}] = struct{}{}
+}
+
+func (f *Fake) IsResident(_ context.Context, cityID, userID uuid.UUID) (bool, error) {
+	if f.allowAll {
+		return true, nil
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	_, ok := f.residents[cacheKey{cityID: cityID, userID: userID}]
+
+	return ok, nil
+}
@@ -0,0 +1,39 @@
+package residency
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chains-lab/city-petitions-svc/internal/config"
+	"github.com/google/uuid"
+)
+
+// Verifier answers whether a user is registered as a resident of a city.
+type Verifier interface {
+	IsResident(ctx context.Context, cityID, userID uuid.UUID) (bool, error)
+}
+
+const (
+	ModeHTTP = "http"
+	ModeFake = "fake"
+)
+
+func NewVerifier(cfg config.Config) (Verifier, error) {
+	residence := cfg.Properties.Residence
+
+	var verifier Verifier
+	switch residence.Mode {
+	case ModeHTTP, "":
+		verifier = NewClient(residence.URL, residence.APIKey, residence.Timeout)
+	case ModeFake:
+		return NewFake(true), nil
+	default:
+		return nil, fmt.Errorf("unknown residency verifier mode '%s'", residence.Mode)
+	}
+
+	if residence.CacheTTL > 0 {
+		verifier = NewCached(verifier, residence.CacheTTL)
+	}
+
+	return verifier, nil
+}
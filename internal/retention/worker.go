@@ -0,0 +1,80 @@
+package retention
+
+import (
+	"context"
+	"time"
+
+	"github.com/chains-lab/city-petitions-svc/internal/app/models"
+	"github.com/chains-lab/city-petitions-svc/internal/config"
+	"github.com/chains-lab/city-petitions-svc/internal/logger"
+)
+
+const defaultInterval = 24 * time.Hour
+
+type applier interface {
+	ApplyRetention(ctx context.Context, dryRun bool) (models.RetentionReport, error)
+}
+
+// Worker periodically pseudonymizes signers of petitions closed longer than their retention period.
+type Worker struct {
+	app      applier
+	interval time.Duration
+	dryRun   bool
+}
+
+func New(cfg config.Config, app applier) *Worker {
+	interval := cfg.Petitions.Retention.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	return &Worker{
+		app:      app,
+		interval: interval,
+		dryRun:   cfg.Petitions.Retention.DryRun,
+	}
+}
+
+// Run applies retention right away and then every interval until ctx is done. A failed run is
+// logged and retried on the next tick.
+func (w *Worker) Run(ctx context.Context, log logger.Logger) error {
+	log.Infof("signature retention runs every %s (dry run %t)", w.interval, w.dryRun)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.RunOnce(ctx, log, w.dryRun); err != nil {
+			log.WithError(err).Error("signature retention run failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce applies retention a single time and logs the report.
+func (w *Worker) RunOnce(ctx context.Context, log logger.Logger, dryRun bool) (models.RetentionReport, error) {
+	report, err := w.app.ApplyRetention(ctx, dryRun)
+	if err != nil {
+		return report, err
+	}
+
+	verb := "pseudonymized"
+	if report.DryRun {
+		verb = "would pseudonymize"
+	}
+
+	for _, p := range report.Petitions {
+		log.WithField("petition_id", p.PetitionID).
+			WithField("city_id", p.CityID).
+			Infof("retention %s %d signatures, petition closed at %s, period %s",
+				verb, p.Signatures, p.ClosedAt.Format(time.RFC3339), p.Period)
+	}
+	log.Infof("retention %s %d signatures of %d petitions", verb, report.Signatures, len(report.Petitions))
+
+	return report, nil
+}